@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,18 +20,19 @@ import (
 	faucetClient "github.com/gnolang/faucet/client/http"
 	rpcClient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/memo"
 
 	_ "github.com/joho/godotenv/autoload"
-
-	_ "embed"
 )
 
-//go:embed template.txt
-var template string // register contract template
-
 // Errors
 var (
 	errNoFundedAccount = errors.New("no funded account found")
+
+	// ErrTokenAlreadyRegistered is returned when the token
+	// is already registered on the target realm
+	ErrTokenAlreadyRegistered = errors.New("token already registered")
 )
 
 var logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
@@ -43,110 +46,232 @@ type AddPkg struct {
 	rpcClient      rpcClient.RPCClient // the rpc client
 	keyring        keyring.Keyring     // the faucet keyring
 	prepareTxMsgFn PrepareTxMessageFn  // transaction message creator
+	prepareCallFn  PrepareTxMessageFn  // call transaction message creator
+	chainID        string              // the chain ID of the remote chain
+	targets        []*Target           // the registration targets
+	version        string              // the active registry version
+	packages       PackageStorage      // the saved chain packages, for type-checking
+	memoTag        string              // the tag of the registration tx memos
+	auditLog       AuditLog            // the signed tx audit log, if any
+}
+
+// Receipt is the receipt of a broadcasted registration tx
+type Receipt struct {
+	TxHash string   // the base64 encoded tx hash
+	Fee    std.Coin // the fee paid for the tx
+	Height int64    // the height the tx was committed in
 }
 
-// RegisterGrc20Token registers news grc20 token to pre-defined register contract
-func RegisterGrc20Token(pkgPath string) error {
+// New creates a new AddPkg instance, configured from the environment
+func New(opts ...Option) (*AddPkg, error) {
 	gnoRpcUrl := getEnv("GNO_RPC_URL", "http://localhost:26657")
 	client, err := client.NewClient(gnoRpcUrl)
 	if err != nil {
 		logger.Error("unable to create TM2 client", "error", err)
-		return err
+		return nil, err
 	}
 
 	rClient, err := rpcClient.NewHTTPClient(gnoRpcUrl)
 	if err != nil {
 		logger.Error("unable to create rpc client", "error", err)
-		return err
+		return nil, err
 	}
 
-	registered, err := checkIfTokenRegistered(rClient, pkgPath)
+	// Create a new AddPkg instance
+	estimator, err := NewEstimator()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if registered {
-		return fmt.Errorf("token already registered: %s", pkgPath)
+
+	// faucet client
+	fClient, err := faucetClient.NewClient(gnoRpcUrl)
+	if err != nil {
+		logger.Error("unable to create faucet client", "error", err)
+		return nil, err
 	}
 
+	registerMnemonic := getEnv("GNO_REGISTER_MNEMONIC", "")
+	a := &AddPkg{
+		estimator:      estimator,
+		logger:         logger,
+		client:         *client,
+		faucetClient:   *fClient,
+		rpcClient:      *rClient,
+		keyring:        memory.New(registerMnemonic, 1),
+		prepareTxMsgFn: defaultPrepareTxMessage,
+		prepareCallFn:  defaultPrepareCallMessage,
+		chainID:        getEnv("GNO_CHAIN_ID", "dev"),
+		targets:        []*Target{DefaultTarget()},
+		memoTag:        memo.DefaultTag,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a, nil
+}
+
+// NewEstimator creates the static gas estimator, configured from the environment
+func NewEstimator() (estimate.Estimator, error) {
 	// load envs
 	gasFeeDenom := getEnv("GNO_GAS_FEE_DENOM", "ugnot")
 	gasFeeAmountStr := getEnv("GNO_GAS_FEE_AMOUNT", "1000000")
 	gasFeeAmount, err := strconv.ParseInt(gasFeeAmountStr, 10, 64)
 	if err != nil {
 		logger.Error("error parsing gas fee amount", "error", err.Error())
-		return err
+		return nil, err
 	}
 	gasFeeWantedStr := getEnv("GNO_GAS_WANTED", "100000000") // current max block gas after bump PR, https://github.com/gnolang/gno/pull/2065
 	gasFeeWanted, err := strconv.ParseInt(gasFeeWantedStr, 10, 64)
 	if err != nil {
 		logger.Error("error parsing gas fee wanted", "error", err.Error())
-		return err
+		return nil, err
 	}
 
-	// Create a new AddPkg instance
-	estimator := static.New(
+	return static.New(
 		std.NewCoin(gasFeeDenom, gasFeeAmount),
 		gasFeeWanted,
-	)
+	), nil
+}
 
-	// faucet client
-	fClient, err := faucetClient.NewClient(gnoRpcUrl)
-	if err != nil {
-		logger.Error("unable to create faucet client", "error", err)
-		return err
+// Render renders the register packages of the token of the given
+// kind, for every target of the active registry version. The key is set
+// for the tokens kept by key in factory realms, with the realm as the package
+// path. Only the targets with the given names are rendered, if any names are given
+func (a *AddPkg) Render(kind ledger.Kind, pkgPath, key string, names ...string) []*RenderedPackage {
+	return renderTargets(namedTargets(activeTargets(a.targets, a.version, kind), names), pkgPath, key)
+}
+
+// SetVersion sets the active registry version,
+// the tokens are registered to from then on
+func (a *AddPkg) SetVersion(version string) error {
+	if _, ok := Versions(a.targets)[version]; !ok && version != "" {
+		return fmt.Errorf("%w, %q", errUnknownVersion, version)
 	}
 
-	registerMnemonic := getEnv("GNO_REGISTER_MNEMONIC", "")
-	a := &AddPkg{
-		estimator:      estimator,
-		logger:         logger,
-		client:         *client,
-		faucetClient:   *fClient,
-		rpcClient:      *rClient,
-		keyring:        memory.New(registerMnemonic, 1),
-		prepareTxMsgFn: defaultPrepareTxMessage,
+	a.version = version
+
+	return nil
+}
+
+// EstimateFee returns the fee paid for a single registration tx
+func (a *AddPkg) EstimateFee() std.Coin {
+	return a.estimator.EstimateGasFee()
+}
+
+// RegisterGrc20Token registers news grc20 token to pre-defined register contract.
+// The receipt is returned for every tx that was committed, even if it failed
+// during execution, since the fee was paid either way
+func (a *AddPkg) RegisterGrc20Token(pkgPath string) (*Receipt, error) {
+	registered, err := checkIfTokenRegistered(&a.rpcClient, a.registryPath(), pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	if registered {
+		return nil, fmt.Errorf("%w: %s", ErrTokenAlreadyRegistered, pkgPath)
 	}
 
 	// Register the GRC20 token
-	gnoChainId := getEnv("GNO_CHAIN_ID", "dev")
-	return a.registerGrc20Token(pkgPath, gnoChainId) // #87 func
+	return a.register(ledger.KindGRC20, pkgPath) // #87 func
+}
+
+// IsRegistered checks if the grc20 token is registered
+// on the registry realm of the active version
+func (a *AddPkg) IsRegistered(pkgPath string) (bool, error) {
+	return checkIfTokenRegistered(&a.rpcClient, a.registryPath(), pkgPath)
+}
+
+// RegisterGrc20Tokens registers the grc20 tokens in a single tx, with the
+// register packages of every token. The batch fails as a whole if any
+// of the tokens is already registered, or if any of its packages is broken
+func (a *AddPkg) RegisterGrc20Tokens(pkgPaths []string) (*Receipt, error) {
+	for _, pkgPath := range pkgPaths {
+		registered, err := checkIfTokenRegistered(&a.rpcClient, a.registryPath(), pkgPath)
+		if err != nil {
+			return nil, err
+		}
+		if registered {
+			return nil, fmt.Errorf("%w: %s", ErrTokenAlreadyRegistered, pkgPath)
+		}
+	}
+
+	return a.register(ledger.KindGRC20, pkgPaths...)
+}
+
+// RegisterGrc721Collection registers the grc721 collection to the grc721
+// targets. The registries are not queried beforehand, since the
+// registered check is specific to the grc20 registry
+func (a *AddPkg) RegisterGrc721Collection(pkgPath string) (*Receipt, error) {
+	return a.register(ledger.KindGRC721, pkgPath)
 }
 
-func (a *AddPkg) registerGrc20Token(pkgPath, gnoChainId string) error {
+// register registers the tokens of the given kind in a single tx,
+// with the register packages of every matching target
+func (a *AddPkg) register(kind ledger.Kind, pkgPaths ...string) (*Receipt, error) {
+	tokens := make([]*ledger.Token, 0, len(pkgPaths))
+	for _, pkgPath := range pkgPaths {
+		tokens = append(tokens, &ledger.Token{PkgPath: pkgPath})
+	}
+
+	signed, err := a.Prepare(kind, tokens...)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Broadcast(signed)
+}
+
+// prepare prepares and signs the registration tx of the tokens of the given kind,
+// with the register packages of every matching target, and simulates it
+func (a *AddPkg) prepare(kind ledger.Kind, txMemo string, tokens ...*ledger.Token) (*std.Tx, std.Account, error) {
 	// Find an account that has balance to cover tx fee
 	fundAccount, err := a.findFundedAccount()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	// Prepare the transaction
-	registerCode := strings.ReplaceAll(template, "pkgPath", pkgPath)
+	// Type-check the register packages, so template
+	// errors are caught before any fee is paid
+	packages := make([]*RenderedPackage, 0, len(tokens)*len(a.targets))
+	for _, token := range tokens {
+		// The keyed tokens are registered with their realm, and their key
+		pkgPath := strings.TrimSuffix(token.PkgPath, "."+token.Key)
 
-	_removeCommon := strings.Replace(pkgPath, "gno.land/r/", "", 1)
-	pathToRegister := "gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/" + _removeCommon
+		rendered := a.Render(kind, pkgPath, token.Key, token.Targets...)
+		if len(rendered) == 0 && token.Key != "" {
+			return nil, nil, fmt.Errorf("%w for the keyed token %s, with a key argument", errNoTargets, token.PkgPath)
+		}
 
-	/*
-		orig:					gno.land/r/gnoswap/gns
-		remove:				gnoswap/gns
-		toRegister: 	gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/gnoswap/gns
-	*/
+		packages = append(packages, rendered...)
+	}
 
-	pCfg := PrepareCfg{
-		Creator: fundAccount.GetAddress(),
-		PkgName: "token_register",
-		PkgPath: pathToRegister,
-		Files: []*std.MemFile{
-			{
-				Name: "register.gno",
-				Body: registerCode,
-			},
-		},
+	if len(packages) == 0 {
+		return nil, nil, fmt.Errorf("%w for %s tokens", errNoTargets, kind)
 	}
-	tx := prepareTransaction(a.estimator, a.prepareTxMsgFn(pCfg))
+
+	if err := typeCheckPackages(a.packageGetter(), packages); err != nil {
+		var templateErr *TemplateError
+
+		// Inconclusive type-checks are left to the simulation,
+		// since the imported packages might not be indexed yet
+		if !errors.As(err, &templateErr) || !templateErr.Inconclusive() {
+			return nil, nil, err
+		}
+
+		a.logger.Warn("unable to type-check register package", "error", err)
+	}
+
+	// Prepare the transaction, with a message per token and target
+	tx := prepareTransaction(
+		a.estimator,
+		txMemo,
+		prepareMessages(a.prepareTxMsgFn, a.prepareCallFn, fundAccount.GetAddress(), packages)...,
+	)
 
 	// Sign the transaction
 	sCfg := signCfg{
-		chainID:       gnoChainId,
+		chainID:       a.chainID,
 		accountNumber: fundAccount.GetAccountNumber(),
 		sequence:      fundAccount.GetSequence(),
 	}
@@ -156,12 +281,47 @@ func (a *AddPkg) registerGrc20Token(pkgPath, gnoChainId string) error {
 		a.keyring.GetKey(fundAccount.GetAddress()),
 		sCfg,
 	); err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	// Simulate the transaction, so broken registrations
+	// are caught before any fee is paid
+	if err := simulateTransaction(&a.rpcClient, tx); err != nil {
+		return nil, nil, err
 	}
 
-	// Broadcast the transaction
-	_, err = a.faucetClient.SendTransactionCommit(tx)
-	return err
+	return tx, fundAccount, nil
+}
+
+// SignerBalance returns the balance of the best funded signer account,
+// in the fee denom, since it is the account the next tx is signed with
+func (a *AddPkg) SignerBalance() (*ledger.Balance, error) {
+	denom := a.estimator.EstimateGasFee().Denom
+
+	var signer *ledger.Balance
+
+	for _, address := range a.keyring.GetAddresses() {
+		account, err := a.faucetClient.GetAccount(address)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch account %s, %w", address, err)
+		}
+
+		amount := account.GetCoins().AmountOf(denom)
+
+		if signer == nil || amount > signer.Amount {
+			signer = &ledger.Balance{
+				Signer: address.String(),
+				Denom:  denom,
+				Amount: amount,
+			}
+		}
+	}
+
+	if signer == nil {
+		return nil, errNoFundedAccount
+	}
+
+	return signer, nil
 }
 
 // findFundedAccount finds an account
@@ -223,8 +383,17 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func checkIfTokenRegistered(client *rpcClient.RPCClient, pkgPath string) (bool, error) {
-	poolContract := getEnv("POOL_CONTRACT_PATH", "gno.land/r/gnoswap/pool")
+// registryPath returns the registry realm queried for the registered
+// tokens, the registry of the active version if configured
+func (a *AddPkg) registryPath() string {
+	if registry := Versions(a.targets)[a.version]; registry != "" {
+		return registry
+	}
+
+	return getEnv("POOL_CONTRACT_PATH", "gno.land/r/gnoswap/pool")
+}
+
+func checkIfTokenRegistered(client abciClient, poolContract, pkgPath string) (bool, error) {
 	payload := fmt.Sprintf("%s.GetRegisteredTokens()", poolContract)
 
 	res, err := client.ABCIQuery("vm/qeval", []byte(payload))
@@ -232,13 +401,38 @@ func checkIfTokenRegistered(client *rpcClient.RPCClient, pkgPath string) (bool,
 		logger.Error("unable to fetch registered tokens", "error", err)
 		return false, err
 	}
-	tokens := string(res.Response.Data)
 
-	registered := strings.Contains(tokens, pkgPath)
-	if registered {
+	if res.Response.Error != nil {
+		logger.Error("unable to fetch registered tokens", "error", res.Response.Error)
+		return false, res.Response.Error
+	}
+
+	// The token paths are matched exactly, so a token
+	// is not mistaken for another one with the same prefix
+	if slices.Contains(parseQEvalStrings(res.Response.Data), pkgPath) {
 		logger.Info("token already registered", "pkgPath", pkgPath)
 		return true, nil
 	}
 
 	return false, nil
 }
+
+// qevalString matches the (quoted) string values of the qeval result,
+// ex. (slice[("gno.land/r/demo/foo" string),("gno.land/r/demo/bar" string)] []string)
+var qevalString = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// parseQEvalStrings parses the string values of the qeval result
+func parseQEvalStrings(data []byte) []string {
+	values := make([]string, 0)
+
+	for _, quoted := range qevalString.FindAllString(string(data), -1) {
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
@@ -0,0 +1,94 @@
+package addpkg
+
+import (
+	"errors"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	coreTypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIfTokenRegistered(t *testing.T) {
+	t.Parallel()
+
+	const registered = `(slice[("gno.land/r/demo/bar" string),("gno.land/r/demo/factory.foo" string)] []string)`
+
+	testTable := []struct {
+		name       string
+		pkgPath    string
+		registered bool
+	}{
+		{
+			"registered token",
+			"gno.land/r/demo/bar",
+			true,
+		},
+		{
+			"registered keyed token",
+			"gno.land/r/demo/factory.foo",
+			true,
+		},
+		{
+			"token with a registered prefix",
+			"gno.land/r/demo/ba",
+			false,
+		},
+		{
+			"token prefixed by a registered token",
+			"gno.land/r/demo/bar2",
+			false,
+		},
+		{
+			"unregistered token",
+			"gno.land/r/demo/baz",
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockABCIClient{
+				abciQueryFn: func(path string, data []byte) (*coreTypes.ResultABCIQuery, error) {
+					require.Equal(t, "vm/qeval", path)
+					require.Equal(t, "gno.land/r/gnoswap/pool.GetRegisteredTokens()", string(data))
+
+					return &coreTypes.ResultABCIQuery{
+						Response: abci.ResponseQuery{ResponseBase: abci.ResponseBase{Data: []byte(registered)}},
+					}, nil
+				},
+			}
+
+			ok, err := checkIfTokenRegistered(client, "gno.land/r/gnoswap/pool", testCase.pkgPath)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.registered, ok)
+		})
+	}
+}
+
+func TestCheckIfTokenRegistered_QueryError(t *testing.T) {
+	t.Parallel()
+
+	queryErr := errors.New("unable to query")
+
+	client := &mockABCIClient{
+		abciQueryFn: func(string, []byte) (*coreTypes.ResultABCIQuery, error) {
+			return &coreTypes.ResultABCIQuery{
+				Response: abci.ResponseQuery{
+					ResponseBase: abci.ResponseBase{Error: abci.StringError(queryErr.Error())},
+				},
+			}, nil
+		},
+	}
+
+	ok, err := checkIfTokenRegistered(client, "gno.land/r/gnoswap/pool", "gno.land/r/demo/bar")
+
+	assert.False(t, ok)
+	assert.ErrorContains(t, err, queryErr.Error())
+}
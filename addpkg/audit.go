@@ -0,0 +1,50 @@
+package addpkg
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// AuditLog is the append-only record of every signed tx
+type AuditLog interface {
+	// Append appends the signed tx entry to the audit log
+	Append(entry *audit.Entry) error
+}
+
+// record records the signed registration tx to the audit log, if any.
+// The tx is never broadcast if it was unable to be recorded
+func (a *AddPkg) record(
+	tx *std.Tx,
+	txHash string,
+	signer std.Account,
+	kind ledger.Kind,
+	tokens []*ledger.Token,
+) error {
+	if a.auditLog == nil {
+		return nil
+	}
+
+	triggers := make([]*audit.Token, 0, len(tokens))
+
+	for _, token := range tokens {
+		triggers = append(triggers, &audit.Token{
+			PkgPath:      token.PkgPath,
+			DeployTxHash: token.DeployTxHash,
+		})
+	}
+
+	entry, err := audit.NewEntry(tx, txHash, signer, fmt.Sprintf("%s registration", kind), triggers)
+	if err != nil {
+		return fmt.Errorf("unable to audit transaction, %w", err)
+	}
+
+	if err := a.auditLog.Append(entry); err != nil {
+		return fmt.Errorf("unable to audit transaction, %w", err)
+	}
+
+	return nil
+}
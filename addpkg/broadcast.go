@@ -0,0 +1,140 @@
+package addpkg
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/memo"
+)
+
+var (
+	// ErrBroadcastUncertain is returned when the outcome of the tx broadcast
+	// is unknown, ex. on a timeout. The tx might still be committed
+	ErrBroadcastUncertain = errors.New("transaction broadcast outcome unknown")
+
+	// ErrTxNotFound is returned when the tx is not committed on chain
+	ErrTxNotFound = errors.New("transaction not found")
+)
+
+// SignedTx is a signed registration tx, prepared ahead of its
+// broadcast so its hash is known before it is sent. Broadcasting
+// the same signed tx again reuses its sequence, so it is never paid twice
+type SignedTx struct {
+	Hash string   // the base64 encoded tx hash
+	Raw  []byte   // the amino encoded signed tx
+	Fee  std.Coin // the tx fee
+}
+
+// Prepare type-checks, signs and simulates the registration tx of the
+// tokens of the given kind, without broadcasting it. The tx memo links
+// the registration to the tokens, by their IDs and source deploy txs, and
+// to the registry version. The signed tx is recorded to the audit log,
+// if any, before it is returned
+func (a *AddPkg) Prepare(kind ledger.Kind, tokens ...*ledger.Token) (*SignedTx, error) {
+	links := make([]memo.Link, 0, len(tokens))
+
+	for _, token := range tokens {
+		links = append(links, memo.Link{
+			TokenID:      token.PkgPath,
+			DeployTxHash: token.DeployTxHash,
+		})
+	}
+
+	tx, signer, err := a.prepare(kind, memo.Format(a.memoTag, a.version, links...), tokens...)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := amino.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	signed := &SignedTx{
+		Hash: base64.StdEncoding.EncodeToString(types.Tx(raw).Hash()),
+		Raw:  raw,
+		Fee:  tx.Fee.GasFee,
+	}
+
+	if err := a.record(tx, signed.Hash, signer, kind, tokens); err != nil {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+// Broadcast broadcasts the signed registration tx, and waits for it to be
+// committed. ErrBroadcastUncertain is returned if the outcome is unknown
+func (a *AddPkg) Broadcast(signed *SignedTx) (*Receipt, error) {
+	var tx std.Tx
+
+	if err := amino.Unmarshal(signed.Raw, &tx); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal transaction, %w", err)
+	}
+
+	response, err := a.faucetClient.SendTransactionCommit(&tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w, unable to send transaction, %w", ErrBroadcastUncertain, err)
+	}
+
+	// Check the errors
+	if response.CheckTx.IsErr() {
+		return nil, fmt.Errorf("transaction failed initial validation, %w", response.CheckTx.Error)
+	}
+
+	receipt := &Receipt{
+		TxHash: signed.Hash,
+		Fee:    signed.Fee,
+		Height: response.Height,
+	}
+
+	if response.DeliverTx.IsErr() {
+		return receipt, fmt.Errorf("transaction failed during execution, %w", response.DeliverTx.Error)
+	}
+
+	return receipt, nil
+}
+
+// LookupTx looks up the committed tx by its hash, on the node /tx endpoint.
+// ErrTxNotFound is returned if the tx is not committed
+func (a *AddPkg) LookupTx(txHash string) (*Receipt, error) {
+	hash, err := base64.StdEncoding.DecodeString(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode tx hash, %w", err)
+	}
+
+	result, err := a.rpcClient.Tx(hash)
+	if err != nil {
+		// The node doesn't distinguish the missing txs from the other errors,
+		// which is safe, since the signed tx is only ever broadcast again
+		return nil, fmt.Errorf("%w, %w", ErrTxNotFound, err)
+	}
+
+	if result == nil {
+		return nil, ErrTxNotFound
+	}
+
+	var tx std.Tx
+
+	if err := amino.Unmarshal(result.Tx, &tx); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal transaction, %w", err)
+	}
+
+	receipt := &Receipt{
+		TxHash: txHash,
+		Fee:    tx.Fee.GasFee,
+		Height: result.Height,
+	}
+
+	if result.TxResult.IsErr() {
+		return receipt, fmt.Errorf("transaction failed during execution, %w", result.TxResult.Error)
+	}
+
+	return receipt, nil
+}
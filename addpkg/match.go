@@ -0,0 +1,58 @@
+package addpkg
+
+import (
+	"slices"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Registers checks if the msg is a registration of the given token, to one
+// of the targets of the registry version: the deployment of its register
+// package, or the register function call of the registry realm
+func (a *AddPkg) Registers(version, tokenID string, msg std.Msg) bool {
+	return registers(a.targets, version, tokenID, msg)
+}
+
+// registers checks if the msg matches a registration of the
+// token, rendered for any of the targets of the registry version
+func registers(targets []*Target, version, tokenID string, msg std.Msg) bool {
+	if tokenID == "" {
+		return false
+	}
+
+	pkgPath, key := splitTokenID(tokenID)
+
+	for _, target := range targets {
+		if target.Version != "" && target.Version != version {
+			continue
+		}
+
+		if target.keyed() != (key != "") {
+			continue
+		}
+
+		if matches(target.Render(pkgPath, key), msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks if the msg is the rendered registration
+func matches(pkg *RenderedPackage, msg std.Msg) bool {
+	switch msg := msg.(type) {
+	case vm.MsgAddPackage:
+		return pkg.Mode == ModeAddPkg &&
+			msg.Package != nil &&
+			msg.Package.Path == pkg.PkgPath
+	case vm.MsgCall:
+		return pkg.Mode == ModeCall &&
+			msg.PkgPath == pkg.PkgPath &&
+			msg.Func == pkg.Func &&
+			slices.Equal(msg.Args, pkg.Args)
+	default:
+		return false
+	}
+}
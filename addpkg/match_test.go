@@ -0,0 +1,191 @@
+package addpkg
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddPkg_Registers(t *testing.T) {
+	t.Parallel()
+
+	a := &AddPkg{
+		targets: []*Target{
+			DefaultTarget(),
+			{
+				Name:  "registry",
+				Mode:  ModeCall,
+				Realm: "gno.land/r/demo/registry",
+				Func:  "Register",
+				Args:  []string{"pkgPath"},
+			},
+			{
+				Name:  "factory_registry",
+				Mode:  ModeCall,
+				Realm: "gno.land/r/demo/registry",
+				Func:  "RegisterKeyed",
+				Args:  []string{"pkgPath", "tokenKey"},
+			},
+			{
+				Name:    "registry_v2",
+				Mode:    ModeCall,
+				Realm:   "gno.land/r/demo/v2/registry",
+				Func:    "Register",
+				Args:    []string{"pkgPath"},
+				Version: "v2",
+			},
+		},
+	}
+
+	testTable := []struct {
+		name      string
+		version   string
+		tokenID   string
+		msg       std.Msg
+		registers bool
+	}{
+		{
+			"register package",
+			"",
+			"gno.land/r/demo/foo",
+			vm.MsgAddPackage{
+				Package: &std.MemPackage{
+					Path: "gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/demo/foo",
+				},
+			},
+			true,
+		},
+		{
+			"register package of a keyed token",
+			"",
+			"gno.land/r/demo/factory.bar",
+			vm.MsgAddPackage{
+				Package: &std.MemPackage{
+					Path: "gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/demo/factory.bar",
+				},
+			},
+			false,
+		},
+		{
+			"keyed registry call",
+			"",
+			"gno.land/r/demo/factory.bar",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/registry",
+				Func:    "RegisterKeyed",
+				Args:    []string{"gno.land/r/demo/factory", "bar"},
+			},
+			true,
+		},
+		{
+			"keyed registry call of another token",
+			"",
+			"gno.land/r/demo/factory.bar",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/registry",
+				Func:    "RegisterKeyed",
+				Args:    []string{"gno.land/r/demo/factory", "baz"},
+			},
+			false,
+		},
+		{
+			"registry call of the keyed token realm",
+			"",
+			"gno.land/r/demo/factory.bar",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/registry",
+				Func:    "Register",
+				Args:    []string{"gno.land/r/demo/factory"},
+			},
+			false,
+		},
+		{
+			"unrelated package",
+			"",
+			"gno.land/r/demo/foo",
+			vm.MsgAddPackage{
+				Package: &std.MemPackage{
+					Path: "gno.land/r/demo/foo",
+				},
+			},
+			false,
+		},
+		{
+			"registry call",
+			"",
+			"gno.land/r/demo/foo",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/registry",
+				Func:    "Register",
+				Args:    []string{"gno.land/r/demo/foo"},
+			},
+			true,
+		},
+		{
+			"registry call of another token",
+			"",
+			"gno.land/r/demo/foo",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/registry",
+				Func:    "Register",
+				Args:    []string{"gno.land/r/demo/bar"},
+			},
+			false,
+		},
+		{
+			"unrelated call",
+			"",
+			"gno.land/r/demo/foo",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/foo",
+				Func:    "Transfer",
+			},
+			false,
+		},
+		{
+			"empty token ID",
+			"",
+			"",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/registry",
+				Func:    "Register",
+				Args:    []string{""},
+			},
+			false,
+		},
+		{
+			"versioned registry call",
+			"v2",
+			"gno.land/r/demo/foo",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/v2/registry",
+				Func:    "Register",
+				Args:    []string{"gno.land/r/demo/foo"},
+			},
+			true,
+		},
+		{
+			"registry call of another version",
+			"v1",
+			"gno.land/r/demo/foo",
+			vm.MsgCall{
+				PkgPath: "gno.land/r/demo/v2/registry",
+				Func:    "Register",
+				Args:    []string{"gno.land/r/demo/foo"},
+			},
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.registers, a.Registers(testCase.version, testCase.tokenID, testCase.msg))
+		})
+	}
+}
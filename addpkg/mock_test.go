@@ -309,3 +309,17 @@ func (m *mockKeyring) GetKey(address crypto.Address) crypto.PrivKey {
 
 	return nil
 }
+
+type abciQueryDelegate func(string, []byte) (*coreTypes.ResultABCIQuery, error)
+
+type mockABCIClient struct {
+	abciQueryFn abciQueryDelegate
+}
+
+func (m *mockABCIClient) ABCIQuery(path string, data []byte) (*coreTypes.ResultABCIQuery, error) {
+	if m.abciQueryFn != nil {
+		return m.abciQueryFn(path, data)
+	}
+
+	return nil, nil
+}
@@ -20,3 +20,41 @@ func WithPrepareTxMessageFn(prepareTxMsgFn PrepareTxMessageFn) Option {
 		f.prepareTxMsgFn = prepareTxMsgFn
 	}
 }
+
+// WithPrepareCallMessageFn specifies the register
+// function call transaction message constructor
+func WithPrepareCallMessageFn(prepareCallFn PrepareTxMessageFn) Option {
+	return func(f *AddPkg) {
+		f.prepareCallFn = prepareCallFn
+	}
+}
+
+// WithTargets specifies the registration targets
+func WithTargets(targets []*Target) Option {
+	return func(f *AddPkg) {
+		f.targets = targets
+	}
+}
+
+// WithPackageStorage specifies the saved chain packages,
+// used to resolve the imports when type-checking the register packages
+func WithPackageStorage(packages PackageStorage) Option {
+	return func(f *AddPkg) {
+		f.packages = packages
+	}
+}
+
+// WithMemoTag specifies the tag of the registration tx memos
+func WithMemoTag(tag string) Option {
+	return func(f *AddPkg) {
+		f.memoTag = tag
+	}
+}
+
+// WithAuditLog specifies the audit log,
+// every signed registration tx is recorded to
+func WithAuditLog(log AuditLog) Option {
+	return func(f *AddPkg) {
+		f.auditLog = log
+	}
+}
@@ -0,0 +1,58 @@
+package addpkg
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// PlaceholderPkgPath is the package path of the placeholder token,
+// the register packages are type-checked with before any token is registered
+const PlaceholderPkgPath = "gno.land/r/grc20_register/placeholder"
+
+// placeholdersFS holds the placeholder token packages, with
+// the standard token API of each kind
+//
+//go:embed placeholders/*.gno
+var placeholdersFS embed.FS
+
+// placeholderPackage returns the placeholder token package of the kind
+func placeholderPackage(kind ledger.Kind) (*std.MemPackage, error) {
+	if kind == "" {
+		kind = ledger.KindGRC20
+	}
+
+	body, err := placeholdersFS.ReadFile(fmt.Sprintf("placeholders/%s.gno", kind))
+	if err != nil {
+		return nil, fmt.Errorf("no placeholder token for kind %q, %w", kind, err)
+	}
+
+	return &std.MemPackage{
+		Name: "placeholder",
+		Path: PlaceholderPkgPath,
+		Files: []*std.MemFile{
+			{
+				Name: "placeholder.gno",
+				Body: string(body),
+			},
+		},
+	}, nil
+}
+
+// TypeCheckPlaceholder parses and type-checks the register packages
+// rendered for the placeholder token of the given kind, so broken
+// templates are caught before the first token is registered
+func (a *AddPkg) TypeCheckPlaceholder(kind ledger.Kind) error {
+	placeholder, err := placeholderPackage(kind)
+	if err != nil {
+		return err
+	}
+
+	getter := a.packageGetter()
+	getter.placeholder = placeholder
+
+	return typeCheckPackages(getter, a.Render(kind, PlaceholderPkgPath, ""))
+}
@@ -0,0 +1,126 @@
+package addpkg
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestPlaceholderPackage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown kind", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := placeholderPackage("grc1155")
+
+		assert.ErrorContains(t, err, `no placeholder token for kind "grc1155"`)
+	})
+
+	for _, kind := range []ledger.Kind{"", ledger.KindGRC20, ledger.KindGRC721} {
+		kind := kind
+
+		t.Run(string(kind), func(t *testing.T) {
+			t.Parallel()
+
+			pkg, err := placeholderPackage(kind)
+			require.NoError(t, err)
+
+			assert.Equal(t, PlaceholderPkgPath, pkg.Path)
+			require.Len(t, pkg.Files, 1)
+			assert.Contains(t, pkg.Files[0].Body, "package placeholder")
+		})
+	}
+}
+
+func TestTypeCheckPackages_Placeholder(t *testing.T) {
+	t.Parallel()
+
+	storage := mockPackageStorage{
+		"gno.land/p/demo/users": {
+			Name: "users",
+			Path: "gno.land/p/demo/users",
+			Files: []*std.MemFile{
+				{
+					Name: "users.gno",
+					Body: "package users\n\ntype AddressOrName string\n",
+				},
+			},
+		},
+	}
+
+	testTable := []struct {
+		name  string
+		body  string
+		valid bool
+	}{
+		{
+			"valid template",
+			`package register
+
+import (
+	token "pkgPath"
+
+	pusers "gno.land/p/demo/users"
+)
+
+func init() {
+	token.Transfer(pusers.AddressOrName("g1user"), token.TotalSupply())
+}
+`,
+			true,
+		},
+		{
+			"undefined token function",
+			`package register
+
+import (
+	token "pkgPath"
+)
+
+func init() {
+	token.Mint(100)
+}
+`,
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			placeholder, err := placeholderPackage(ledger.KindGRC20)
+			require.NoError(t, err)
+
+			target := &Target{
+				Name:       "registry",
+				PathPrefix: "gno.land/r/demo/register",
+				PkgName:    "register",
+				body:       testCase.body,
+			}
+
+			err = typeCheckPackages(
+				&packageGetter{storage: storage, placeholder: placeholder},
+				[]*RenderedPackage{target.Render(PlaceholderPkgPath, "")},
+			)
+
+			if testCase.valid {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var templateErr *TemplateError
+
+			require.ErrorAs(t, err, &templateErr)
+			assert.False(t, templateErr.Inconclusive())
+		})
+	}
+}
@@ -9,15 +9,17 @@ import (
 )
 
 // PrepareTxMessageFn is the callback method that
-// constructs the addpkg transaction message
+// constructs the registration transaction message
 type PrepareTxMessageFn func(PrepareCfg) std.Msg
 
 // PrepareCfg specifies the tx prepare configuration
 type PrepareCfg struct {
 	Creator crypto.Address // the creator address
 	PkgName string
-	PkgPath string
+	PkgPath string // the register package path, or the called realm path
 	Files   []*std.MemFile
+	Func    string   // the called function, in the "call" mode
+	Args    []string // the called function arguments, in the "call" mode
 }
 
 // defaultPrepareTxMessage constructs the default
@@ -36,15 +38,75 @@ func defaultPrepareTxMessage(cfg PrepareCfg) std.Msg {
 	return msgAddPackage
 }
 
-// prepareTransaction prepares the transaction for signing
+// defaultPrepareCallMessage constructs the default
+// register function call transaction message
+func defaultPrepareCallMessage(cfg PrepareCfg) std.Msg {
+	return vm.MsgCall{
+		Caller:  cfg.Creator,
+		PkgPath: cfg.PkgPath,
+		Func:    cfg.Func,
+		Args:    cfg.Args,
+	}
+}
+
+// prepareMessages constructs the transaction messages for the rendered
+// registrations, using the message constructor of the target mode
+func prepareMessages(
+	prepareTxMsgFn PrepareTxMessageFn,
+	prepareCallMsgFn PrepareTxMessageFn,
+	creator crypto.Address,
+	packages []*RenderedPackage,
+) []std.Msg {
+	msgs := make([]std.Msg, 0, len(packages))
+
+	for _, pkg := range packages {
+		prepareFn := prepareTxMsgFn
+		if pkg.Mode == ModeCall {
+			prepareFn = prepareCallMsgFn
+		}
+
+		msgs = append(msgs, prepareFn(PrepareCfg{
+			Creator: creator,
+			PkgName: pkg.PkgName,
+			PkgPath: pkg.PkgPath,
+			Files:   pkg.Files,
+			Func:    pkg.Func,
+			Args:    pkg.Args,
+		}))
+	}
+
+	return msgs
+}
+
+// EstimateRegistration estimates the fee of the
+// registration tx deploying the given register packages
+func EstimateRegistration(estimator estimate.Estimator, packages []*RenderedPackage) std.Fee {
+	tx := prepareTransaction(
+		estimator,
+		"",
+		prepareMessages(
+			defaultPrepareTxMessage,
+			defaultPrepareCallMessage,
+			crypto.Address{},
+			packages,
+		)...,
+	)
+
+	return tx.Fee
+}
+
+// prepareTransaction prepares the transaction for signing,
+// with the given memo
 func prepareTransaction(
 	estimator estimate.Estimator,
-	msg std.Msg,
+	memo string,
+	msgs ...std.Msg,
 ) *std.Tx {
 	// Construct the transaction
 	tx := &std.Tx{
-		Msgs:       []std.Msg{msg},
+		Msgs:       msgs,
 		Signatures: nil,
+		Memo:       memo,
 	}
 
 	// Prepare the gas fee
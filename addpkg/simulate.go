@@ -0,0 +1,118 @@
+package addpkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// simulatePath is the ABCI query path used for tx simulation
+const simulatePath = ".app/simulate"
+
+// vmErrorRegex matches a Gno source position followed by the error message,
+// ex. gno.land/r/demo/register.gno:24:9: undefined: token.Approve
+var vmErrorRegex = regexp.MustCompile(`\.gno:\d+(?::\d+)?:\s*([^"\n]+)`)
+
+// vmErrorStringRegex matches the message of a formatted error string,
+// ex. &errors.errorString{s:"invalid package path"}
+var vmErrorStringRegex = regexp.MustCompile(`s:"([^"\n]+)"`)
+
+// SimulationError is the error returned when the registration
+// transaction fails the pre-broadcast simulation
+type SimulationError struct {
+	Log     string // the raw simulation log
+	VMError string // the parsed VM error
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("transaction simulation failed, %s", e.VMError)
+}
+
+// simulateTransaction simulates the signed transaction on the remote chain,
+// without broadcasting it. The query only fails if the tx is unable to be
+// simulated, while the tx execution outcome is in the returned sdk result
+func simulateTransaction(client abciClient, tx *std.Tx) error {
+	encodedTx, err := amino.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	res, err := client.ABCIQuery(simulatePath, encodedTx)
+	if err != nil {
+		return fmt.Errorf("unable to simulate transaction, %w", err)
+	}
+
+	if res.Response.Error != nil {
+		return newSimulationError(res.Response.Log, res.Response.Error)
+	}
+
+	var result sdk.Result
+
+	if err := amino.Unmarshal(res.Response.Value, &result); err != nil {
+		return fmt.Errorf("unable to decode simulation result, %w", err)
+	}
+
+	if result.Error != nil {
+		return newSimulationError(result.Log, result.Error)
+	}
+
+	return nil
+}
+
+// newSimulationError creates the simulation error from the
+// simulation log, or from the error itself if there is no log
+func newSimulationError(log string, err error) *SimulationError {
+	if log == "" {
+		log = err.Error()
+	}
+
+	return &SimulationError{
+		Log:     log,
+		VMError: parseVMError(log),
+	}
+}
+
+// parseVMError extracts the human-readable VM error
+// from the (verbose) simulation log
+func parseVMError(log string) string {
+	if matches := vmErrorRegex.FindStringSubmatch(log); len(matches) > 1 {
+		return cleanVMError(matches[1])
+	}
+
+	if matches := vmErrorStringRegex.FindStringSubmatch(log); len(matches) > 1 {
+		return cleanVMError(matches[1])
+	}
+
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(line)
+
+		// Skip the error frame markers
+		if line == "" || strings.HasPrefix(line, "--=") {
+			continue
+		}
+
+		// Errors without a message are
+		// represented by their type name
+		line = strings.TrimPrefix(line, "Data: ")
+		if index := strings.Index(line, "{"); index > 0 {
+			line = line[:index]
+		}
+
+		return cleanVMError(line)
+	}
+
+	return "unknown error"
+}
+
+// cleanVMError removes the escaping and trailing
+// punctuation left over from the error formatting
+func cleanVMError(msg string) string {
+	msg = strings.ReplaceAll(msg, `\"`, `"`)
+	msg = strings.TrimRight(msg, `\"}, `)
+
+	return strings.TrimSpace(msg)
+}
@@ -0,0 +1,168 @@
+package addpkg
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	coreTypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVMError(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		log      string
+		expected string
+	}{
+		{
+			"type check error",
+			`--= Error =--
+Data: vm.TypeCheckError{abciError:vm.abciError{}, Errors:[]string{"gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/demo/foo/register.gno:29:9: undefined: token.Approve"}}
+Msg Traces:
+    0  /app/gno.land/pkg/sdk/vm/keeper.go:357 - invalid gno package; type check errors:
+--= /Error =--`,
+			"undefined: token.Approve",
+		},
+		{
+			"escaped error",
+			`--= Error =--
+Data: &errors.errorString{s:"register.gno:38:2: name RegisterGRC20Interface not declared"}
+--= /Error =--`,
+			"name RegisterGRC20Interface not declared",
+		},
+		{
+			"no source position",
+			`--= Error =--
+Data: vm.UnauthorizedUserError{abciError:vm.abciError{}}
+--= /Error =--`,
+			"vm.UnauthorizedUserError",
+		},
+		{
+			"error string",
+			`--= Error =--
+Data: &errors.errorString{s:"invalid package path"}
+--= /Error =--`,
+			"invalid package path",
+		},
+		{
+			"empty log",
+			"",
+			"unknown error",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, parseVMError(testCase.log))
+		})
+	}
+}
+
+// newSimulateClient creates a mock client that
+// serves the given simulation result
+func newSimulateClient(t *testing.T, response abci.ResponseBase, result *sdk.Result) *mockABCIClient {
+	t.Helper()
+
+	return &mockABCIClient{
+		abciQueryFn: func(path string, _ []byte) (*coreTypes.ResultABCIQuery, error) {
+			require.Equal(t, simulatePath, path)
+
+			query := abci.ResponseQuery{
+				ResponseBase: response,
+			}
+
+			if result != nil {
+				query.Value = amino.MustMarshal(result)
+			}
+
+			return &coreTypes.ResultABCIQuery{
+				Response: query,
+			}, nil
+		},
+	}
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	t.Parallel()
+
+	vmLog := `--= Error =--
+Data: vm.TypeCheckError{abciError:vm.abciError{}, Errors:[]string{"gno.land/r/demo/foo/register.gno:29:9: undefined: token.Approve"}}
+--= /Error =--`
+
+	testTable := []struct {
+		name        string
+		response    abci.ResponseBase
+		result      *sdk.Result
+		expectedErr string // the parsed VM error, if any
+	}{
+		{
+			"tx succeeded",
+			abci.ResponseBase{},
+			&sdk.Result{GasUsed: 100},
+			"",
+		},
+		{
+			"tx failed in the VM",
+			abci.ResponseBase{},
+			&sdk.Result{
+				ResponseBase: abci.ResponseBase{
+					Error: abci.StringError("type check errors"),
+					Log:   vmLog,
+				},
+			},
+			"undefined: token.Approve",
+		},
+		{
+			"tx failed without a log",
+			abci.ResponseBase{},
+			&sdk.Result{
+				ResponseBase: abci.ResponseBase{
+					Error: abci.StringError("insufficient funds"),
+				},
+			},
+			"insufficient funds",
+		},
+		{
+			"tx unable to be simulated",
+			abci.ResponseBase{
+				Error: abci.StringError("unable to decode tx"),
+			},
+			nil,
+			"unable to decode tx",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := simulateTransaction(
+				newSimulateClient(t, testCase.response, testCase.result),
+				&std.Tx{Memo: testCase.name},
+			)
+
+			if testCase.expectedErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var simErr *SimulationError
+
+			require.ErrorAs(t, err, &simErr)
+			assert.Equal(t, testCase.expectedErr, simErr.VMError)
+		})
+	}
+}
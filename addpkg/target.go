@@ -0,0 +1,361 @@
+package addpkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/ledger"
+
+	_ "embed"
+)
+
+//go:embed template.txt
+var template string // register contract template
+
+const (
+	// DefaultTargetName is the name of the default (gnoswap) registration target
+	DefaultTargetName = "gnoswap"
+
+	defaultPathPrefix  = "gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5"
+	defaultPkgName     = "token_register"
+	registerFileName   = "register.gno"
+	pkgPathPlaceholder = "pkgPath"
+	keyPlaceholder     = "tokenKey"
+)
+
+const (
+	// ModeAddPkg registers the token by deploying
+	// a register package, the default mode
+	ModeAddPkg = "addpkg"
+
+	// ModeCall registers the token by calling
+	// the register function of the registry realm
+	ModeCall = "call"
+)
+
+var (
+	errUnknownVersion    = errors.New("unknown registry version")
+	errNoTargets         = errors.New("no registration targets configured")
+	errInvalidTargetName = errors.New("invalid target name")
+	errInvalidTargetMode = errors.New("invalid target mode")
+	errMissingCallTarget = errors.New("call targets require a realm and a function")
+	errInvalidTargetKind = errors.New("invalid target kind")
+	errMissingTemplate   = errors.New("grc721 targets require a template")
+)
+
+// Target is a registration target, the registry realms
+// the detected tokens are registered to
+type Target struct {
+	// Name is the unique name of the target
+	Name string `json:"name"`
+
+	// Template is the path to the register package template,
+	// where "pkgPath" is replaced with the token package path.
+	// The embedded (gnoswap) template is used if empty
+	Template string `json:"template,omitempty"`
+
+	// PathPrefix is the path the register packages are deployed under
+	PathPrefix string `json:"pathPrefix"`
+
+	// PkgName is the name of the register packages
+	PkgName string `json:"pkgName"`
+
+	// Mode is the registration mode, "addpkg" or "call"
+	Mode string `json:"mode,omitempty"`
+
+	// Kind is the token standard registered to the target, "grc20" or "grc721".
+	// The grc20 tokens are registered if empty
+	Kind ledger.Kind `json:"kind,omitempty"`
+
+	// Realm is the registry realm called in the "call" mode
+	Realm string `json:"realm,omitempty"`
+
+	// Func is the register function called in the "call" mode,
+	// where "pkgPath" is replaced with the token package path
+	Func string `json:"func,omitempty"`
+
+	// Args are the register function arguments in the "call" mode,
+	// where "pkgPath" is replaced with the token package path, and
+	// "tokenKey" with the key of the tokens kept by key in factory realms.
+	// The keyed tokens are only registered to the targets with a key argument
+	Args []string `json:"args,omitempty"`
+
+	// Version is the registry version of the target. Versioned targets are
+	// only used while their version is active, unversioned targets always are
+	Version string `json:"version,omitempty"`
+
+	// Registry is the registry realm of the target version. Its deployment
+	// activates the version, and it is queried for the registered tokens
+	Registry string `json:"registry,omitempty"`
+
+	// body is the loaded template body
+	body string
+}
+
+// RenderedPackage is the registration of a token, for a single target.
+// In the "addpkg" mode, it is the deployed register package.
+// In the "call" mode, it is the register function call of the registry realm
+type RenderedPackage struct {
+	Target  string
+	Mode    string
+	Kind    ledger.Kind
+	PkgName string
+	PkgPath string // the register package path, or the called realm path
+	Files   []*std.MemFile
+	Func    string
+	Args    []string
+}
+
+// DefaultTarget returns the default (gnoswap) registration target
+func DefaultTarget() *Target {
+	return &Target{
+		Name:       DefaultTargetName,
+		PathPrefix: defaultPathPrefix,
+		PkgName:    defaultPkgName,
+		Mode:       ModeAddPkg,
+		Kind:       ledger.KindGRC20,
+		body:       template,
+	}
+}
+
+// LoadTargets loads the registration targets from the given JSON config file.
+// Relative template paths are resolved against the config file directory
+func LoadTargets(path string) ([]*Target, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read targets file, %w", err)
+	}
+
+	var targets []*Target
+
+	if err := json.Unmarshal(raw, &targets); err != nil {
+		return nil, fmt.Errorf("unable to parse targets file, %w", err)
+	}
+
+	if len(targets) == 0 {
+		return nil, errNoTargets
+	}
+
+	names := make(map[string]struct{}, len(targets))
+
+	for _, target := range targets {
+		if _, exists := names[target.Name]; exists || target.Name == "" {
+			return nil, fmt.Errorf("%w, %q", errInvalidTargetName, target.Name)
+		}
+
+		names[target.Name] = struct{}{}
+
+		switch target.Kind {
+		case "":
+			target.Kind = ledger.KindGRC20
+		case ledger.KindGRC20, ledger.KindGRC721:
+		default:
+			return nil, fmt.Errorf("%w %q, target %s", errInvalidTargetKind, target.Kind, target.Name)
+		}
+
+		switch target.Mode {
+		case "":
+			target.Mode = ModeAddPkg
+		case ModeAddPkg:
+		case ModeCall:
+			if target.Realm == "" || target.Func == "" {
+				return nil, fmt.Errorf("%w, target %s", errMissingCallTarget, target.Name)
+			}
+
+			// Call targets don't deploy packages
+			continue
+		default:
+			return nil, fmt.Errorf("%w %q, target %s", errInvalidTargetMode, target.Mode, target.Name)
+		}
+
+		if target.PathPrefix == "" {
+			target.PathPrefix = defaultPathPrefix
+		}
+
+		if target.PkgName == "" {
+			target.PkgName = defaultPkgName
+		}
+
+		target.body = template
+
+		if target.Template == "" {
+			// The embedded template only registers grc20 tokens
+			if target.Kind == ledger.KindGRC721 {
+				return nil, fmt.Errorf("%w, target %s", errMissingTemplate, target.Name)
+			}
+
+			continue
+		}
+
+		templatePath := target.Template
+		if !filepath.IsAbs(templatePath) {
+			templatePath = filepath.Join(filepath.Dir(path), templatePath)
+		}
+
+		body, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read template of target %s, %w", target.Name, err)
+		}
+
+		target.body = string(body)
+	}
+
+	return targets, nil
+}
+
+// Render renders the registration of the given token package path, and
+// its key, if it is kept by key in a factory realm. The keyed tokens are
+// only rendered by the keyed targets, since their package path is the realm
+func (t *Target) Render(pkgPath, key string) *RenderedPackage {
+	if t.Mode == ModeCall {
+		replacer := strings.NewReplacer(pkgPathPlaceholder, pkgPath, keyPlaceholder, key)
+
+		args := make([]string, 0, len(t.Args))
+		for _, arg := range t.Args {
+			args = append(args, replacer.Replace(arg))
+		}
+
+		return &RenderedPackage{
+			Target:  t.Name,
+			Mode:    ModeCall,
+			Kind:    t.Kind,
+			PkgPath: t.Realm,
+			Func:    replacer.Replace(t.Func),
+			Args:    args,
+		}
+	}
+
+	/*
+		orig:					gno.land/r/gnoswap/gns
+		remove:				gnoswap/gns
+		toRegister: 	gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/gnoswap/gns
+	*/
+	_removeCommon := strings.Replace(pkgPath, "gno.land/r/", "", 1)
+
+	return &RenderedPackage{
+		Target:  t.Name,
+		Mode:    ModeAddPkg,
+		Kind:    t.Kind,
+		PkgName: t.PkgName,
+		PkgPath: strings.TrimSuffix(t.PathPrefix, "/") + "/" + _removeCommon,
+		Files: []*std.MemFile{
+			{
+				Name: registerFileName,
+				Body: strings.ReplaceAll(t.body, pkgPathPlaceholder, pkgPath),
+			},
+		},
+	}
+}
+
+// keyed checks if the target registers the tokens kept by key in factory
+// realms, a register function call with the token key as an argument
+func (t *Target) keyed() bool {
+	if t.Mode != ModeCall {
+		return false
+	}
+
+	return slices.ContainsFunc(t.Args, func(arg string) bool {
+		return strings.Contains(arg, keyPlaceholder)
+	})
+}
+
+// kind returns the token standard registered to the target
+func (t *Target) kind() ledger.Kind {
+	if t.Kind == "" {
+		return ledger.KindGRC20
+	}
+
+	return t.Kind
+}
+
+// Versions returns the registry versions of the targets, and
+// the registry realms that activate them, if any
+func Versions(targets []*Target) map[string]string {
+	versions := make(map[string]string)
+
+	for _, target := range targets {
+		if target.Version == "" {
+			continue
+		}
+
+		if target.Registry != "" || versions[target.Version] == "" {
+			versions[target.Version] = target.Registry
+		}
+	}
+
+	return versions
+}
+
+// activeTargets returns the targets of the given token kind,
+// used with the given registry version
+func activeTargets(targets []*Target, version string, kind ledger.Kind) []*Target {
+	active := make([]*Target, 0, len(targets))
+
+	for _, target := range targets {
+		if target.kind() != kind {
+			continue
+		}
+
+		if target.Version == "" || target.Version == version {
+			active = append(active, target)
+		}
+	}
+
+	return active
+}
+
+// namedTargets returns the targets with the given names,
+// or all the targets if no names are given
+func namedTargets(targets []*Target, names []string) []*Target {
+	if len(names) == 0 {
+		return targets
+	}
+
+	named := make([]*Target, 0, len(names))
+
+	for _, target := range targets {
+		if slices.Contains(names, target.Name) {
+			named = append(named, target)
+		}
+	}
+
+	return named
+}
+
+// renderTargets renders the register packages of the token, for every
+// target. The keyed tokens are only rendered by the keyed targets,
+// and the tokens registered by their package path by the others
+func renderTargets(targets []*Target, pkgPath, key string) []*RenderedPackage {
+	packages := make([]*RenderedPackage, 0, len(targets))
+
+	for _, target := range targets {
+		if target.keyed() != (key != "") {
+			continue
+		}
+
+		packages = append(packages, target.Render(pkgPath, key))
+	}
+
+	return packages
+}
+
+// splitTokenID splits the token ID into the token package path, and its
+// key, if any. The keyed token IDs are in the grc20reg "pkgPath.key" format,
+// and the last element of a package path never contains a dot
+func splitTokenID(tokenID string) (string, string) {
+	name := tokenID[strings.LastIndex(tokenID, "/")+1:]
+
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return tokenID, ""
+	}
+
+	return tokenID[:len(tokenID)-len(name)+i], name[i+1:]
+}
@@ -0,0 +1,373 @@
+package addpkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestTarget_Render(t *testing.T) {
+	t.Parallel()
+
+	pkg := DefaultTarget().Render("gno.land/r/gnoswap/gns", "")
+
+	assert.Equal(t, DefaultTargetName, pkg.Target)
+	assert.Equal(t, "token_register", pkg.PkgName)
+	assert.Equal(t, "gno.land/r/g1er355fkjksqpdtwmhf5penwa82p0rhqxkkyhk5/gnoswap/gns", pkg.PkgPath)
+
+	require.Len(t, pkg.Files, 1)
+
+	assert.Equal(t, "register.gno", pkg.Files[0].Name)
+	assert.Contains(t, pkg.Files[0].Body, `token "gno.land/r/gnoswap/gns"`)
+	assert.NotContains(t, pkg.Files[0].Body, `"pkgPath"`)
+}
+
+func TestLoadTargets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "market.txt"),
+		[]byte(`package market_register
+
+import token "pkgPath"
+`),
+		0o600,
+	))
+
+	path := filepath.Join(dir, "targets.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "gnoswap"},
+		{"name": "market", "template": "market.txt", "pathPrefix": "gno.land/r/market/", "pkgName": "market_register"}
+	]`), 0o600))
+
+	targets, err := LoadTargets(path)
+	require.NoError(t, err)
+
+	require.Len(t, targets, 2)
+
+	// Make sure the defaults are applied
+	gnoswap := targets[0].Render("gno.land/r/demo/foo", "")
+
+	assert.Equal(t, DefaultTarget().Render("gno.land/r/demo/foo", ""), gnoswap)
+
+	// Make sure the custom template is used
+	market := targets[1].Render("gno.land/r/demo/foo", "")
+
+	assert.Equal(t, "gno.land/r/market/demo/foo", market.PkgPath)
+	assert.Equal(t, "market_register", market.PkgName)
+	assert.Equal(t, "package market_register\n\nimport token \"gno.land/r/demo/foo\"\n", market.Files[0].Body)
+}
+
+func TestLoadTargets_Call(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "targets.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "gnoswap"},
+		{"name": "market", "mode": "call", "realm": "gno.land/r/market/registry", "func": "Register", "args": ["pkgPath", "grc20"]}
+	]`), 0o600))
+
+	targets, err := LoadTargets(path)
+	require.NoError(t, err)
+
+	require.Len(t, targets, 2)
+
+	market := targets[1].Render("gno.land/r/demo/foo", "")
+
+	assert.Equal(t, ModeCall, market.Mode)
+	assert.Equal(t, "gno.land/r/market/registry", market.PkgPath)
+	assert.Equal(t, "Register", market.Func)
+	assert.Equal(t, []string{"gno.land/r/demo/foo", "grc20"}, market.Args)
+	assert.Empty(t, market.Files)
+
+	// Make sure the message constructor is selected by the mode
+	msgs := prepareMessages(
+		defaultPrepareTxMessage,
+		defaultPrepareCallMessage,
+		crypto.Address{},
+		[]*RenderedPackage{targets[0].Render("gno.land/r/demo/foo", ""), market},
+	)
+
+	require.Len(t, msgs, 2)
+
+	assert.IsType(t, vm.MsgAddPackage{}, msgs[0])
+
+	call, ok := msgs[1].(vm.MsgCall)
+	require.True(t, ok)
+
+	assert.Equal(t, "gno.land/r/market/registry", call.PkgPath)
+	assert.Equal(t, "Register", call.Func)
+	assert.Equal(t, []string{"gno.land/r/demo/foo", "grc20"}, call.Args)
+}
+
+func TestLoadTargets_Kind(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "gallery.txt"),
+		[]byte(`package gallery_register
+
+import collection "pkgPath"
+`),
+		0o600,
+	))
+
+	path := filepath.Join(dir, "targets.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "gnoswap"},
+		{"name": "gallery", "kind": "grc721", "template": "gallery.txt", "pathPrefix": "gno.land/r/gallery", "pkgName": "gallery_register"}
+	]`), 0o600))
+
+	targets, err := LoadTargets(path)
+	require.NoError(t, err)
+
+	require.Len(t, targets, 2)
+	assert.Equal(t, ledger.KindGRC20, targets[0].Kind)
+	assert.Equal(t, ledger.KindGRC721, targets[1].Kind)
+
+	a := &AddPkg{
+		targets: targets,
+	}
+
+	// Make sure the tokens are only rendered for the targets of their kind
+	grc20 := a.Render(ledger.KindGRC20, "gno.land/r/demo/foo", "")
+
+	require.Len(t, grc20, 1)
+	assert.Equal(t, "gnoswap", grc20[0].Target)
+
+	grc721 := a.Render(ledger.KindGRC721, "gno.land/r/demo/nft", "")
+
+	require.Len(t, grc721, 1)
+	assert.Equal(t, "gallery", grc721[0].Target)
+	assert.Equal(t, ledger.KindGRC721, grc721[0].Kind)
+	assert.Equal(t, "gno.land/r/gallery/demo/nft", grc721[0].PkgPath)
+	assert.Equal(
+		t,
+		"package gallery_register\n\nimport collection \"gno.land/r/demo/nft\"\n",
+		grc721[0].Files[0].Body,
+	)
+}
+
+func TestLoadTargets_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name    string
+		targets string
+	}{
+		{
+			"no targets",
+			`[]`,
+		},
+		{
+			"missing name",
+			`[{"pathPrefix": "gno.land/r/market"}]`,
+		},
+		{
+			"duplicate name",
+			`[{"name": "gnoswap"}, {"name": "gnoswap"}]`,
+		},
+		{
+			"missing template",
+			`[{"name": "market", "template": "missing.txt"}]`,
+		},
+		{
+			"invalid mode",
+			`[{"name": "market", "mode": "run"}]`,
+		},
+		{
+			"call without function",
+			`[{"name": "market", "mode": "call", "realm": "gno.land/r/market/registry"}]`,
+		},
+		{
+			"invalid kind",
+			`[{"name": "market", "kind": "grc1155"}]`,
+		},
+		{
+			"grc721 without template",
+			`[{"name": "gallery", "kind": "grc721"}]`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "targets.json")
+
+			require.NoError(t, os.WriteFile(path, []byte(testCase.targets), 0o600))
+
+			_, err := LoadTargets(path)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAddPkg_SetVersion(t *testing.T) {
+	t.Parallel()
+
+	a := &AddPkg{
+		targets: []*Target{
+			{Name: "router", PkgName: "router_register", Mode: ModeAddPkg},
+			{Name: "pool_v1", PkgName: "pool_register", Mode: ModeAddPkg, Version: "v1"},
+			{Name: "pool_v2", PkgName: "pool_register", Mode: ModeAddPkg, Version: "v2", Registry: "gno.land/r/gnoswap/v2/pool"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"v1": "", "v2": "gno.land/r/gnoswap/v2/pool"}, Versions(a.targets))
+
+	targetNames := func() []string {
+		names := make([]string, 0)
+		for _, pkg := range a.Render(ledger.KindGRC20, "gno.land/r/demo/foo", "") {
+			names = append(names, pkg.Target)
+		}
+
+		return names
+	}
+
+	// Make sure only the unversioned targets are used without a version
+	assert.Equal(t, []string{"router"}, targetNames())
+
+	require.NoError(t, a.SetVersion("v2"))
+	assert.Equal(t, []string{"router", "pool_v2"}, targetNames())
+	assert.Equal(t, "gno.land/r/gnoswap/v2/pool", a.registryPath())
+
+	// Make sure unknown versions are not activated
+	assert.ErrorIs(t, a.SetVersion("v3"), errUnknownVersion)
+	assert.Equal(t, []string{"router", "pool_v2"}, targetNames())
+}
+
+func TestAddPkg_Render_Named(t *testing.T) {
+	t.Parallel()
+
+	a := &AddPkg{
+		targets: []*Target{
+			{Name: "router", PkgName: "router_register", Mode: ModeAddPkg},
+			{Name: "pool", PkgName: "pool_register", Mode: ModeAddPkg},
+			{Name: "collections", PkgName: "collection_register", Mode: ModeAddPkg, Kind: ledger.KindGRC721},
+		},
+	}
+
+	targetNames := func(names ...string) []string {
+		rendered := make([]string, 0)
+		for _, pkg := range a.Render(ledger.KindGRC20, "gno.land/r/demo/foo", "", names...) {
+			rendered = append(rendered, pkg.Target)
+		}
+
+		return rendered
+	}
+
+	// Make sure every target of the kind is rendered without names
+	assert.Equal(t, []string{"router", "pool"}, targetNames())
+
+	// Make sure only the named targets of the kind are rendered
+	assert.Equal(t, []string{"pool"}, targetNames("pool"))
+	assert.Empty(t, targetNames("collections"))
+}
+
+func TestAddPkg_Render_Keyed(t *testing.T) {
+	t.Parallel()
+
+	a := &AddPkg{
+		targets: []*Target{
+			DefaultTarget(),
+			{
+				Name:  "registry",
+				Mode:  ModeCall,
+				Kind:  ledger.KindGRC20,
+				Realm: "gno.land/r/demo/grc20reg",
+				Func:  "Register",
+				Args:  []string{"pkgPath"},
+			},
+			{
+				Name:  "keyed_registry",
+				Mode:  ModeCall,
+				Kind:  ledger.KindGRC20,
+				Realm: "gno.land/r/demo/grc20reg",
+				Func:  "RegisterKeyed",
+				Args:  []string{"pkgPath", "tokenKey"},
+			},
+		},
+	}
+
+	// Make sure the keyed token is only rendered by the keyed target,
+	// with its realm and its key as separate arguments
+	keyed := a.Render(ledger.KindGRC20, "gno.land/r/demo/factory", "bar")
+	require.Len(t, keyed, 1)
+
+	assert.Equal(t, &RenderedPackage{
+		Target:  "keyed_registry",
+		Mode:    ModeCall,
+		Kind:    ledger.KindGRC20,
+		PkgPath: "gno.land/r/demo/grc20reg",
+		Func:    "RegisterKeyed",
+		Args:    []string{"gno.land/r/demo/factory", "bar"},
+	}, keyed[0])
+
+	// Make sure the token registered by its package path skips the keyed target
+	rendered := a.Render(ledger.KindGRC20, "gno.land/r/demo/foo", "")
+	require.Len(t, rendered, 2)
+
+	assert.Equal(t, DefaultTargetName, rendered[0].Target)
+	assert.Equal(t, "registry", rendered[1].Target)
+}
+
+func TestSplitTokenID(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name    string
+		tokenID string
+		pkgPath string
+		key     string
+	}{
+		{
+			"package path",
+			"gno.land/r/demo/foo",
+			"gno.land/r/demo/foo",
+			"",
+		},
+		{
+			"keyed token",
+			"gno.land/r/demo/factory.bar",
+			"gno.land/r/demo/factory",
+			"bar",
+		},
+		{
+			"key with a dot",
+			"gno.land/r/demo/factory.bar.v2",
+			"gno.land/r/demo/factory",
+			"bar.v2",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgPath, key := splitTokenID(testCase.tokenID)
+
+			assert.Equal(t, testCase.pkgPath, pkgPath)
+			assert.Equal(t, testCase.key, key)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package addpkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/stdlibs"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// qfilePath is the ABCI query path used for fetching package files
+const qfilePath = "vm/qfile"
+
+// PackageStorage is the storage of the packages
+// deployed on chain, that the indexer has saved
+type PackageStorage interface {
+	// GetPackage fetches the deployed package using its package path
+	GetPackage(pkgPath string) (*std.MemPackage, error)
+}
+
+// abciClient is the ABCI query client, used
+// for fetching the packages that are not saved yet
+type abciClient interface {
+	ABCIQuery(path string, data []byte) (*ctypes.ResultABCIQuery, error)
+}
+
+// TemplateError is the error returned when a rendered
+// register package fails to parse or type-check
+type TemplateError struct {
+	Target     string   // the registration target name
+	PkgPath    string   // the register package path
+	Unresolved []string // the imports that could not be resolved, if any
+	Err        error    // the type-check error
+}
+
+func (e *TemplateError) Error() string {
+	msg := fmt.Sprintf("template error for target %s (%s), %s", e.Target, e.PkgPath, e.Err)
+
+	if len(e.Unresolved) > 0 {
+		msg += fmt.Sprintf(" (unresolved imports: %s)", strings.Join(e.Unresolved, ", "))
+	}
+
+	return msg
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// Inconclusive returns a flag indicating if the type-check failed
+// because some imports could not be resolved, in which case the
+// template error can also be caused by packages that are not indexed yet
+func (e *TemplateError) Inconclusive() bool {
+	return len(e.Unresolved) > 0
+}
+
+// packageGetter resolves the imports of the register packages,
+// from the standard libraries and the saved chain packages.
+// The packages that are not saved yet are fetched from the chain, if possible
+type packageGetter struct {
+	storage PackageStorage
+	client  abciClient
+
+	placeholder *std.MemPackage // the placeholder token package, if any
+
+	unresolved []string
+}
+
+// GetMemPackage fetches the package with the given path, if any
+func (g *packageGetter) GetMemPackage(pkgPath string) *std.MemPackage {
+	if pkg := stdlibs.EmbeddedMemPackage(pkgPath); pkg != nil {
+		return pkg
+	}
+
+	if g.placeholder != nil && g.placeholder.Path == pkgPath {
+		return g.placeholder
+	}
+
+	if g.storage != nil {
+		if pkg, err := g.storage.GetPackage(pkgPath); err == nil {
+			return pkg
+		}
+	}
+
+	if g.client != nil {
+		if pkg, err := queryPackage(g.client, pkgPath); err == nil {
+			return pkg
+		}
+	}
+
+	g.unresolved = append(g.unresolved, pkgPath)
+
+	return nil
+}
+
+// queryPackage fetches the deployed package files from the chain
+func queryPackage(client abciClient, pkgPath string) (*std.MemPackage, error) {
+	res, err := client.ABCIQuery(qfilePath, []byte(pkgPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Response.Error != nil {
+		return nil, res.Response.Error
+	}
+
+	pkg := &std.MemPackage{
+		Name: pkgPath[strings.LastIndex(pkgPath, "/")+1:],
+		Path: pkgPath,
+	}
+
+	// The package query returns the newline-separated file names
+	for _, name := range strings.Split(string(res.Response.Data), "\n") {
+		if !strings.HasSuffix(name, ".gno") {
+			continue
+		}
+
+		fileRes, err := client.ABCIQuery(qfilePath, []byte(pkgPath+"/"+name))
+		if err != nil {
+			return nil, err
+		}
+
+		if fileRes.Response.Error != nil {
+			return nil, fileRes.Response.Error
+		}
+
+		pkg.Files = append(pkg.Files, &std.MemFile{
+			Name: name,
+			Body: string(fileRes.Response.Data),
+		})
+	}
+
+	return pkg, nil
+}
+
+// TypeCheck parses and type-checks the register packages rendered
+// for the given token package path, for every target of its kind
+func (a *AddPkg) TypeCheck(kind ledger.Kind, pkgPath string) error {
+	return typeCheckPackages(a.packageGetter(), a.Render(kind, pkgPath, ""))
+}
+
+// packageGetter creates the import resolver of the register packages
+func (a *AddPkg) packageGetter() *packageGetter {
+	return &packageGetter{
+		storage: a.packages,
+		client:  &a.rpcClient,
+	}
+}
+
+// typeCheckPackages type-checks the rendered register packages,
+// returning the template error of the first broken package, if any
+func typeCheckPackages(getter *packageGetter, packages []*RenderedPackage) error {
+	for _, pkg := range packages {
+		// Call targets don't deploy packages
+		if pkg.Mode == ModeCall {
+			continue
+		}
+
+		getter.unresolved = nil
+
+		memPkg := &std.MemPackage{
+			Name:  pkg.PkgName,
+			Path:  pkg.PkgPath,
+			Files: pkg.Files,
+		}
+
+		if err := gnolang.TypeCheckMemPackage(memPkg, getter, false); err != nil {
+			return &TemplateError{
+				Target:     pkg.Target,
+				PkgPath:    pkg.PkgPath,
+				Unresolved: getter.unresolved,
+				Err:        err,
+			}
+		}
+	}
+
+	return nil
+}
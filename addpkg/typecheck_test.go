@@ -0,0 +1,158 @@
+package addpkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errPackageNotFound = errors.New("package not found")
+
+type mockPackageStorage map[string]*std.MemPackage
+
+func (m mockPackageStorage) GetPackage(pkgPath string) (*std.MemPackage, error) {
+	pkg, ok := m[pkgPath]
+	if !ok {
+		return nil, errPackageNotFound
+	}
+
+	return pkg, nil
+}
+
+func TestTypeCheckPackages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tokenPath    = "gno.land/r/demo/foo20"
+		registryPath = "gno.land/r/demo/registry"
+
+		storage = mockPackageStorage{
+			tokenPath: {
+				Name: "foo20",
+				Path: tokenPath,
+				Files: []*std.MemFile{
+					{
+						Name: "foo20.gno",
+						Body: "package foo20\n\nfunc TotalSupply() uint64 { return 0 }\n",
+					},
+				},
+			},
+			registryPath: {
+				Name: "registry",
+				Path: registryPath,
+				Files: []*std.MemFile{
+					{
+						Name: "registry.gno",
+						Body: "package registry\n\nfunc Register(pkgPath string, totalSupply func() uint64) {}\n",
+					},
+				},
+			},
+		}
+	)
+
+	testTable := []struct {
+		name         string
+		body         string
+		valid        bool
+		inconclusive bool
+	}{
+		{
+			"valid template",
+			`package register
+
+import (
+	token "pkgPath"
+
+	"gno.land/r/demo/registry"
+)
+
+func init() {
+	registry.Register("pkgPath", token.TotalSupply)
+}
+`,
+			true,
+			false,
+		},
+		{
+			"undefined token function",
+			`package register
+
+import (
+	token "pkgPath"
+
+	"gno.land/r/demo/registry"
+)
+
+func init() {
+	registry.Register("pkgPath", token.Supply)
+}
+`,
+			false,
+			false,
+		},
+		{
+			"syntax error",
+			`package register
+
+func init() {
+`,
+			false,
+			false,
+		},
+		{
+			"unresolved import",
+			`package register
+
+import (
+	token "pkgPath"
+
+	"gno.land/r/demo/unknown"
+)
+
+func init() {
+	unknown.Register("pkgPath", token.TotalSupply)
+}
+`,
+			false,
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			target := &Target{
+				Name:       "registry",
+				PathPrefix: "gno.land/r/demo/register",
+				PkgName:    "register",
+				body:       testCase.body,
+			}
+
+			err := typeCheckPackages(
+				&packageGetter{storage: storage},
+				[]*RenderedPackage{target.Render(tokenPath, "")},
+			)
+
+			if testCase.valid {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var templateErr *TemplateError
+
+			require.ErrorAs(t, err, &templateErr)
+
+			assert.Equal(t, "registry", templateErr.Target)
+			assert.Equal(t, "gno.land/r/demo/register/demo/foo20", templateErr.PkgPath)
+			assert.Equal(t, testCase.inconclusive, templateErr.Inconclusive())
+			assert.Contains(t, templateErr.Error(), "template error for target registry")
+		})
+	}
+}
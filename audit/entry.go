@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// ErrMismatch is returned when the committed tx
+// doesn't match the audited signed tx
+var ErrMismatch = errors.New("committed transaction doesn't match the audit entry")
+
+// Entry is the audit record of a signed tx. Every entry is linked
+// to the previous one by the hash of its line, forming a hash chain
+type Entry struct {
+	Seq  uint64    `json:"seq"`  // the entry sequence, starting at 1
+	Time time.Time `json:"time"` // the signing time
+
+	TxHash        string            `json:"txHash"`        // the base64 encoded tx hash
+	Signer        string            `json:"signer"`        // the signer address
+	AccountNumber uint64            `json:"accountNumber"` // the signer account number
+	Sequence      uint64            `json:"sequence"`      // the signer account sequence
+	Fee           string            `json:"fee"`           // the tx gas fee
+	GasWanted     int64             `json:"gasWanted"`     // the tx gas limit
+	Memo          string            `json:"memo"`          // the tx memo
+	Messages      []json.RawMessage `json:"messages"`      // the amino JSON encoded tx messages
+
+	Reason string   `json:"reason"` // the reason the tx was signed
+	Tokens []*Token `json:"tokens"` // the tokens that triggered the tx
+
+	Prev string `json:"prev"` // the hash of the previous entry line, empty for the first entry
+}
+
+// Token is the token that triggered the signed tx
+type Token struct {
+	PkgPath      string `json:"pkgPath"`
+	DeployTxHash string `json:"deployTxHash,omitempty"`
+}
+
+// NewEntry creates the audit entry of the signed tx
+func NewEntry(
+	tx *std.Tx,
+	txHash string,
+	signer std.Account,
+	reason string,
+	tokens []*Token,
+) (*Entry, error) {
+	messages, err := encodeMessages(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		Time:          time.Now().UTC(),
+		TxHash:        txHash,
+		Signer:        signer.GetAddress().String(),
+		AccountNumber: signer.GetAccountNumber(),
+		Sequence:      signer.GetSequence(),
+		Fee:           tx.Fee.GasFee.String(),
+		GasWanted:     tx.Fee.GasWanted,
+		Memo:          tx.Memo,
+		Messages:      messages,
+		Reason:        reason,
+		Tokens:        tokens,
+	}, nil
+}
+
+// Compare cross-checks the audit entry against the committed tx,
+// returning ErrMismatch if the tx content differs from the entry
+func Compare(entry *Entry, tx *std.Tx) error {
+	messages, err := encodeMessages(tx)
+	if err != nil {
+		return err
+	}
+
+	signers := tx.GetSigners()
+
+	switch {
+	case len(signers) == 0 || signers[0].String() != entry.Signer:
+		return fmt.Errorf("%w, signer differs", ErrMismatch)
+	case tx.Fee.GasFee.String() != entry.Fee || tx.Fee.GasWanted != entry.GasWanted:
+		return fmt.Errorf("%w, fee differs", ErrMismatch)
+	case tx.Memo != entry.Memo:
+		return fmt.Errorf("%w, memo differs", ErrMismatch)
+	case !slices.EqualFunc(messages, entry.Messages, func(a, b json.RawMessage) bool {
+		return string(a) == string(b)
+	}):
+		return fmt.Errorf("%w, messages differ", ErrMismatch)
+	}
+
+	return nil
+}
+
+// encodeMessages encodes the tx messages to amino JSON
+func encodeMessages(tx *std.Tx) ([]json.RawMessage, error) {
+	messages := make([]json.RawMessage, 0, len(tx.Msgs))
+
+	for _, msg := range tx.Msgs {
+		raw, err := amino.MarshalJSON(msg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode transaction message, %w", err)
+		}
+
+		messages = append(messages, raw)
+	}
+
+	return messages, nil
+}
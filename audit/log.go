@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Log is the append-only JSON lines audit log. The entries
+// are synced to disk before the signed tx is broadcast
+type Log struct {
+	file *os.File
+
+	seq  uint64 // the sequence of the last entry
+	head string // the hash of the last entry line
+	size int64  // the size of the complete entries
+
+	mux sync.Mutex
+}
+
+// Open opens the audit log, creating it if it doesn't exist. The hash
+// chain of the existing entries is verified, so new entries are never
+// appended to a tampered log. A partial last line, left by a crash
+// mid-write, is discarded, since its tx was never broadcast
+func Open(path string) (*Log, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to read audit log, %w", err)
+	}
+
+	// Discard the partial last line, if any
+	complete := raw[:bytes.LastIndexByte(raw, '\n')+1]
+
+	chain, err := Verify(bytes.NewReader(complete))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit log, %w", err)
+	}
+
+	if len(complete) != len(raw) {
+		if err := file.Truncate(int64(len(complete))); err != nil {
+			_ = file.Close()
+
+			return nil, fmt.Errorf("unable to discard partial audit entry, %w", err)
+		}
+	}
+
+	if _, err := file.Seek(int64(len(complete)), io.SeekStart); err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("unable to seek audit log, %w", err)
+	}
+
+	return &Log{
+		file: file,
+		seq:  uint64(len(chain.Entries)),
+		head: chain.Head,
+		size: int64(len(complete)),
+	}, nil
+}
+
+// Append links the entry to the hash chain, and appends it to the log.
+// It returns once the entry is synced to disk
+func (l *Log) Append(entry *Entry) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry.Seq = l.seq + 1
+	entry.Prev = l.head
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit entry, %w", err)
+	}
+
+	line = append(line, '\n')
+
+	if _, err := l.file.Write(line); err != nil {
+		l.discard()
+
+		return fmt.Errorf("unable to write audit entry, %w", err)
+	}
+
+	if err := l.file.Sync(); err != nil {
+		l.discard()
+
+		return fmt.Errorf("unable to sync audit log, %w", err)
+	}
+
+	l.seq = entry.Seq
+	l.head = hashLine(line[:len(line)-1])
+	l.size += int64(len(line))
+
+	return nil
+}
+
+// discard discards the partially written entry, if any,
+// so the next entry is appended after the complete entries
+func (l *Log) discard() {
+	_ = l.file.Truncate(l.size)
+	_, _ = l.file.Seek(l.size, io.SeekStart)
+}
+
+// Head returns the hash of the last entry line, which
+// can be recorded externally to anchor the hash chain
+func (l *Log) Head() string {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return l.head
+}
+
+// Close closes the audit log
+func (l *Log) Close() error {
+	return l.file.Close()
+}
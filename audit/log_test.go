@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEntries appends the entries for the given tx hashes to the audit log
+func writeEntries(t *testing.T, path string, txHashes ...string) {
+	t.Helper()
+
+	l, err := Open(path)
+	require.NoError(t, err)
+
+	for _, txHash := range txHashes {
+		require.NoError(t, l.Append(&Entry{
+			TxHash: txHash,
+			Reason: "grc20 registration",
+			Tokens: []*Token{{PkgPath: "gno.land/r/demo/" + txHash}},
+		}))
+	}
+
+	require.NoError(t, l.Close())
+}
+
+// verifyFile verifies the hash chain of the audit log file
+func verifyFile(t *testing.T, path string) (*Chain, error) {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return Verify(bytes.NewReader(raw))
+}
+
+func TestLog_Append(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	writeEntries(t, path, "foo", "bar")
+
+	// Make sure the chain is continued after a reopen
+	writeEntries(t, path, "baz")
+
+	chain, err := verifyFile(t, path)
+	require.NoError(t, err)
+
+	require.Len(t, chain.Entries, 3)
+
+	for i, entry := range chain.Entries {
+		assert.Equal(t, uint64(i+1), entry.Seq)
+	}
+
+	assert.Equal(t, "baz", chain.Entries[2].TxHash)
+	assert.Empty(t, chain.Entries[0].Prev)
+	assert.NotEmpty(t, chain.Head)
+
+	l, err := Open(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, chain.Head, l.Head())
+	require.NoError(t, l.Close())
+}
+
+func TestLog_PartialEntry(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	writeEntries(t, path, "foo")
+
+	// Simulate a crash mid-write
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+
+	_, err = file.WriteString(`{"seq":2,"txHash":"ba`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// Make sure the partial entry is discarded
+	writeEntries(t, path, "bar")
+
+	chain, err := verifyFile(t, path)
+	require.NoError(t, err)
+
+	require.Len(t, chain.Entries, 2)
+	assert.Equal(t, "bar", chain.Entries[1].TxHash)
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		tamper func(lines [][]byte) [][]byte
+	}{
+		{
+			"modified entry",
+			func(lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte("bar"), []byte("baz"), 1)
+
+				return lines
+			},
+		},
+		{
+			"removed entry",
+			func(lines [][]byte) [][]byte {
+				return append(lines[:1], lines[2:]...)
+			},
+		},
+		{
+			"reordered entries",
+			func(lines [][]byte) [][]byte {
+				lines[0], lines[1] = lines[1], lines[0]
+
+				return lines
+			},
+		},
+		{
+			"invalid entry",
+			func(lines [][]byte) [][]byte {
+				lines[2] = []byte("not an entry")
+
+				return lines
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+			writeEntries(t, path, "foo", "bar", "baz")
+
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			lines := testCase.tamper(bytes.Split(bytes.TrimSuffix(raw, []byte("\n")), []byte("\n")))
+
+			require.NoError(t, os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0o600))
+
+			_, err = verifyFile(t, path)
+			assert.ErrorIs(t, err, ErrBrokenChain)
+
+			// Make sure nothing is appended to the tampered log
+			_, err = Open(path)
+			assert.ErrorIs(t, err, ErrBrokenChain)
+		})
+	}
+}
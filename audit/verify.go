@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxLineSize is the maximum size of an entry line,
+// which carries the register packages of the tx
+const maxLineSize = 64 * 1024 * 1024
+
+// ErrBrokenChain is returned when the audit log hash chain is broken,
+// since an entry was modified, removed or inserted
+var ErrBrokenChain = errors.New("audit log hash chain broken")
+
+// Chain is the verified audit log hash chain
+type Chain struct {
+	Entries []*Entry // the entries, in order
+	Head    string   // the hash of the last entry line, empty if there are no entries
+}
+
+// Verify reads the audit log, and verifies its hash chain. Every entry
+// must link to the hash of the previous line, with the next sequence
+func Verify(r io.Reader) (*Chain, error) {
+	var (
+		chain   = &Chain{Entries: make([]*Entry, 0)}
+		scanner = bufio.NewScanner(r)
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%w, line %d is not a valid entry, %w", ErrBrokenChain, line, err)
+		}
+
+		if entry.Seq != uint64(line) {
+			return nil, fmt.Errorf("%w, line %d has sequence %d", ErrBrokenChain, line, entry.Seq)
+		}
+
+		if entry.Prev != chain.Head {
+			return nil, fmt.Errorf("%w, line %d doesn't link to the previous entry", ErrBrokenChain, line)
+		}
+
+		chain.Entries = append(chain.Entries, &entry)
+		chain.Head = hashLine(scanner.Bytes())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read audit log, %w", err)
+	}
+
+	return chain, nil
+}
+
+// hashLine returns the hex encoded hash of the entry line
+func hashLine(line []byte) string {
+	hash := sha256.Sum256(line)
+
+	return hex.EncodeToString(hash[:])
+}
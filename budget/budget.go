@@ -0,0 +1,190 @@
+package budget
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+const (
+	hour = time.Hour
+	day  = 24 * time.Hour
+)
+
+// Config is the registrar fee budget configuration.
+// All limits are denominated in the fee denomination (ugnot),
+// and a limit of 0 means there is no limit
+type Config struct {
+	// Hourly is the maximum amount spent in any rolling hour
+	Hourly int64 `json:"hourly"`
+
+	// Daily is the maximum amount spent in any rolling day
+	Daily int64 `json:"daily"`
+
+	// PerDeployer is the maximum amount spent on the tokens
+	// of a single deployer address, in any rolling day
+	PerDeployer int64 `json:"perDeployer"`
+}
+
+// ExceededError is the error returned when a registration
+// would exceed one of the configured budgets
+type ExceededError struct {
+	Budget string // the exceeded budget
+	Limit  int64  // the budget limit
+	Spent  int64  // the amount already spent in the budget window
+	Amount int64  // the amount that was requested
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf(
+		"%s budget exceeded: spent %d of %d, requested %d",
+		e.Budget,
+		e.Spent,
+		e.Limit,
+		e.Amount,
+	)
+}
+
+// Summary is the spend accounting overview
+type Summary struct {
+	Limits      Config           `json:"limits"`
+	PerDeployer map[string]int64 `json:"perDeployer"`
+
+	Hourly int64 `json:"hourly"`
+	Daily  int64 `json:"daily"`
+	Total  int64 `json:"total"`
+}
+
+// Tracker keeps track of the fees spent by the registrar,
+// and enforces the configured budgets
+type Tracker struct {
+	// spends are the spends within the longest budget window,
+	// in chronological order
+	spends []*ledger.Spend
+
+	cfg   Config
+	total int64
+
+	mux sync.RWMutex
+}
+
+// NewTracker creates a new budget tracker, using the
+// previously persisted spends
+func NewTracker(cfg Config, spends []*ledger.Spend) *Tracker {
+	t := &Tracker{
+		cfg:    cfg,
+		spends: make([]*ledger.Spend, 0, len(spends)),
+	}
+
+	for _, spend := range spends {
+		t.Record(spend)
+	}
+
+	return t
+}
+
+// Check verifies the given amount can be spent
+// on a token of the given deployer, at the given time
+func (t *Tracker) Check(deployer string, amount int64, now time.Time) error {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	var (
+		hourly    = t.spentSince(now.Add(-hour), "")
+		daily     = t.spentSince(now.Add(-day), "")
+		byAddress = t.spentSince(now.Add(-day), deployer)
+	)
+
+	switch {
+	case exceeds(t.cfg.Hourly, hourly, amount):
+		return &ExceededError{Budget: "hourly", Limit: t.cfg.Hourly, Spent: hourly, Amount: amount}
+	case exceeds(t.cfg.Daily, daily, amount):
+		return &ExceededError{Budget: "daily", Limit: t.cfg.Daily, Spent: daily, Amount: amount}
+	case exceeds(t.cfg.PerDeployer, byAddress, amount):
+		return &ExceededError{Budget: "deployer", Limit: t.cfg.PerDeployer, Spent: byAddress, Amount: amount}
+	default:
+		return nil
+	}
+}
+
+// Record records the given spend
+func (t *Tracker) Record(spend *ledger.Spend) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.total += spend.Amount
+
+	// Keep the spends sorted by time
+	index := len(t.spends)
+	for index > 0 && t.spends[index-1].Time.After(spend.Time) {
+		index--
+	}
+
+	t.spends = append(t.spends, nil)
+	copy(t.spends[index+1:], t.spends[index:])
+	t.spends[index] = spend
+
+	t.prune(spend.Time)
+}
+
+// Summary returns the spend accounting overview, at the given time
+func (t *Tracker) Summary(now time.Time) Summary {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	summary := Summary{
+		Limits:      t.cfg,
+		PerDeployer: make(map[string]int64),
+		Hourly:      t.spentSince(now.Add(-hour), ""),
+		Daily:       t.spentSince(now.Add(-day), ""),
+		Total:       t.total,
+	}
+
+	for _, spend := range t.spends {
+		if spend.Time.After(now.Add(-day)) {
+			summary.PerDeployer[spend.Deployer] += spend.Amount
+		}
+	}
+
+	return summary
+}
+
+// spentSince returns the amount spent after the given time,
+// optionally filtered by the deployer address
+func (t *Tracker) spentSince(since time.Time, deployer string) int64 {
+	var spent int64
+
+	for i := len(t.spends) - 1; i >= 0; i-- {
+		spend := t.spends[i]
+
+		if !spend.Time.After(since) {
+			break
+		}
+
+		if deployer == "" || spend.Deployer == deployer {
+			spent += spend.Amount
+		}
+	}
+
+	return spent
+}
+
+// prune drops the spends that are outside
+// of the longest budget window
+func (t *Tracker) prune(now time.Time) {
+	cutoff := now.Add(-day)
+
+	index := 0
+	for index < len(t.spends) && !t.spends[index].Time.After(cutoff) {
+		index++
+	}
+
+	t.spends = t.spends[index:]
+}
+
+// exceeds checks if spending the amount would go over the limit
+func exceeds(limit, spent, amount int64) bool {
+	return limit > 0 && spent+amount > limit
+}
@@ -0,0 +1,119 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestTracker_Check(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now = time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+
+		spends = []*ledger.Spend{
+			{Time: now.Add(-30 * time.Hour), Deployer: "g1alice", Amount: 1000}, // outside of all windows
+			{Time: now.Add(-5 * time.Hour), Deployer: "g1alice", Amount: 300},
+			{Time: now.Add(-30 * time.Minute), Deployer: "g1bob", Amount: 200},
+		}
+	)
+
+	testTable := []struct {
+		name           string
+		deployer       string
+		expectedBudget string
+		cfg            Config
+		amount         int64
+	}{
+		{
+			"unlimited",
+			"g1alice",
+			"",
+			Config{},
+			1_000_000,
+		},
+		{
+			"within all budgets",
+			"g1alice",
+			"",
+			Config{Hourly: 300, Daily: 600, PerDeployer: 400},
+			100,
+		},
+		{
+			"hourly exceeded",
+			"g1alice",
+			"hourly",
+			Config{Hourly: 250},
+			100,
+		},
+		{
+			"daily exceeded",
+			"g1alice",
+			"daily",
+			Config{Daily: 550},
+			100,
+		},
+		{
+			"deployer exceeded",
+			"g1alice",
+			"deployer",
+			Config{PerDeployer: 350},
+			100,
+		},
+		{
+			"other deployer within budget",
+			"g1carol",
+			"",
+			Config{PerDeployer: 350},
+			100,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			tracker := NewTracker(testCase.cfg, spends)
+
+			err := tracker.Check(testCase.deployer, testCase.amount, now)
+			if testCase.expectedBudget == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var exceededErr *ExceededError
+
+			require.ErrorAs(t, err, &exceededErr)
+			assert.Equal(t, testCase.expectedBudget, exceededErr.Budget)
+		})
+	}
+}
+
+func TestTracker_Summary(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	tracker := NewTracker(Config{Daily: 10_000}, nil)
+
+	// Record the spends out of order
+	tracker.Record(&ledger.Spend{Time: now.Add(-10 * time.Minute), Deployer: "g1bob", Amount: 200})
+	tracker.Record(&ledger.Spend{Time: now.Add(-2 * time.Hour), Deployer: "g1alice", Amount: 300})
+	tracker.Record(&ledger.Spend{Time: now.Add(-48 * time.Hour), Deployer: "g1alice", Amount: 1000})
+
+	summary := tracker.Summary(now)
+
+	assert.Equal(t, Config{Daily: 10_000}, summary.Limits)
+	assert.EqualValues(t, 200, summary.Hourly)
+	assert.EqualValues(t, 500, summary.Daily)
+	assert.EqualValues(t, 1500, summary.Total)
+	assert.Equal(t, map[string]int64{"g1alice": 300, "g1bob": 200}, summary.PerDeployer)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// defaultIndexer is the default indexer JSON-RPC URL
+const defaultIndexer = "http://127.0.0.1:8546"
+
+var (
+	errInvalidAuditArgs = errors.New("a single audit log path is required")
+	errAuditMismatch    = errors.New("audit entries don't match the committed transactions")
+	errAuditLookup      = errors.New("audit entries unable to be looked up")
+	errTxNotCommitted   = errors.New("transaction not committed")
+)
+
+// txLookup fetches the committed tx by its base64 encoded
+// hash, returning errTxNotCommitted if it is not committed
+type txLookup func(hash string) (*types.TxResult, error)
+
+type auditVerifyCfg struct {
+	indexer string
+	offline bool
+}
+
+// newAuditCmd creates the audit log command
+func newAuditCmd() *ffcli.Command {
+	return &ffcli.Command{
+		Name:       "audit",
+		ShortUsage: "audit <subcommand> [flags] [<arg>...]",
+		ShortHelp:  "Inspects the signed tx audit log",
+		FlagSet:    flag.NewFlagSet("audit", flag.ExitOnError),
+		Subcommands: []*ffcli.Command{
+			newAuditVerifyCmd(),
+		},
+		Exec: func(_ context.Context, _ []string) error {
+			return flag.ErrHelp
+		},
+	}
+}
+
+// newAuditVerifyCmd creates the audit log verify command
+func newAuditVerifyCmd() *ffcli.Command {
+	cfg := &auditVerifyCfg{}
+
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "verify",
+		ShortUsage: "audit verify [flags] <audit-log>",
+		ShortHelp:  "Verifies the audit log hash chain, and the committed txs",
+		LongHelp: "Verifies the hash chain of the audit log, and cross-checks every entry " +
+			"against the transaction with the same hash, committed on chain and indexed by the indexer",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, args []string) error {
+			return cfg.exec(ctx, args, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the audit verify command flags
+func (c *auditVerifyCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.indexer,
+		"indexer",
+		defaultIndexer,
+		"the JSON-RPC URL of the indexer, the entries are cross-checked against its indexed txs",
+	)
+
+	fs.BoolVar(
+		&c.offline,
+		"offline",
+		false,
+		"only verify the hash chain, without cross-checking the entries on chain",
+	)
+}
+
+// exec executes the audit verify command
+func (c *auditVerifyCfg) exec(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errInvalidAuditArgs
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("unable to open audit log, %w", err)
+	}
+
+	defer file.Close()
+
+	chain, err := audit.Verify(file)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "hash chain verified, %d entries, head %s\n", len(chain.Entries), chain.Head)
+
+	if c.offline {
+		return nil
+	}
+
+	return verifyEntries(chain.Entries, indexerLookup(ctx, c.indexer), out)
+}
+
+// verifyEntries cross-checks the audit entries against the committed txs.
+// The entries that are unable to be looked up are reported separately
+// from the ones that are not committed, and fail the verification
+func verifyEntries(entries []*audit.Entry, lookup txLookup, out io.Writer) error {
+	var committed, uncommitted, mismatched, failed int
+
+	for _, entry := range entries {
+		err := crossCheck(lookup, entry)
+
+		switch {
+		case err == nil:
+			committed++
+		case errors.Is(err, audit.ErrMismatch):
+			mismatched++
+
+			_, _ = fmt.Fprintf(out, "entry %d (tx %s): %s\n", entry.Seq, entry.TxHash, err)
+		case errors.Is(err, errTxNotCommitted):
+			// The signed txs that were rejected
+			// on broadcast are never committed
+			uncommitted++
+
+			_, _ = fmt.Fprintf(out, "entry %d (tx %s): not committed\n", entry.Seq, entry.TxHash)
+		default:
+			failed++
+
+			_, _ = fmt.Fprintf(out, "entry %d (tx %s): lookup failed, %s\n", entry.Seq, entry.TxHash, err)
+		}
+	}
+
+	_, _ = fmt.Fprintf(
+		out,
+		"%d committed, %d not committed, %d mismatched, %d lookups failed\n",
+		committed,
+		uncommitted,
+		mismatched,
+		failed,
+	)
+
+	if mismatched > 0 {
+		return fmt.Errorf("%w, %d entries", errAuditMismatch, mismatched)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w, %d entries", errAuditLookup, failed)
+	}
+
+	return nil
+}
+
+// crossCheck compares the audit entry
+// with the committed tx of the same hash
+func crossCheck(lookup txLookup, entry *audit.Entry) error {
+	result, err := lookup(entry.TxHash)
+	if err != nil {
+		return err
+	}
+
+	var tx std.Tx
+
+	if err := amino.Unmarshal(result.Tx, &tx); err != nil {
+		return fmt.Errorf("unable to unmarshal transaction, %w", err)
+	}
+
+	return audit.Compare(entry, &tx)
+}
+
+// indexerLookup creates the tx lookup using the
+// indexer getTxResultByHash JSON-RPC method
+func indexerLookup(ctx context.Context, url string) txLookup {
+	return func(hash string) (*types.TxResult, error) {
+		body, err := json.Marshal(spec.NewJSONRequest(1, "getTxResultByHash", []any{hash}))
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode request, %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create request, %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch transaction, %w", err)
+		}
+
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to fetch transaction, status %s", res.Status)
+		}
+
+		var response spec.BaseJSONResponse
+
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			return nil, fmt.Errorf("unable to decode response, %w", err)
+		}
+
+		if response.Error != nil {
+			return nil, fmt.Errorf("unable to fetch transaction, %s", response.Error.Message)
+		}
+
+		// The transactions that are not indexed have no result
+		if response.Result == nil {
+			return nil, errTxNotCommitted
+		}
+
+		encoded, ok := response.Result.(string)
+		if !ok {
+			return nil, errors.New("unexpected transaction result format")
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode transaction result, %w", err)
+		}
+
+		var result types.TxResult
+
+		if err := amino.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal transaction result, %w", err)
+		}
+
+		return &result, nil
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestAudit_VerifyEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := []*audit.Entry{
+		{Seq: 1, TxHash: "rejected"},
+		{Seq: 2, TxHash: "unreachable"},
+	}
+
+	lookup := func(hash string) (*types.TxResult, error) {
+		if hash == "rejected" {
+			return nil, errTxNotCommitted
+		}
+
+		return nil, errors.New("connection refused")
+	}
+
+	var out bytes.Buffer
+
+	// Make sure the lookup errors are not reported as not committed
+	err := verifyEntries(entries, lookup, &out)
+	require.ErrorIs(t, err, errAuditLookup)
+
+	assert.Contains(t, out.String(), "entry 1 (tx rejected): not committed\n")
+	assert.Contains(t, out.String(), "entry 2 (tx unreachable): lookup failed, connection refused\n")
+	assert.Contains(t, out.String(), "0 committed, 1 not committed, 0 mismatched, 1 lookups failed\n")
+}
+
+func TestAudit_IndexerLookup(t *testing.T) {
+	t.Parallel()
+
+	indexed := &types.TxResult{Height: 10, Tx: []byte("tx")}
+
+	encoded, err := encode.PrepareValue(indexed)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request spec.BaseJSONRequest
+
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&request)) {
+			return
+		}
+
+		assert.Equal(t, "getTxResultByHash", request.Method)
+
+		response := spec.NewJSONResponse(request.ID, nil, nil)
+
+		switch request.Params[0] {
+		case "indexed":
+			response.Result = encoded
+		case "failing":
+			response.Error = spec.NewJSONError("storage closed", spec.ServerErrorCode)
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	lookup := indexerLookup(context.Background(), server.URL)
+
+	result, err := lookup("indexed")
+	require.NoError(t, err)
+
+	assert.Equal(t, indexed.Height, result.Height)
+	assert.Equal(t, indexed.Tx, result.Tx)
+
+	// Make sure the unknown tx is reported as not committed
+	_, err = lookup("unknown")
+	assert.ErrorIs(t, err, errTxNotCommitted)
+
+	// Make sure the lookup error is not reported as not committed
+	_, err = lookup("failing")
+	require.Error(t, err)
+
+	assert.NotErrorIs(t, err, errTxNotCommitted)
+	assert.Contains(t, err.Error(), "storage closed")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// controller is the runtime registrations control
+type controller interface {
+	// Pause pauses the registrations, without stopping the detection
+	Pause()
+
+	// Resume resumes the paused registrations
+	Resume()
+}
+
+// controlSignals pauses the registrations on SIGUSR1,
+// and resumes them on SIGUSR2, until the context is cancelled
+func controlSignals(c controller, logger *zap.Logger) waitFunc {
+	return func(ctx context.Context) error {
+		signals := make(chan os.Signal, 1)
+
+		signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case sig := <-signals:
+				logger.Info("received control signal", zap.String("signal", sig.String()))
+
+				if sig == syscall.SIGUSR1 {
+					c.Pause()
+
+					continue
+				}
+
+				c.Resume()
+			}
+		}
+	}
+}
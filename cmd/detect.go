@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+	"github.com/gnolang/tx-indexer/registrar"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+const (
+	verdictRegister    = "register"
+	verdictReview      = "review"
+	verdictReject      = "reject"
+	verdictNotDetected = "not_detected"
+)
+
+var errNoSourceFiles = errors.New("no .gno source files provided")
+
+type detectCfg struct {
+	pkgPath          string
+	deployer         string
+	policyPath       string
+	protectedSymbols string
+	format           string
+}
+
+// detectVerdict is the offline detection verdict
+type detectVerdict struct {
+	Token   *ledger.Token `json:"token,omitempty"`
+	Verdict string        `json:"verdict"`
+	Reasons []string      `json:"reasons"`
+}
+
+// newDetectCmd creates the offline token detect command
+func newDetectCmd() *ffcli.Command {
+	cfg := &detectCfg{}
+
+	fs := flag.NewFlagSet("detect", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "detect",
+		ShortUsage: "detect [flags] <package-dir | file.gno>...",
+		ShortHelp:  "Checks if a token package would be auto-registered",
+		LongHelp: "Runs the token detection, metadata extraction, registration policy " +
+			"and risk scoring on a local package, without a chain, and prints the verdict",
+		FlagSet: fs,
+		Exec: func(_ context.Context, args []string) error {
+			return cfg.exec(args, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the detect command flags
+func (c *detectCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.pkgPath,
+		"pkg-path",
+		"",
+		"the package path of the token, read from gno.mod by default",
+	)
+
+	fs.StringVar(
+		&c.deployer,
+		"deployer",
+		"",
+		"the deployer address of the token, for the deployer policy rules",
+	)
+
+	fs.StringVar(
+		&c.policyPath,
+		"policy",
+		"",
+		"the path to the JSON registration policy file",
+	)
+
+	fs.StringVar(
+		&c.protectedSymbols,
+		"protected-symbols",
+		"",
+		"the comma-separated token symbols (or names) that new tokens are not allowed to resemble",
+	)
+
+	fs.StringVar(
+		&c.format,
+		"format",
+		formatText,
+		"the output format (text or json)",
+	)
+}
+
+// exec executes the detect command
+func (c *detectCfg) exec(args []string, out io.Writer) error {
+	if c.format != formatText && c.format != formatJSON {
+		return fmt.Errorf("invalid output format %q", c.format)
+	}
+
+	files, modPath, err := readSourceFiles(args)
+	if err != nil {
+		return err
+	}
+
+	pkgPath := c.pkgPath
+	if pkgPath == "" {
+		pkgPath = modPath
+	}
+
+	// Load the registration policy, if any
+	var p registrar.Policy
+
+	if c.policyPath != "" {
+		loaded, err := policy.Load(c.policyPath)
+		if err != nil {
+			return fmt.Errorf("unable to load registration policy, %w", err)
+		}
+
+		p = loaded
+	}
+
+	verdict := detect(
+		files,
+		pkgPath,
+		c.deployer,
+		p,
+		detector.NewImpersonationChecker(splitList(c.protectedSymbols)),
+	)
+
+	if c.format == formatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(verdict)
+	}
+
+	return writeVerdict(out, verdict)
+}
+
+// detect runs the registration pipeline checks on the package source
+func detect(
+	files []detector.File,
+	pkgPath,
+	deployer string,
+	p registrar.Policy,
+	impersonation *detector.ImpersonationChecker,
+) *detectVerdict {
+	detection := detector.Detect(detector.ExportedFuncs(files), files, true)
+
+	if !detection.IsToken() {
+		reasons := make([]string, 0)
+
+		if len(detection.MissingFuncs) > 0 {
+			reasons = append(
+				reasons,
+				fmt.Sprintf("missing GRC20 functions: %s", strings.Join(detection.MissingFuncs, ", ")),
+			)
+		}
+
+		if detection.Token == nil {
+			reasons = append(reasons, "no grc20.NewBanker token metadata found")
+		}
+
+		return &detectVerdict{
+			Verdict: verdictNotDetected,
+			Reasons: reasons,
+		}
+	}
+
+	token := detection.Token
+	token.PkgPath = pkgPath
+	token.Deployer = deployer
+
+	verdict := &detectVerdict{
+		Token:   token,
+		Verdict: verdictRegister,
+		Reasons: make([]string, 0),
+	}
+
+	if token.Risk != nil {
+		for _, finding := range token.Risk.Findings {
+			verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("risk: %s", finding.Message))
+		}
+	}
+
+	// There is no catalog of registered tokens offline,
+	// so the token is only compared to the protected symbols
+	result := registrar.Evaluate(p, impersonation, token, func() ([]*ledger.Token, error) {
+		return nil, nil
+	})
+
+	if result.Reason != "" {
+		verdict.Reasons = append(verdict.Reasons, result.Reason)
+	}
+
+	switch result.Decision {
+	case policy.DecisionReview:
+		verdict.Verdict = verdictReview
+	case policy.DecisionReject:
+		verdict.Verdict = verdictReject
+	}
+
+	return verdict
+}
+
+// writeVerdict writes the verdict as human-readable text
+func writeVerdict(out io.Writer, verdict *detectVerdict) error {
+	w := bufio.NewWriter(out)
+
+	fmt.Fprintf(w, "verdict:  %s\n", verdict.Verdict)
+
+	if token := verdict.Token; token != nil {
+		fmt.Fprintf(w, "pkgPath:  %s\n", token.PkgPath)
+		fmt.Fprintf(w, "kind:     %s\n", token.Kind)
+
+		if token.Kind == ledger.KindGRC721 {
+			fmt.Fprintf(w, "token:    %s (%s)\n", token.Name, token.Symbol)
+		} else {
+			fmt.Fprintf(w, "token:    %s (%s), %d decimals\n", token.Name, token.Symbol, token.Decimals)
+		}
+
+		if token.Risk != nil {
+			fmt.Fprintf(w, "risk:     %d/100", token.Risk.Score)
+
+			if token.Risk.Template != "" {
+				fmt.Fprintf(w, " (closest template %s, similarity %.2f)", token.Risk.Template, token.Risk.Similarity)
+			}
+
+			fmt.Fprintln(w)
+		}
+	}
+
+	if len(verdict.Reasons) > 0 {
+		fmt.Fprintln(w, "reasons:")
+
+		for _, reason := range verdict.Reasons {
+			fmt.Fprintf(w, "  - %s\n", reason)
+		}
+	}
+
+	return w.Flush()
+}
+
+// readSourceFiles reads the .gno source files from the given
+// package directories and files, along with the gno.mod module path, if any
+func readSourceFiles(args []string) ([]detector.File, string, error) {
+	var (
+		files   = make([]detector.File, 0)
+		modPath string
+	)
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to stat %s, %w", arg, err)
+		}
+
+		paths := []string{arg}
+
+		if info.IsDir() {
+			if paths, err = filepath.Glob(filepath.Join(arg, "*.gno")); err != nil {
+				return nil, "", fmt.Errorf("unable to list %s, %w", arg, err)
+			}
+
+			if modPath == "" {
+				modPath = readModulePath(filepath.Join(arg, "gno.mod"))
+			}
+		}
+
+		for _, path := range paths {
+			body, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", fmt.Errorf("unable to read %s, %w", path, err)
+			}
+
+			files = append(files, detector.File{
+				Name: filepath.Base(path),
+				Body: string(body),
+			})
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, "", errNoSourceFiles
+	}
+
+	return files, modPath, nil
+}
+
+// readModulePath reads the module path from the gno.mod file, if any
+func readModulePath(path string) string {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		if module, found := strings.CutPrefix(strings.TrimSpace(line), "module "); found {
+			return strings.Trim(strings.TrimSpace(module), `"`)
+		}
+	}
+
+	return ""
+}
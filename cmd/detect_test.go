@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenSource is a minimal grc20 token package source
+const tokenSource = `package foo
+
+import "gno.land/p/demo/grc/grc20"
+
+var (
+	banker = grc20.NewBanker("Foo", "FOO", 4)
+	token  = banker.Token()
+)
+
+func TotalSupply() uint64                    { return token.TotalSupply() }
+func BalanceOf(owner std.Address) uint64     { return token.BalanceOf(owner) }
+func Allowance(owner, spender std.Address) uint64 { return token.Allowance(owner, spender) }
+func Transfer(to std.Address, amount uint64)      { token.Transfer(to, amount) }
+func Approve(spender std.Address, amount uint64)  { token.Approve(spender, amount) }
+func TransferFrom(from, to std.Address, amount uint64) { token.TransferFrom(from, to, amount) }
+`
+
+// writePackage writes the token package, with its gno.mod, to a temp dir
+func writePackage(t *testing.T, source string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.gno"), []byte(source), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gno.mod"), []byte("module gno.land/r/demo/foo\n"), 0o600))
+
+	return dir
+}
+
+func TestDetect_Exec(t *testing.T) {
+	t.Parallel()
+
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"decimals": {"min": 6}}`), 0o600))
+
+	testTable := []struct {
+		name            string
+		cfg             *detectCfg
+		source          string
+		expectedVerdict string
+		expectedReason  string
+	}{
+		{
+			"registered token",
+			&detectCfg{},
+			tokenSource,
+			verdictRegister,
+			"",
+		},
+		{
+			"rejected by policy",
+			&detectCfg{policyPath: policyPath},
+			tokenSource,
+			verdictReject,
+			"decimals 4 are below the minimum of 6",
+		},
+		{
+			"impersonation",
+			&detectCfg{protectedSymbols: "F00"},
+			tokenSource,
+			verdictReview,
+			`suspected impersonation, token symbol resembles protected symbol "F00"`,
+		},
+		{
+			"not a token",
+			&detectCfg{},
+			"package foo\n\nfunc TotalSupply() uint64 { return 0 }\n",
+			verdictNotDetected,
+			"no grc20.NewBanker token metadata found",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+
+			testCase.cfg.format = formatJSON
+
+			require.NoError(t, testCase.cfg.exec([]string{writePackage(t, testCase.source)}, &out))
+
+			var verdict detectVerdict
+
+			require.NoError(t, json.Unmarshal(out.Bytes(), &verdict))
+
+			assert.Equal(t, testCase.expectedVerdict, verdict.Verdict)
+
+			if testCase.expectedReason != "" {
+				assert.Contains(t, verdict.Reasons, testCase.expectedReason)
+			}
+
+			if verdict.Token != nil {
+				assert.Equal(t, "gno.land/r/demo/foo", verdict.Token.PkgPath)
+			}
+		})
+	}
+}
+
+func TestDetect_NoSources(t *testing.T) {
+	t.Parallel()
+
+	cfg := &detectCfg{format: formatText}
+
+	assert.ErrorIs(t, cfg.exec([]string{t.TempDir()}, &bytes.Buffer{}), errNoSourceFiles)
+}
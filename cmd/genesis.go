@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/registrar"
+	"github.com/gnolang/tx-indexer/rules"
+	"github.com/gnolang/tx-indexer/storage"
+	"github.com/gnolang/tx-indexer/webhook"
+
+	rpcClient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+)
+
+// genesisRemote is the genesis import source
+// for the remote node genesis
+const genesisRemote = "remote"
+
+var errInvalidGenesisArgs = errors.New("a single genesis file path, or \"remote\", is required")
+
+type genesisImportCfg struct {
+	remote   string
+	dbPath   string
+	logLevel string
+
+	targetsPath  string
+	rulesPath    string
+	webhooksPath string
+}
+
+// newGenesisCmd creates the genesis command
+func newGenesisCmd() *ffcli.Command {
+	return &ffcli.Command{
+		Name:       "genesis",
+		ShortUsage: "genesis <subcommand> [flags] [<arg>...]",
+		ShortHelp:  "Manages the genesis packages",
+		FlagSet:    flag.NewFlagSet("genesis", flag.ExitOnError),
+		Subcommands: []*ffcli.Command{
+			newGenesisImportCmd(),
+		},
+		Exec: func(_ context.Context, _ []string) error {
+			return flag.ErrHelp
+		},
+	}
+}
+
+// newGenesisImportCmd creates the genesis import command
+func newGenesisImportCmd() *ffcli.Command {
+	cfg := &genesisImportCfg{}
+
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "import",
+		ShortUsage: fmt.Sprintf("genesis import [flags] <genesis-file | %s>", genesisRemote),
+		ShortHelp:  "Imports the genesis packages into the indexer DB",
+		LongHelp: "Imports the genesis txs as height 0 records, and the registration intents of the tokens " +
+			"they deploy, registered by the indexer on its next start. " +
+			"The genesis is read from the given file, or fetched from the remote node. " +
+			"The genesis is only imported once, and the indexer must be stopped during the import",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, args []string) error {
+			return cfg.exec(ctx, args)
+		},
+	}
+}
+
+// registerFlags registers the genesis import command flags
+func (c *genesisImportCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		defaultRemote,
+		"the JSON-RPC URL of the Gno chain, the remote genesis is fetched from",
+	)
+
+	fs.StringVar(
+		&c.dbPath,
+		"db-path",
+		defaultDBPath,
+		"the absolute path for the indexer DB (embedded)",
+	)
+
+	fs.StringVar(
+		&c.logLevel,
+		"log-level",
+		zap.InfoLevel.String(),
+		"the log level for the CLI output",
+	)
+
+	fs.StringVar(
+		&c.targetsPath,
+		"targets",
+		"",
+		"the path to the JSON registration targets file, the default gnoswap target is used otherwise",
+	)
+
+	fs.StringVar(
+		&c.rulesPath,
+		"rules",
+		"",
+		"the path to the JSON detection rules file. The built-in grc20 detection is used otherwise",
+	)
+
+	fs.StringVar(
+		&c.webhooksPath,
+		"webhooks",
+		"",
+		"the path to the JSON lifecycle webhooks file, the detected tokens are notified to. Disabled by default",
+	)
+}
+
+// exec executes the genesis import command
+func (c *genesisImportCfg) exec(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errInvalidGenesisArgs
+	}
+
+	// Parse the log level
+	logLevel, err := zap.ParseAtomicLevel(c.logLevel)
+	if err != nil {
+		return fmt.Errorf("unable to parse log level, %w", err)
+	}
+
+	cfg := zap.NewDevelopmentConfig()
+	cfg.Level = logLevel
+
+	// Create a new logger
+	logger, err := cfg.Build()
+	if err != nil {
+		return fmt.Errorf("unable to create logger, %w", err)
+	}
+
+	raw, err := readGenesis(ctx, args[0], c.remote)
+	if err != nil {
+		return err
+	}
+
+	txs, err := fetch.ParseGenesisTxs(raw)
+	if err != nil {
+		return fmt.Errorf("unable to parse genesis, %w", err)
+	}
+
+	// Create a DB instance
+	db, err := storage.NewPebble(c.dbPath)
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logger.Error("unable to gracefully close DB", zap.Error(closeErr))
+		}
+	}()
+
+	f, err := c.fetcher(db, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := f.ImportGenesis(txs); err != nil {
+		return fmt.Errorf("unable to import genesis, %w", err)
+	}
+
+	return nil
+}
+
+// fetcher creates the fetcher importing the genesis, with the same token
+// detection as the indexer. The registration intents are saved by the
+// registrar, which is not served, so they are registered on the next start
+func (c *genesisImportCfg) fetcher(db *storage.Pebble, logger *zap.Logger) (*fetch.Fetcher, error) {
+	// Create a TM2 client
+	tm2Client, err := client.NewClient(c.remote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client, %w", err)
+	}
+
+	// Create a TM2 RPC client
+	rpcClient, err := rpcClient.NewHTTPClient(c.remote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create rpc client, %w", err)
+	}
+
+	// Load the registration targets
+	targets, err := loadTargets(c.targetsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := addpkg.New(
+		addpkg.WithTargets(targets),
+		addpkg.WithPackageStorage(db),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create addpkg, %w", err)
+	}
+
+	em := events.NewManager()
+
+	// The registry realms deployed in genesis activate their versions
+	r := registrar.New(
+		db,
+		a,
+		em,
+		registrar.WithLogger(logger.Named("registrar")),
+		registrar.WithUpgrades(registryUpgrades(targets)),
+	)
+
+	fetchOpts := []fetch.Option{
+		fetch.WithLogger(
+			logger.Named("fetcher"),
+		),
+		// The collections are only detected if they can be registered
+		fetch.WithGRC721Detection(hasKind(targets, ledger.KindGRC721)),
+	}
+
+	// Notify the detected tokens, if configured
+	webhookURLs := make([]string, 0)
+
+	if c.webhooksPath != "" {
+		endpoints, err := webhook.LoadEndpoints(c.webhooksPath)
+		if err != nil {
+			return nil, err
+		}
+
+		webhookURLs = endpointURLs(endpoints)
+
+		fetchOpts = append(fetchOpts, fetch.WithOutbox(webhook.NewDispatcher(db, webhook.New(), endpoints)))
+	}
+
+	// Load the detection rules, if any
+	if c.rulesPath != "" {
+		engine, err := rules.New(
+			c.rulesPath,
+			rules.WithLogger(logger.Named("rules")),
+			rules.WithTargets(targetNames(targets)),
+			rules.WithEndpoints(webhookURLs),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load detection rules, %w", err)
+		}
+
+		fetchOpts = append(fetchOpts, fetch.WithRules(engine))
+	}
+
+	return fetch.New(
+		db,
+		tm2Client,
+		*rpcClient,
+		em,
+		r,
+		fetchOpts...,
+	), nil
+}
+
+// readGenesis reads the raw genesis from the given file,
+// or fetches it from the remote node /genesis endpoint
+func readGenesis(ctx context.Context, source, remote string) ([]byte, error) {
+	if source != genesisRemote {
+		raw, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read genesis file, %w", err)
+		}
+
+		return raw, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(remote, "/")+"/genesis", nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create genesis request, %w", err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch remote genesis, %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch remote genesis, status %s", res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
@@ -25,6 +25,10 @@ func main() {
 	// Add the subcommands
 	cmd.Subcommands = []*ffcli.Command{
 		newStartCmd(),
+		newDetectCmd(),
+		newRenderCmd(),
+		newAuditCmd(),
+		newGenesisCmd(),
 		// newResetCmd(),
 		// newRepairCmd(),
 	}
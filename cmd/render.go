@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+)
+
+var errInvalidRenderArgs = errors.New("a single token package path is required")
+
+type renderCfg struct {
+	targetsPath string
+	outDir      string
+}
+
+// newRenderCmd creates the register package render command
+func newRenderCmd() *ffcli.Command {
+	cfg := &renderCfg{}
+
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "render",
+		ShortUsage: "render [flags] <pkgPath>",
+		ShortHelp:  "Previews the register packages deployed for a token",
+		LongHelp: "Renders the register packages deployed for the given token package path, " +
+			"for every configured target, along with the estimated registration fee",
+		FlagSet: fs,
+		Exec: func(_ context.Context, args []string) error {
+			return cfg.exec(args, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the render command flags
+func (c *renderCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.targetsPath,
+		"targets",
+		"",
+		"the path to the JSON registration targets file, the default gnoswap target is used otherwise",
+	)
+
+	fs.StringVar(
+		&c.outDir,
+		"out",
+		"",
+		"the directory the rendered packages are written to, one subdirectory per target",
+	)
+}
+
+// exec executes the render command
+func (c *renderCfg) exec(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errInvalidRenderArgs
+	}
+
+	targets, err := loadTargets(c.targetsPath)
+	if err != nil {
+		return err
+	}
+
+	estimator, err := addpkg.NewEstimator()
+	if err != nil {
+		return fmt.Errorf("unable to create fee estimator, %w", err)
+	}
+
+	packages := make([]*addpkg.RenderedPackage, 0, len(targets))
+	for _, target := range targets {
+		packages = append(packages, target.Render(args[0], ""))
+	}
+
+	fee := addpkg.EstimateRegistration(estimator, packages)
+
+	if err := writePackages(out, packages, fee.GasFee.String(), fee.GasWanted); err != nil {
+		return err
+	}
+
+	if c.outDir == "" {
+		return nil
+	}
+
+	for _, pkg := range packages {
+		// Call targets don't deploy packages
+		if pkg.Mode == addpkg.ModeCall {
+			continue
+		}
+
+		dir := filepath.Join(c.outDir, pkg.Target)
+
+		if err := writePackageDir(dir, pkg); err != nil {
+			return fmt.Errorf("unable to write package of target %s, %w", pkg.Target, err)
+		}
+
+		fmt.Fprintf(out, "written %s\n", dir)
+	}
+
+	return nil
+}
+
+// loadTargets loads the registration targets,
+// defaulting to the gnoswap target
+func loadTargets(path string) ([]*addpkg.Target, error) {
+	if path == "" {
+		return []*addpkg.Target{addpkg.DefaultTarget()}, nil
+	}
+
+	targets, err := addpkg.LoadTargets(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load registration targets, %w", err)
+	}
+
+	return targets, nil
+}
+
+// writePackages writes the rendered packages as human-readable text
+func writePackages(
+	out io.Writer,
+	packages []*addpkg.RenderedPackage,
+	fee string,
+	gasWanted int64,
+) error {
+	w := bufio.NewWriter(out)
+
+	fmt.Fprintf(w, "fee:      %s (gas wanted %d)\n", fee, gasWanted)
+
+	for _, pkg := range packages {
+		fmt.Fprintf(w, "\ntarget:   %s\n", pkg.Target)
+		fmt.Fprintf(w, "kind:     %s\n", pkg.Kind)
+
+		if pkg.Mode == addpkg.ModeCall {
+			fmt.Fprintf(w, "call:     %s.%s(%s)\n", pkg.PkgPath, pkg.Func, formatArgs(pkg.Args))
+
+			continue
+		}
+
+		fmt.Fprintf(w, "pkgPath:  %s\n", pkg.PkgPath)
+
+		for _, file := range pkg.Files {
+			fmt.Fprintf(w, "--- %s\n%s\n", file.Name, file.Body)
+		}
+	}
+
+	return w.Flush()
+}
+
+// formatArgs formats the call arguments as quoted strings
+func formatArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, strconv.Quote(arg))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// writePackageDir writes the rendered package as a ready-to-use package directory
+func writePackageDir(dir string, pkg *addpkg.RenderedPackage) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, file := range pkg.Files {
+		if err := os.WriteFile(filepath.Join(dir, file.Name), []byte(file.Body), 0o644); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(
+		filepath.Join(dir, "gno.mod"),
+		[]byte(fmt.Sprintf("module %s\n", pkg.PkgPath)),
+		0o644,
+	)
+}
@@ -6,25 +6,45 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"go.uber.org/zap"
 
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/budget"
 	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/detector"
 	"github.com/gnolang/tx-indexer/events"
 	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/lease"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/memo"
+	"github.com/gnolang/tx-indexer/policy"
+	"github.com/gnolang/tx-indexer/probe"
+	"github.com/gnolang/tx-indexer/registrar"
+	"github.com/gnolang/tx-indexer/rules"
 	"github.com/gnolang/tx-indexer/serve"
 	"github.com/gnolang/tx-indexer/serve/graph"
 	"github.com/gnolang/tx-indexer/storage"
+	"github.com/gnolang/tx-indexer/webhook"
 
 	rpcClient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 )
 
 const (
-	defaultRemote  = "http://127.0.0.1:26657"
-	defaultChainId = "dev"
-	defaultDBPath  = "register-db"
+	defaultRemote       = "http://127.0.0.1:26657"
+	defaultChainId      = "dev"
+	defaultDBPath       = "register-db"
+	defaultDrainTimeout = 30 * time.Second
+
+	// drainGrace is the time the services are given to return,
+	// after the in-flight registration drain timeout
+	drainGrace = 5 * time.Second
 )
 
 type startCfg struct {
@@ -38,6 +58,37 @@ type startCfg struct {
 	maxChunkSize int64
 
 	rateLimit int
+
+	budgetHourly      int64
+	budgetDaily       int64
+	budgetPerDeployer int64
+
+	policyPath       string
+	protectedSymbols string
+	targetsPath      string
+
+	batchWindow time.Duration
+	batchSize   int
+
+	registryVersion string
+	memoTag         string
+
+	eventRulesPath string
+	rulesPath      string
+
+	adminListenAddress string
+	adminToken         string
+
+	leaseFile   string
+	leaseHolder string
+	leaseTTL    time.Duration
+
+	drainTimeout time.Duration
+
+	auditLogPath string
+
+	webhooksPath string
+	lowBalance   int64
 }
 
 // newStartCmd creates the indexer start command
@@ -116,10 +167,170 @@ func (c *startCfg) registerFlags(fs *flag.FlagSet) {
 		0,
 		"the maximum HTTP requests allowed per minute per IP, unlimited by default",
 	)
+
+	fs.Int64Var(
+		&c.budgetHourly,
+		"budget-hourly",
+		0,
+		"the maximum registration fees (ugnot) spent in an hour, unlimited by default",
+	)
+
+	fs.Int64Var(
+		&c.budgetDaily,
+		"budget-daily",
+		0,
+		"the maximum registration fees (ugnot) spent in a day, unlimited by default",
+	)
+
+	fs.Int64Var(
+		&c.budgetPerDeployer,
+		"budget-per-deployer",
+		0,
+		"the maximum registration fees (ugnot) spent on a single deployer's tokens in a day, unlimited by default",
+	)
+
+	fs.StringVar(
+		&c.policyPath,
+		"policy",
+		"",
+		"the path to the JSON registration policy file, every token is registered by default",
+	)
+
+	fs.StringVar(
+		&c.targetsPath,
+		"targets",
+		"",
+		"the path to the JSON registration targets file, the default gnoswap target is used otherwise",
+	)
+
+	fs.DurationVar(
+		&c.batchWindow,
+		"batch-window",
+		0,
+		"the window in which the detected tokens are registered in a single tx, disabled by default",
+	)
+
+	fs.IntVar(
+		&c.batchSize,
+		"batch-size",
+		registrar.DefaultBatchSize,
+		"the maximum number of tokens registered in a single batch tx",
+	)
+
+	fs.StringVar(
+		&c.eventRulesPath,
+		"event-rules",
+		"",
+		"the path to the JSON event rules file, for the tokens created by factory realms",
+	)
+
+	fs.StringVar(
+		&c.rulesPath,
+		"rules",
+		"",
+		"the path to the JSON detection rules file, reloaded on change. The built-in grc20 detection is used otherwise",
+	)
+
+	fs.StringVar(
+		&c.registryVersion,
+		"registry-version",
+		"",
+		"the active registry version of the targets, activated by the registry realm deployments otherwise",
+	)
+
+	fs.StringVar(
+		&c.memoTag,
+		"memo-tag",
+		memo.DefaultTag,
+		"the tag of the registration tx memos, linking the registrations to their tokens",
+	)
+
+	fs.StringVar(
+		&c.protectedSymbols,
+		"protected-symbols",
+		"",
+		"the comma-separated token symbols (or names) that new tokens are not allowed to resemble",
+	)
+
+	fs.StringVar(
+		&c.adminListenAddress,
+		"admin-listen-address",
+		"",
+		"the IP:PORT URL for the admin JSON-RPC server, disabled by default",
+	)
+
+	fs.StringVar(
+		&c.adminToken,
+		"admin-token",
+		os.Getenv("ADMIN_TOKEN"),
+		"the bearer token for the admin JSON-RPC server (ADMIN_TOKEN env var)",
+	)
+
+	fs.StringVar(
+		&c.auditLogPath,
+		"audit-log",
+		"",
+		"the path to the append-only audit log of every signed tx, disabled by default",
+	)
+
+	fs.StringVar(
+		&c.webhooksPath,
+		"webhooks",
+		"",
+		"the path to the JSON lifecycle webhooks file, disabled by default",
+	)
+
+	fs.Int64Var(
+		&c.lowBalance,
+		"low-balance",
+		0,
+		"the signer balance (ugnot) below which the low balance webhook is notified, disabled by default",
+	)
+
+	fs.StringVar(
+		&c.leaseFile,
+		"lease-file",
+		"",
+		"the path to the registration lease file on a shared volume, so only the lease holder registers. Disabled by default",
+	)
+
+	fs.StringVar(
+		&c.leaseHolder,
+		"lease-holder",
+		"",
+		"the unique name of the instance holding the registration lease, the hostname and PID by default",
+	)
+
+	fs.DurationVar(
+		&c.drainTimeout,
+		"drain-timeout",
+		defaultDrainTimeout,
+		"the time the in-flight registrations are drained for on shutdown, the rest is left for the next start",
+	)
+
+	fs.DurationVar(
+		&c.leaseTTL,
+		"lease-ttl",
+		lease.DefaultTTL,
+		"the registration lease duration, after which the standby takes over",
+	)
 }
 
 // exec executes the indexer start command
 func (c *startCfg) exec(ctx context.Context) error {
+	// Make sure the admin server is never served without auth
+	if c.adminListenAddress != "" && c.adminToken == "" {
+		return errors.New("admin token is required for the admin JSON-RPC server")
+	}
+
+	if c.batchSize < 1 {
+		return errors.New("batch size must be at least 1")
+	}
+
+	if c.leaseFile != "" && c.leaseTTL <= 0 {
+		return errors.New("lease TTL must be positive")
+	}
+
 	// Parse the log level
 	logLevel, err := zap.ParseAtomicLevel(c.logLevel)
 	if err != nil {
@@ -141,7 +352,17 @@ func (c *startCfg) exec(ctx context.Context) error {
 		return fmt.Errorf("unable to open storage DB, %w", err)
 	}
 
+	// The storage and the audit log are only closed once every service
+	// returned, since an abandoned registration still writes to them
+	drained := true
+
 	defer func() {
+		if !drained {
+			logger.Warn("services not drained, leaving the DB open")
+
+			return
+		}
+
 		if closeErr := db.Close(); closeErr != nil {
 			logger.Error("unable to gracefully close DB", zap.Error(closeErr))
 		}
@@ -162,23 +383,213 @@ func (c *startCfg) exec(ctx context.Context) error {
 		return fmt.Errorf("unable to create rpc client, %w", err)
 	}
 
-	// Create the fetcher service
-	f := fetch.New(
+	// Load the registration targets
+	targets, err := loadTargets(c.targetsPath)
+	if err != nil {
+		return err
+	}
+
+	addpkgOpts := []addpkg.Option{
+		addpkg.WithTargets(targets),
+		addpkg.WithPackageStorage(db),
+		addpkg.WithMemoTag(c.memoTag),
+	}
+
+	// Open the signed tx audit log, if any
+	if c.auditLogPath != "" {
+		auditLog, err := audit.Open(c.auditLogPath)
+		if err != nil {
+			return fmt.Errorf("unable to open audit log, %w", err)
+		}
+
+		defer func() {
+			if !drained {
+				logger.Warn("services not drained, leaving the audit log open")
+
+				return
+			}
+
+			if closeErr := auditLog.Close(); closeErr != nil {
+				logger.Error("unable to gracefully close audit log", zap.Error(closeErr))
+			}
+		}()
+
+		addpkgOpts = append(addpkgOpts, addpkg.WithAuditLog(auditLog))
+	}
+
+	// Create the registration tx broadcaster
+	a, err := addpkg.New(addpkgOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to create addpkg, %w", err)
+	}
+
+	// Load the registrar spend accounting
+	spends, err := db.GetSpends()
+	if err != nil {
+		return fmt.Errorf("unable to load registrar spends, %w", err)
+	}
+
+	tracker := budget.NewTracker(
+		budget.Config{
+			Hourly:      c.budgetHourly,
+			Daily:       c.budgetDaily,
+			PerDeployer: c.budgetPerDeployer,
+		},
+		spends,
+	)
+
+	registrarOpts := []registrar.Option{
+		registrar.WithLogger(
+			logger.Named("registrar"),
+		),
+		registrar.WithBudget(tracker),
+		registrar.WithImpersonationChecker(
+			detector.NewImpersonationChecker(splitList(c.protectedSymbols)),
+		),
+		registrar.WithProber(probe.New(rpcClient)),
+		registrar.WithBatchWindow(c.batchWindow),
+		registrar.WithBatchSize(c.batchSize),
+		registrar.WithDrainTimeout(c.drainTimeout),
+		registrar.WithUpgrades(registryUpgrades(targets)),
+	}
+
+	// Create the lifecycle webhook dispatcher, if configured.
+	// The webhook rule actions only notify the configured endpoints
+	var (
+		dispatcher  *webhook.Dispatcher
+		webhookURLs = make([]string, 0)
+	)
+
+	if c.webhooksPath != "" {
+		endpoints, err := webhook.LoadEndpoints(c.webhooksPath)
+		if err != nil {
+			return err
+		}
+
+		webhookURLs = endpointURLs(endpoints)
+
+		dispatcher = webhook.NewDispatcher(
+			db,
+			webhook.New(webhook.WithLogger(logger.Named("webhook-dispatcher"))),
+			endpoints,
+		)
+
+		registrarOpts = append(
+			registrarOpts,
+			registrar.WithOutbox(dispatcher),
+			registrar.WithLowBalance(c.lowBalance),
+		)
+	}
+
+	// Create the registration lease elector, if configured
+	var elector *lease.Elector
+
+	if c.leaseFile != "" {
+		holder, err := c.holder()
+		if err != nil {
+			return err
+		}
+
+		elector = lease.New(
+			lease.NewFileLocker(c.leaseFile),
+			holder,
+			lease.WithTTL(c.leaseTTL),
+			lease.WithLogger(logger.Named("lease")),
+		)
+
+		registrarOpts = append(registrarOpts, registrar.WithFence(elector))
+	}
+
+	// Load the registration policy, if any
+	if c.policyPath != "" {
+		p, err := policy.Load(c.policyPath)
+		if err != nil {
+			return fmt.Errorf("unable to load registration policy, %w", err)
+		}
+
+		registrarOpts = append(registrarOpts, registrar.WithPolicy(p))
+	}
+
+	// Create the registrar service
+	r := registrar.New(
 		db,
-		tm2Client,
-		*rpcClient,
+		a,
 		em,
+		registrarOpts...,
+	)
+
+	// Activate the configured registry version, if any
+	if c.registryVersion != "" {
+		if err := r.Upgrade(c.registryVersion); err != nil {
+			return fmt.Errorf("unable to activate registry version, %w", err)
+		}
+	}
+
+	// Type-check the register packages, so template errors
+	// are caught at startup, instead of on the first registration
+	if err := typeCheckTargets(a, db, logger.Named("typecheck")); err != nil {
+		return err
+	}
+
+	// Create the fetcher service
+	fetchOpts := []fetch.Option{
 		fetch.WithLogger(
 			logger.Named("fetcher"),
 		),
 		fetch.WithMaxSlots(c.maxSlots),
 		fetch.WithMaxChunkSize(c.maxChunkSize),
+		// The collections are only detected if they can be registered
+		fetch.WithGRC721Detection(hasKind(targets, ledger.KindGRC721)),
+		// The registration txs are linked to their tokens by their memo
+		fetch.WithBlockProcessor(memo.NewProcessor(c.memoTag, a)),
+	}
+
+	// Notify the detected tokens, if configured
+	if dispatcher != nil {
+		fetchOpts = append(fetchOpts, fetch.WithOutbox(dispatcher))
+	}
+
+	// Load the event rules, if any
+	if c.eventRulesPath != "" {
+		eventRules, err := detector.LoadEventRules(c.eventRulesPath)
+		if err != nil {
+			return err
+		}
+
+		fetchOpts = append(fetchOpts, fetch.WithEventDetector(detector.NewEventDetector(eventRules)))
+	}
+
+	// Load the detection rules, if any
+	var engine *rules.Engine
+
+	if c.rulesPath != "" {
+		engine, err = rules.New(
+			c.rulesPath,
+			rules.WithLogger(logger.Named("rules")),
+			rules.WithTargets(targetNames(targets)),
+			rules.WithEndpoints(webhookURLs),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to load detection rules, %w", err)
+		}
+
+		fetchOpts = append(fetchOpts, fetch.WithRules(engine))
+	}
+
+	f := fetch.New(
+		db,
+		tm2Client,
+		*rpcClient,
+		em,
+		r,
+		fetchOpts...,
 	)
 
 	// Create the JSON-RPC service
 	j := setupJSONRPC(
 		db,
 		em,
+		tracker,
 		logger,
 	)
 
@@ -193,25 +604,101 @@ func (c *startCfg) exec(ctx context.Context) error {
 	hs := serve.NewHTTPServer(mux, c.listenAddress, logger.Named("http-server"))
 
 	// Create a new waiter
-	w := newWaiter(ctx)
+	w := newWaiter(ctx, c.drainTimeout+drainGrace)
 
 	// Add the fetcher service
 	w.add(f.FetchChainData)
 
+	// Add the registrar service. With a registration lease,
+	// it only runs while the lease is held, while the
+	// fetcher keeps indexing on every instance
+	serveRegistrar := r.Serve
+
+	if elector != nil {
+		serveRegistrar = elector.Lead(r.Serve)
+	}
+
+	w.add(serveRegistrar)
+
+	// Add the registrar pause (SIGUSR1) and resume (SIGUSR2) signals
+	w.add(controlSignals(r, logger.Named("control")))
+
+	// Add the lifecycle webhook dispatcher, if any. With a registration
+	// lease, the notifications are only delivered while the lease is held,
+	// and kept in the outbox of the standby instances. The registrar and
+	// the dispatcher share the lease, released once both returned
+	if dispatcher != nil {
+		serveDispatcher := dispatcher.Serve
+
+		if elector != nil {
+			serveDispatcher = elector.Lead(dispatcher.Serve)
+		}
+
+		w.add(serveDispatcher)
+	}
+
+	// Add the rules watcher, if any
+	if engine != nil {
+		w.add(engine.Watch)
+	}
+
 	// Add the JSON-RPC service
 	w.add(hs.Serve)
 
+	// Add the admin JSON-RPC service, if enabled
+	if c.adminListenAddress != "" {
+		adminJ := serve.NewJSONRPC(
+			em,
+			serve.WithLogger(
+				logger.Named("admin-json-rpc"),
+			),
+		)
+
+		// Admin handlers
+		adminJ.RegisterAdminEndpoints(r)
+
+		adminMux := chi.NewMux()
+
+		adminMux.Use(serve.AuthMiddleware(c.adminToken))
+
+		adminMux = adminJ.SetupRoutes(adminMux)
+
+		ahs := serve.NewHTTPServer(adminMux, c.adminListenAddress, logger.Named("admin-http-server"))
+
+		w.add(ahs.Serve)
+	}
+
 	// Wait for the services to stop
+	waitErr := w.wait()
+
+	drained = !errors.Is(waitErr, errDrainTimeout) && !errors.Is(waitErr, registrar.ErrNotDrained)
+
 	return errors.Join(
-		w.wait(),
+		waitErr,
 		logger.Sync(),
 	)
 }
 
+// holder returns the registration lease holder name,
+// which defaults to the hostname and PID of the instance
+func (c *startCfg) holder() (string, error) {
+	if c.leaseHolder != "" {
+		return c.leaseHolder, nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve lease holder, %w", err)
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid()), nil
+}
+
 // setupJSONRPC sets up the JSONRPC instance
 func setupJSONRPC(
 	db *storage.Pebble,
 	em *events.Manager,
+	tracker *budget.Tracker,
 	logger *zap.Logger,
 ) *serve.JSONRPC {
 	j := serve.NewJSONRPC(
@@ -230,9 +717,132 @@ func setupJSONRPC(
 	// Sub handlers
 	j.RegisterSubEndpoints(db)
 
+	// Token handlers
+	j.RegisterTokenEndpoints(db)
+
+	// Spend handlers
+	j.RegisterSpendEndpoints(tracker)
+
 	return j
 }
 
+// typeCheckTargets type-checks the register packages rendered for the
+// latest registered token of each kind, or for a placeholder token if
+// none is registered yet. Inconclusive type-checks, caused by packages
+// that are not indexed yet, are only logged
+func typeCheckTargets(a *addpkg.AddPkg, db *storage.Pebble, logger *zap.Logger) error {
+	tokens, err := db.GetTokens()
+	if err != nil {
+		return fmt.Errorf("unable to load token ledger, %w", err)
+	}
+
+	latest := make(map[ledger.Kind]*ledger.Token)
+
+	for _, token := range tokens {
+		if token.Status != ledger.StatusRegistered {
+			continue
+		}
+
+		kind := token.Kind
+		if kind == "" {
+			kind = ledger.KindGRC20
+		}
+
+		if latest[kind] == nil || token.Height > latest[kind].Height {
+			latest[kind] = token
+		}
+	}
+
+	for _, kind := range []ledger.Kind{ledger.KindGRC20, ledger.KindGRC721} {
+		pkgPath := addpkg.PlaceholderPkgPath
+
+		if token, ok := latest[kind]; ok {
+			pkgPath = token.PkgPath
+			err = a.TypeCheck(kind, pkgPath)
+		} else {
+			err = a.TypeCheckPlaceholder(kind)
+		}
+
+		if err == nil {
+			logger.Info("register packages type-checked", zap.String("kind", string(kind)), zap.String("pkgPath", pkgPath))
+
+			continue
+		}
+
+		var templateErr *addpkg.TemplateError
+		if errors.As(err, &templateErr) && templateErr.Inconclusive() {
+			logger.Warn("unable to type-check register packages", zap.Error(err))
+
+			continue
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// targetNames returns the names of the targets
+func targetNames(targets []*addpkg.Target) []string {
+	names := make([]string, 0, len(targets))
+
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+
+	return names
+}
+
+// endpointURLs returns the URLs of the webhook endpoints
+func endpointURLs(endpoints []*webhook.Endpoint) []string {
+	urls := make([]string, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		urls = append(urls, endpoint.URL)
+	}
+
+	return urls
+}
+
+// hasKind checks if any of the targets
+// registers the tokens of the given kind
+func hasKind(targets []*addpkg.Target, kind ledger.Kind) bool {
+	for _, target := range targets {
+		if target.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registryUpgrades maps the registry realms of the
+// versioned targets to the versions they activate
+func registryUpgrades(targets []*addpkg.Target) map[string]string {
+	upgrades := make(map[string]string)
+
+	for version, registry := range addpkg.Versions(targets) {
+		if registry != "" {
+			upgrades[registry] = version
+		}
+	}
+
+	return upgrades
+}
+
+// splitList splits the comma-separated flag value
+func splitList(value string) []string {
+	list := make([]string, 0)
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
 func NewCORSHandler() func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(
@@ -2,13 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 )
 
+// errDrainTimeout is returned when the services
+// don't stop within the drain timeout, after a shutdown
+var errDrainTimeout = errors.New("services not drained in time")
+
 type waitFunc func(ctx context.Context) error
 
 // waiter is a concept used for waiting on running services
@@ -16,13 +23,17 @@ type waiter struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	drainTimeout time.Duration
+
 	waitFns []waitFunc
 }
 
-// newWaiter creates a new waiter instance
-func newWaiter(ctx context.Context) *waiter {
+// newWaiter creates a new waiter instance. After a shutdown,
+// the services are waited on up to the drain timeout, if any
+func newWaiter(ctx context.Context, drainTimeout time.Duration) *waiter {
 	w := &waiter{
-		waitFns: []waitFunc{},
+		drainTimeout: drainTimeout,
+		waitFns:      []waitFunc{},
 	}
 
 	w.ctx, w.cancel = signal.NotifyContext(
@@ -41,7 +52,8 @@ func (w *waiter) add(fns ...waitFunc) {
 	w.waitFns = append(w.waitFns, fns...)
 }
 
-// wait blocks until all added wait services finish
+// wait blocks until all added wait services finish,
+// or until the drain timeout passes after a shutdown
 func (w *waiter) wait() error {
 	g, ctx := errgroup.WithContext(w.ctx)
 
@@ -62,5 +74,31 @@ func (w *waiter) wait() error {
 		)
 	}
 
-	return g.Wait()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- g.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	if w.drainTimeout <= 0 {
+		return <-done
+	}
+
+	timer := time.NewTimer(w.drainTimeout)
+	defer timer.Stop()
+
+	// The work that is not drained is left persisted,
+	// and is resolved on the next start
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w, after %s", errDrainTimeout, w.drainTimeout)
+	}
 }
@@ -0,0 +1,216 @@
+package detector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+var errInvalidEventRule = errors.New("invalid event rule")
+
+// EventRule matches the events emitted for the tokens
+// that are created without a package deployment, ex. by factory realms
+type EventRule struct {
+	// Type is the matched event type, ex. "TokenCreated"
+	Type string `json:"type"`
+
+	// PkgPath is the realm emitting the event, any realm if empty
+	PkgPath string `json:"pkgPath,omitempty"`
+
+	// PathAttr is the event attribute holding the token package path.
+	// The emitting realm is the token package if empty
+	PathAttr string `json:"pathAttr,omitempty"`
+
+	// KeyAttr is the event attribute holding the token key,
+	// for the tokens kept by key within their package, if any
+	KeyAttr string `json:"keyAttr,omitempty"`
+
+	// NameAttr, SymbolAttr and DecimalsAttr are the event attributes
+	// holding the token metadata, for the keyed tokens
+	NameAttr     string `json:"nameAttr,omitempty"`
+	SymbolAttr   string `json:"symbolAttr,omitempty"`
+	DecimalsAttr string `json:"decimalsAttr,omitempty"`
+}
+
+// EventMatch is a token reference found in the emitted events
+type EventMatch struct {
+	Event   string // the matched event type
+	PkgPath string // the token package path
+	Key     string // the token key within the package, if any
+
+	Name     string
+	Symbol   string
+	Decimals int
+}
+
+// ID returns the token identifier, the package path
+// and the key, if any, in the grc20reg "pkgPath.key" format
+func (m *EventMatch) ID() string {
+	if m.Key == "" {
+		return m.PkgPath
+	}
+
+	return m.PkgPath + "." + m.Key
+}
+
+// gnoEvent is the Gno event emitted by the realms
+type gnoEvent struct {
+	Type    string `json:"type"`
+	PkgPath string `json:"pkg_path"`
+	Attrs   []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"attrs"`
+}
+
+// attr returns the value of the event attribute, if any
+func (e *gnoEvent) attr(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	for _, attr := range e.Attrs {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// EventDetector detects the tokens
+// referenced by the emitted tx events
+type EventDetector struct {
+	rules []EventRule
+}
+
+// NewEventDetector creates a new event detector with the given rules
+func NewEventDetector(rules []EventRule) *EventDetector {
+	return &EventDetector{
+		rules: rules,
+	}
+}
+
+// LoadEventRules loads the event rules from the given JSON file
+func LoadEventRules(path string) ([]EventRule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read event rules file, %w", err)
+	}
+
+	var rules []EventRule
+
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse event rules file, %w", err)
+	}
+
+	for i, rule := range rules {
+		if rule.Type == "" {
+			return nil, fmt.Errorf("%w #%d, missing event type", errInvalidEventRule, i)
+		}
+	}
+
+	return rules, nil
+}
+
+// Detect returns the token references found in the emitted events,
+// in the order they were emitted. Each token is referenced once
+func (d *EventDetector) Detect(events []abci.Event) []*EventMatch {
+	var (
+		matches = make([]*EventMatch, 0)
+		seen    = make(map[string]struct{})
+	)
+
+	for _, event := range events {
+		parsed, ok := parseEvent(event)
+		if !ok {
+			continue
+		}
+
+		for _, rule := range d.rules {
+			match, ok := rule.match(parsed)
+			if !ok {
+				continue
+			}
+
+			if _, exists := seen[match.ID()]; exists {
+				break
+			}
+
+			seen[match.ID()] = struct{}{}
+			matches = append(matches, match)
+
+			break
+		}
+	}
+
+	return matches
+}
+
+// EventTypes returns the types of the emitted Gno events
+func EventTypes(events []abci.Event) []string {
+	types := make([]string, 0, len(events))
+
+	for _, event := range events {
+		if parsed, ok := parseEvent(event); ok {
+			types = append(types, parsed.Type)
+		}
+	}
+
+	return types
+}
+
+// match matches the event against the rule,
+// returning the referenced token, if any
+func (r *EventRule) match(event *gnoEvent) (*EventMatch, bool) {
+	if event.Type != r.Type {
+		return nil, false
+	}
+
+	if r.PkgPath != "" && event.PkgPath != r.PkgPath {
+		return nil, false
+	}
+
+	pkgPath := event.PkgPath
+	if r.PathAttr != "" {
+		pkgPath = event.attr(r.PathAttr)
+	}
+
+	if pkgPath == "" {
+		return nil, false
+	}
+
+	match := &EventMatch{
+		Event:   event.Type,
+		PkgPath: pkgPath,
+		Key:     event.attr(r.KeyAttr),
+		Name:    event.attr(r.NameAttr),
+		Symbol:  event.attr(r.SymbolAttr),
+	}
+
+	if decimals := event.attr(r.DecimalsAttr); decimals != "" {
+		match.Decimals, _ = strconv.Atoi(decimals)
+	}
+
+	return match, true
+}
+
+// parseEvent parses the emitted event as a Gno event
+func parseEvent(event abci.Event) (*gnoEvent, bool) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, false
+	}
+
+	var parsed gnoEvent
+
+	if err := json.Unmarshal(data, &parsed); err != nil || parsed.Type == "" {
+		return nil, false
+	}
+
+	return &parsed, true
+}
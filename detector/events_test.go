@@ -0,0 +1,124 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAttr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type testEvent struct {
+	Type    string     `json:"type"`
+	PkgPath string     `json:"pkg_path"`
+	Func    string     `json:"func"`
+	Attrs   []testAttr `json:"attrs"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+func TestEventDetector_Detect(t *testing.T) {
+	t.Parallel()
+
+	d := NewEventDetector([]EventRule{
+		{
+			Type:         "TokenCreated",
+			PkgPath:      "gno.land/r/demo/grc20factory",
+			KeyAttr:      "symbol",
+			NameAttr:     "name",
+			SymbolAttr:   "symbol",
+			DecimalsAttr: "decimals",
+		},
+		{
+			Type:     "register",
+			PkgPath:  "gno.land/r/demo/grc20reg",
+			PathAttr: "pkgpath",
+			KeyAttr:  "slug",
+		},
+	})
+
+	events := []abci.Event{
+		testEvent{
+			Type:    "TokenCreated",
+			PkgPath: "gno.land/r/demo/grc20factory",
+			Attrs: []testAttr{
+				{Key: "name", Value: "Foo"},
+				{Key: "symbol", Value: "FOO"},
+				{Key: "decimals", Value: "6"},
+			},
+		},
+		// Same event type, emitted by another realm
+		testEvent{
+			Type:    "TokenCreated",
+			PkgPath: "gno.land/r/demo/other",
+			Attrs:   []testAttr{{Key: "symbol", Value: "BAR"}},
+		},
+		testEvent{
+			Type:    "register",
+			PkgPath: "gno.land/r/demo/grc20reg",
+			Attrs: []testAttr{
+				{Key: "pkgpath", Value: "gno.land/r/demo/baz"},
+				{Key: "slug", Value: ""},
+			},
+		},
+		// Duplicate reference
+		testEvent{
+			Type:    "register",
+			PkgPath: "gno.land/r/demo/grc20reg",
+			Attrs:   []testAttr{{Key: "pkgpath", Value: "gno.land/r/demo/baz"}},
+		},
+		// Missing token path
+		testEvent{
+			Type:    "register",
+			PkgPath: "gno.land/r/demo/grc20reg",
+		},
+	}
+
+	matches := d.Detect(events)
+
+	require.Len(t, matches, 2)
+
+	assert.Equal(t, &EventMatch{
+		Event:    "TokenCreated",
+		PkgPath:  "gno.land/r/demo/grc20factory",
+		Key:      "FOO",
+		Name:     "Foo",
+		Symbol:   "FOO",
+		Decimals: 6,
+	}, matches[0])
+	assert.Equal(t, "gno.land/r/demo/grc20factory.FOO", matches[0].ID())
+
+	assert.Equal(t, "gno.land/r/demo/baz", matches[1].PkgPath)
+	assert.Equal(t, "gno.land/r/demo/baz", matches[1].ID())
+}
+
+func TestLoadEventRules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`[
+		{"type": "register", "pkgPath": "gno.land/r/demo/grc20reg", "pathAttr": "pkgpath", "keyAttr": "slug"}
+	]`), 0o600))
+
+	rules, err := LoadEventRules(valid)
+	require.NoError(t, err)
+
+	assert.Equal(t, []EventRule{
+		{Type: "register", PkgPath: "gno.land/r/demo/grc20reg", PathAttr: "pkgpath", KeyAttr: "slug"},
+	}, rules)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`[{"pkgPath": "gno.land/r/demo/grc20reg"}]`), 0o600))
+
+	_, err = LoadEventRules(invalid)
+	assert.ErrorIs(t, err, errInvalidEventRule)
+}
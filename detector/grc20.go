@@ -0,0 +1,129 @@
+package detector
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+
+	mapset "github.com/deckarep/golang-set"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+const (
+	bankerPattern = `grc20\.NewBanker\("([^"]+)",\s*"([^"]+)",\s*(\d+)\)`
+)
+
+var bankerRegex = regexp.MustCompile(bankerPattern)
+
+// grc20Funcs are the functions a GRC20 token package exposes.
+// REF: https://github.com/gnolang/gno/blob/0f2e7551b43c18d27b63cbbadecf07ee48f185f9/examples/gno.land/p/demo/grc/grc20/imustgrc20.gno#L13-L21
+var grc20Funcs = []string{"TotalSupply", "BalanceOf", "Transfer", "Allowance", "Approve", "TransferFrom"}
+
+// Detection is the GRC20 token detection result
+type Detection struct {
+	// Token is the detected token metadata, if any
+	Token *ledger.Token
+
+	// MissingFuncs are the missing GRC20 functions, if any
+	MissingFuncs []string
+}
+
+// IsToken checks if the package is a GRC20 token with metadata
+func (d *Detection) IsToken() bool {
+	return len(d.MissingFuncs) == 0 && d.Token != nil
+}
+
+// DetectGRC20 detects a GRC20 token from the package exported functions
+// and source files, extracting the token metadata and the source risk report
+func DetectGRC20(funcs []string, files []File) *Detection {
+	detection := &Detection{
+		MissingFuncs: missingFuncs(grc20Funcs, funcs),
+	}
+
+	token, found := extractMeta(files)
+	if !found {
+		return detection
+	}
+
+	token.Kind = ledger.KindGRC20
+	token.Risk = AnalyzeRisk(files)
+	detection.Token = token
+
+	return detection
+}
+
+// ExportedFuncs returns the exported package-level functions
+// declared in the package source files, like vm/qfuncs does on chain
+func ExportedFuncs(files []File) []string {
+	var (
+		fset  = token.NewFileSet()
+		funcs = make([]string, 0)
+	)
+
+	for _, file := range sourceFiles(files) {
+		parsed, err := parser.ParseFile(fset, file.Name, file.Body, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+				continue
+			}
+
+			funcs = append(funcs, fn.Name.Name)
+		}
+	}
+
+	return funcs
+}
+
+// missingFuncs returns the required functions
+// missing from the given function list
+func missingFuncs(required, funcs []string) []string {
+	funcSet := mapset.NewSet()
+	for _, fn := range funcs {
+		funcSet.Add(fn)
+	}
+
+	missing := make([]string, 0)
+
+	for _, fn := range required {
+		if !funcSet.Contains(fn) {
+			missing = append(missing, fn)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+// extractMeta extracts the token metadata from the
+// grc20 banker constructor in the package source, if any
+func extractMeta(files []File) (*ledger.Token, bool) {
+	for _, file := range files {
+		matches := bankerRegex.FindStringSubmatch(file.Body)
+		if len(matches) == 0 {
+			continue
+		}
+
+		decimals, err := strconv.Atoi(matches[3])
+		if err != nil {
+			continue
+		}
+
+		return &ledger.Token{
+			Name:     matches[1],
+			Symbol:   matches[2],
+			Decimals: decimals,
+		}, true
+	}
+
+	return nil, false
+}
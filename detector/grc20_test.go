@@ -0,0 +1,77 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectGRC20(t *testing.T) {
+	t.Parallel()
+
+	body, err := templatesFS.ReadFile("templates/foo20.gno")
+	require.NoError(t, err)
+
+	files := []File{{Name: "foo20.gno", Body: string(body)}}
+
+	t.Run("token", func(t *testing.T) {
+		t.Parallel()
+
+		detection := DetectGRC20(ExportedFuncs(files), files)
+
+		require.True(t, detection.IsToken())
+
+		assert.Equal(t, "Foo", detection.Token.Name)
+		assert.Equal(t, "FOO", detection.Token.Symbol)
+		assert.Equal(t, 4, detection.Token.Decimals)
+		assert.NotNil(t, detection.Token.Risk)
+	})
+
+	t.Run("missing functions", func(t *testing.T) {
+		t.Parallel()
+
+		detection := DetectGRC20([]string{"TotalSupply", "BalanceOf", "Transfer"}, files)
+
+		assert.False(t, detection.IsToken())
+		assert.Equal(t, []string{"Allowance", "Approve", "TransferFrom"}, detection.MissingFuncs)
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		t.Parallel()
+
+		detection := DetectGRC20(grc20Funcs, []File{{Name: "foo.gno", Body: "package foo"}})
+
+		assert.False(t, detection.IsToken())
+		assert.Nil(t, detection.Token)
+	})
+}
+
+func TestExportedFuncs(t *testing.T) {
+	t.Parallel()
+
+	files := []File{
+		{
+			Name: "foo.gno",
+			Body: `package foo
+
+type T struct{}
+
+func (T) Method() {}
+
+func Exported() {}
+
+func unexported() {}
+`,
+		},
+		{
+			Name: "foo_test.gno",
+			Body: `package foo
+
+func TestExported() {}
+`,
+		},
+	}
+
+	assert.Equal(t, []string{"Exported"}, ExportedFuncs(files))
+}
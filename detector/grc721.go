@@ -0,0 +1,133 @@
+package detector
+
+import (
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+const (
+	// grc721PkgPath is the GRC721 package NFT collections are built with
+	grc721PkgPath = "gno.land/p/demo/grc/grc721"
+
+	nftPattern = `grc721\.NewBasicNFT\("([^"]+)",\s*"([^"]+)"\)`
+)
+
+var nftRegex = regexp.MustCompile(nftPattern)
+
+// grc721Funcs are the functions a GRC721 collection package exposes.
+// REF: https://github.com/gnolang/gno/blob/0f2e7551b43c18d27b63cbbadecf07ee48f185f9/examples/gno.land/p/demo/grc/grc721/igrc721.gno
+var grc721Funcs = []string{
+	"BalanceOf",
+	"OwnerOf",
+	"TransferFrom",
+	"Approve",
+	"SetApprovalForAll",
+	"GetApproved",
+	"IsApprovedForAll",
+}
+
+// DetectGRC721 detects a GRC721 collection from the package exported
+// functions and source files, extracting the collection metadata.
+// The package is required to import the grc721 package
+func DetectGRC721(funcs []string, files []File) *Detection {
+	detection := &Detection{
+		MissingFuncs: missingFuncs(grc721Funcs, funcs),
+	}
+
+	if !importsPackage(files, grc721PkgPath) {
+		return detection
+	}
+
+	collection, found := extractCollectionMeta(files)
+	if !found {
+		return detection
+	}
+
+	detection.Token = collection
+
+	return detection
+}
+
+// Detect detects a GRC20 token, or a GRC721 collection if enabled,
+// from the package exported functions and source files.
+// The GRC20 detection result is returned if neither is found
+func Detect(funcs []string, files []File, grc721 bool) *Detection {
+	detection := DetectGRC20(funcs, files)
+	if detection.IsToken() || !grc721 {
+		return detection
+	}
+
+	if collection := DetectGRC721(funcs, files); collection.IsToken() {
+		return collection
+	}
+
+	return detection
+}
+
+// importsPackage checks if any of the package
+// source files imports the given package
+func importsPackage(files []File, pkgPath string) bool {
+	for _, path := range Imports(files) {
+		if path == pkgPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Imports returns the packages imported by the
+// package source files, in the order they are imported
+func Imports(files []File) []string {
+	var (
+		fset    = token.NewFileSet()
+		imports = make([]string, 0)
+		seen    = make(map[string]struct{})
+	)
+
+	for _, file := range sourceFiles(files) {
+		parsed, err := parser.ParseFile(fset, file.Name, file.Body, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+
+		for _, spec := range parsed.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := seen[path]; ok {
+				continue
+			}
+
+			seen[path] = struct{}{}
+			imports = append(imports, path)
+		}
+	}
+
+	return imports
+}
+
+// extractCollectionMeta extracts the collection metadata from the
+// grc721 basic NFT constructor in the package source, if any
+func extractCollectionMeta(files []File) (*ledger.Token, bool) {
+	for _, file := range files {
+		matches := nftRegex.FindStringSubmatch(file.Body)
+		if len(matches) == 0 {
+			continue
+		}
+
+		return &ledger.Token{
+			Kind:   ledger.KindGRC721,
+			Name:   matches[1],
+			Symbol: matches[2],
+		}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,121 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+const nftSource = `package nft
+
+import (
+	"std"
+
+	"gno.land/p/demo/grc/grc721"
+)
+
+var collection = grc721.NewBasicNFT("Gnome Punks", "GPUNK")
+
+func BalanceOf(user std.Address) uint64 { return 0 }
+
+func OwnerOf(tid grc721.TokenID) std.Address { return "" }
+
+func TransferFrom(from, to std.Address, tid grc721.TokenID) {}
+
+func Approve(user std.Address, tid grc721.TokenID) {}
+
+func SetApprovalForAll(user std.Address, approved bool) {}
+
+func GetApproved(tid grc721.TokenID) std.Address { return "" }
+
+func IsApprovedForAll(owner, user std.Address) bool { return false }
+`
+
+func TestDetectGRC721(t *testing.T) {
+	t.Parallel()
+
+	files := []File{{Name: "nft.gno", Body: nftSource}}
+
+	t.Run("collection", func(t *testing.T) {
+		t.Parallel()
+
+		detection := DetectGRC721(ExportedFuncs(files), files)
+
+		require.True(t, detection.IsToken())
+
+		assert.Equal(t, ledger.KindGRC721, detection.Token.Kind)
+		assert.Equal(t, "Gnome Punks", detection.Token.Name)
+		assert.Equal(t, "GPUNK", detection.Token.Symbol)
+		assert.Zero(t, detection.Token.Decimals)
+		assert.Nil(t, detection.Token.Risk)
+	})
+
+	t.Run("missing functions", func(t *testing.T) {
+		t.Parallel()
+
+		detection := DetectGRC721([]string{"BalanceOf", "OwnerOf", "TransferFrom"}, files)
+
+		assert.False(t, detection.IsToken())
+		assert.Equal(
+			t,
+			[]string{"Approve", "GetApproved", "IsApprovedForAll", "SetApprovalForAll"},
+			detection.MissingFuncs,
+		)
+	})
+
+	t.Run("missing grc721 import", func(t *testing.T) {
+		t.Parallel()
+
+		// The collection shares the function set, but is not built with grc721
+		body := `package nft
+
+var collection = grc721.NewBasicNFT("Gnome Punks", "GPUNK")
+`
+
+		detection := DetectGRC721(grc721Funcs, []File{{Name: "nft.gno", Body: body}})
+
+		assert.False(t, detection.IsToken())
+		assert.Nil(t, detection.Token)
+	})
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	grc20Body, err := templatesFS.ReadFile("templates/foo20.gno")
+	require.NoError(t, err)
+
+	var (
+		grc20Files  = []File{{Name: "foo20.gno", Body: string(grc20Body)}}
+		grc721Files = []File{{Name: "nft.gno", Body: nftSource}}
+	)
+
+	t.Run("grc20 token", func(t *testing.T) {
+		t.Parallel()
+
+		detection := Detect(ExportedFuncs(grc20Files), grc20Files, true)
+
+		require.True(t, detection.IsToken())
+		assert.Equal(t, ledger.KindGRC20, detection.Token.Kind)
+	})
+
+	t.Run("grc721 collection", func(t *testing.T) {
+		t.Parallel()
+
+		detection := Detect(ExportedFuncs(grc721Files), grc721Files, true)
+
+		require.True(t, detection.IsToken())
+		assert.Equal(t, ledger.KindGRC721, detection.Token.Kind)
+	})
+
+	t.Run("grc721 detection disabled", func(t *testing.T) {
+		t.Parallel()
+
+		detection := Detect(ExportedFuncs(grc721Files), grc721Files, false)
+
+		assert.False(t, detection.IsToken())
+	})
+}
@@ -0,0 +1,156 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// confusables maps the common lookalike characters
+// to their (lowercase) Latin skeleton
+var confusables = map[rune]string{
+	// Uppercase Greek and Cyrillic, that differ from their lowercase lookalikes
+	'Α': "a", 'Β': "b", 'Ε': "e", 'Ζ': "z", 'Η': "h", 'Ι': "l", 'Κ': "k",
+	'Μ': "m", 'Ν': "n", 'Ο': "o", 'Ρ': "p", 'Τ': "t", 'Υ': "y", 'Χ': "x",
+	'І': "l", 'Ј': "j", 'Ѕ': "s",
+	// Cyrillic
+	'а': "a", 'в': "b", 'е': "e", 'ё': "e", 'к': "k", 'м': "m", 'н': "h",
+	'о': "o", 'р': "p", 'с': "c", 'т': "t", 'у': "y", 'х': "x", 'ѕ': "s",
+	'і': "l", 'ї': "l", 'ј': "j", 'ԁ': "d", 'ԛ': "q", 'ԝ': "w", 'ɡ': "g",
+	// Greek
+	'α': "a", 'β': "b", 'ε': "e", 'η': "n", 'ι': "l", 'κ': "k", 'ν': "v",
+	'ο': "o", 'ρ': "p", 'τ': "t", 'υ': "u", 'χ': "x", 'ω': "w",
+	// Latin and digit lookalikes
+	'i': "l", 'ı': "l", '|': "l", '1': "l", '0': "o", '5': "s",
+}
+
+// multiConfusables are the character sequences
+// that look like a single character
+var multiConfusables = strings.NewReplacer(
+	"rn", "m",
+	"vv", "w",
+)
+
+// Impersonation is a suspected impersonation match
+type Impersonation struct {
+	Field  string // the matched token field (name or symbol)
+	Target string // the impersonated token or protected symbol
+}
+
+func (i *Impersonation) String() string {
+	return fmt.Sprintf("token %s resembles %s", i.Field, i.Target)
+}
+
+// ImpersonationChecker compares new tokens against the existing
+// token catalog and the protected symbols, to flag impersonations
+type ImpersonationChecker struct {
+	// protected maps the skeletons to the protected symbols
+	protected map[string]string
+}
+
+// NewImpersonationChecker creates a new impersonation checker,
+// using the given protected symbols (or names)
+func NewImpersonationChecker(protected []string) *ImpersonationChecker {
+	c := &ImpersonationChecker{
+		protected: make(map[string]string, len(protected)),
+	}
+
+	for _, symbol := range protected {
+		if s := Skeleton(symbol); s != "" {
+			c.protected[s] = symbol
+		}
+	}
+
+	return c
+}
+
+// Check checks if the token resembles a protected symbol,
+// or a different token from the catalog
+func (c *ImpersonationChecker) Check(token *ledger.Token, catalog []*ledger.Token) (*Impersonation, bool) {
+	var (
+		name   = Skeleton(token.Name)
+		symbol = Skeleton(token.Symbol)
+	)
+
+	// Check the protected symbols
+	for _, field := range []struct {
+		name     string
+		skeleton string
+	}{
+		{"symbol", symbol},
+		{"name", name},
+	} {
+		if target, ok := c.protected[field.skeleton]; ok && field.skeleton != "" {
+			return &Impersonation{
+				Field:  field.name,
+				Target: fmt.Sprintf("protected symbol %q", target),
+			}, true
+		}
+	}
+
+	// Check the existing tokens
+	for _, existing := range catalog {
+		if existing.PkgPath == token.PkgPath {
+			continue
+		}
+
+		if symbol != "" && symbol == Skeleton(existing.Symbol) {
+			return &Impersonation{
+				Field:  "symbol",
+				Target: fmt.Sprintf("%q of %s", existing.Symbol, existing.PkgPath),
+			}, true
+		}
+
+		if name != "" && name == Skeleton(existing.Name) {
+			return &Impersonation{
+				Field:  "name",
+				Target: fmt.Sprintf("%q of %s", existing.Name, existing.PkgPath),
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// Skeleton normalizes the given name or symbol for comparison.
+// It is case-insensitive, strips the diacritics, separators and
+// whitespace, and maps the confusable characters to their Latin lookalikes
+func Skeleton(s string) string {
+	var b strings.Builder
+
+	for _, r := range norm.NFKD.String(s) {
+		// Drop the diacritics left over after the decomposition
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		// Uppercase lookalikes are mapped before lowercasing,
+		// since their lowercase forms can resemble other letters
+		if mapped, ok := confusables[r]; ok {
+			b.WriteString(mapped)
+
+			continue
+		}
+
+		r = unicode.ToLower(r)
+
+		if mapped, ok := confusables[r]; ok {
+			b.WriteString(mapped)
+
+			continue
+		}
+
+		// Drop the separators, whitespace and punctuation
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return multiConfusables.Replace(b.String())
+}
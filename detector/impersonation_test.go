@@ -0,0 +1,128 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestSkeleton(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{
+			"case",
+			"GnoSwap",
+			"gnoswap",
+		},
+		{
+			"separators",
+			"Gno Swap",
+			"gno-swap",
+		},
+		{
+			"cyrillic lookalikes",
+			"GNS",
+			"GΝЅ", // Greek Nu, Cyrillic Dze
+		},
+		{
+			"diacritics",
+			"Gnoswap",
+			"Gnöswäp",
+		},
+		{
+			"digit lookalikes",
+			"GNOSWAP",
+			"GN0SWAP",
+		},
+		{
+			"multi character lookalikes",
+			"Gnome",
+			"Gnorne",
+		},
+		{
+			"fullwidth characters",
+			"USDC",
+			"ＵＳＤＣ",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, Skeleton(testCase.a), Skeleton(testCase.b))
+		})
+	}
+}
+
+func TestImpersonationChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	var (
+		checker = NewImpersonationChecker([]string{"GNS", "GNOT"})
+
+		catalog = []*ledger.Token{
+			{PkgPath: "gno.land/r/gnoswap/v1/gns", Name: "Gnoswap", Symbol: "GNS"},
+			{PkgPath: "gno.land/r/demo/usdc", Name: "USD Coin", Symbol: "USDC"},
+		}
+	)
+
+	testTable := []struct {
+		name          string
+		token         *ledger.Token
+		expectedField string
+	}{
+		{
+			"protected symbol",
+			&ledger.Token{PkgPath: "gno.land/r/scam/gnot", Name: "Gno Token", Symbol: "gnot"},
+			"symbol",
+		},
+		{
+			"catalog symbol",
+			&ledger.Token{PkgPath: "gno.land/r/scam/usdc", Name: "Stable", Symbol: "USDС"}, // Cyrillic Es
+			"symbol",
+		},
+		{
+			"catalog name",
+			&ledger.Token{PkgPath: "gno.land/r/scam/swap", Name: "GNO SWAP", Symbol: "SWP"},
+			"name",
+		},
+		{
+			"same package",
+			&ledger.Token{PkgPath: "gno.land/r/demo/usdc", Name: "USD Coin", Symbol: "USDC"},
+			"",
+		},
+		{
+			"unique token",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Name: "Foo", Symbol: "FOO"},
+			"",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			match, found := checker.Check(testCase.token, catalog)
+			if testCase.expectedField == "" {
+				assert.False(t, found)
+
+				return
+			}
+
+			assert.True(t, found)
+			assert.Equal(t, testCase.expectedField, match.Field)
+		})
+	}
+}
@@ -0,0 +1,540 @@
+package detector
+
+import (
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// Risk analysis rules
+const (
+	RuleUnrestrictedMint = "unrestricted_mint"
+	RuleUnrestrictedBurn = "unrestricted_burn"
+	RuleTransferBlocking = "transfer_blocking"
+	RuleOriginCaller     = "origin_caller_in_transfer"
+	RuleExposedLedger    = "exposed_ledger"
+	RuleCustomLedger     = "custom_ledger"
+	RuleUnknownTemplate  = "unknown_template"
+	RuleUnparsableSource = "unparsable_source"
+)
+
+const (
+	maxRiskScore          = 100
+	similarityThreshold   = 0.5 // the minimum similarity to an audited template
+	grc20ImportPathPrefix = "gno.land/p/demo/grc/"
+)
+
+// ruleWeights are the risk score weights of the rules.
+// Each rule contributes to the score once, regardless
+// of the number of findings
+var ruleWeights = map[string]int{
+	RuleUnrestrictedMint: 40,
+	RuleUnrestrictedBurn: 30,
+	RuleTransferBlocking: 25,
+	RuleOriginCaller:     20,
+	RuleExposedLedger:    30,
+	RuleCustomLedger:     20,
+	RuleUnknownTemplate:  10,
+	RuleUnparsableSource: 10,
+}
+
+var (
+	// callerCheckRegex matches the names of the caller assertion helpers,
+	// ex. assertIsAdmin, onlyOwner, AssertCallerIsOwner
+	callerCheckRegex = regexp.MustCompile(`(?i)^(assert|only|require|check|must)\w*(owner|admin|caller|minter|auth|access|role)`)
+
+	// privilegedRegex matches the identifiers of privileged addresses
+	privilegedRegex = regexp.MustCompile(`(?i)(owner|admin|minter|operator)`)
+
+	// blockingRegex matches the identifiers of transfer blocking state
+	blockingRegex = regexp.MustCompile(`(?i)(black|block|ban|frozen|freeze|paus|whitelist|allowlist)`)
+
+	// ledgerTypeRegex matches the privileged grc20 ledger types
+	ledgerTypeRegex = regexp.MustCompile(`(?i)(banker|adminledger|privateledger)`)
+
+	// ledgerVarRegex matches the names of custom balance storage
+	ledgerVarRegex = regexp.MustCompile(`(?i)(balance|ledger|allowance)`)
+)
+
+// callerFuncs are the std functions that return the caller
+var callerFuncs = map[string]struct{}{
+	"GetOrigCaller": {},
+	"OriginCaller":  {},
+	"PrevRealm":     {},
+	"PreviousRealm": {},
+	"GetCallerAt":   {},
+}
+
+// originCallerFuncs are the std functions that return the tx origin caller
+var originCallerFuncs = map[string]struct{}{
+	"GetOrigCaller": {},
+	"OriginCaller":  {},
+}
+
+// transferFuncs are the exported grc20 transfer path entry points
+var transferFuncs = []string{"Transfer", "TransferFrom"}
+
+//go:embed templates/*.gno
+var templatesFS embed.FS
+
+// File is a single package source file
+type File struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+// funcInfo is the static analysis summary of a package-level function
+type funcInfo struct {
+	// calls are the names of the called package-level functions
+	calls []string
+
+	// methods are the names of the called methods (selectors)
+	methods []string
+
+	// stdCalls are the names of the called std functions
+	stdCalls []string
+
+	// checksCaller is set if the function asserts the caller
+	checksCaller bool
+
+	// blocksTransfer is set if the function has
+	// conditions on blocking state or privileged addresses
+	blocksTransfer bool
+}
+
+// AnalyzeRisk statically analyzes the token package
+// source files, and produces the risk report
+func AnalyzeRisk(files []File) *ledger.Risk {
+	var (
+		report = &ledger.Risk{
+			Findings: make([]ledger.RiskFinding, 0),
+		}
+
+		fset   = token.NewFileSet()
+		funcs  = make(map[string]*funcInfo)
+		parsed = make([]*ast.File, 0, len(files))
+
+		hasGRC20Import bool
+	)
+
+	addFinding := func(rule, format string, args ...any) {
+		report.Findings = append(report.Findings, ledger.RiskFinding{
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, file := range sourceFiles(files) {
+		f, err := parser.ParseFile(fset, file.Name, file.Body, parser.SkipObjectResolution)
+		if err != nil {
+			addFinding(RuleUnparsableSource, "unable to parse %s, %s", file.Name, err)
+
+			continue
+		}
+
+		for _, spec := range f.Imports {
+			if strings.HasPrefix(strings.Trim(spec.Path.Value, `"`), grc20ImportPathPrefix) {
+				hasGRC20Import = true
+			}
+		}
+
+		parsed = append(parsed, f)
+	}
+
+	for _, f := range parsed {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				// Methods are not reachable as plain calls
+				if d.Recv != nil || d.Body == nil {
+					continue
+				}
+
+				funcs[d.Name.Name] = analyzeFunc(d)
+
+				if d.Name.IsExported() && d.Type.Results != nil {
+					for _, result := range d.Type.Results.List {
+						if ledgerTypeRegex.MatchString(types.ExprString(result.Type)) {
+							addFinding(
+								RuleExposedLedger,
+								"exported function %s returns the privileged ledger (%s)",
+								d.Name.Name,
+								types.ExprString(result.Type),
+							)
+						}
+					}
+				}
+			case *ast.GenDecl:
+				checkVars(d, hasGRC20Import, addFinding)
+			}
+		}
+	}
+
+	// Check the exported functions for unrestricted minting and burning
+	for _, name := range sortedKeys(funcs) {
+		if !ast.IsExported(name) {
+			continue
+		}
+
+		reachable := reach(funcs, name)
+
+		if anyFunc(funcs, reachable, func(info *funcInfo) bool { return info.checksCaller }) {
+			continue
+		}
+
+		if anyFunc(funcs, reachable, func(info *funcInfo) bool { return slices.Contains(info.methods, "Mint") }) {
+			addFinding(RuleUnrestrictedMint, "exported function %s can mint tokens without a caller check", name)
+		}
+
+		if anyFunc(funcs, reachable, func(info *funcInfo) bool { return slices.Contains(info.methods, "Burn") }) {
+			addFinding(RuleUnrestrictedBurn, "exported function %s can burn tokens without a caller check", name)
+		}
+	}
+
+	// Check the transfer paths
+	for _, name := range transferFuncs {
+		if _, ok := funcs[name]; !ok {
+			continue
+		}
+
+		reachable := reach(funcs, name)
+
+		if anyFunc(funcs, reachable, func(info *funcInfo) bool { return info.blocksTransfer }) {
+			addFinding(RuleTransferBlocking, "%s can be blocked by privileged state", name)
+		}
+
+		if anyFunc(funcs, reachable, func(info *funcInfo) bool {
+			for _, call := range info.stdCalls {
+				if _, ok := originCallerFuncs[call]; ok {
+					return true
+				}
+			}
+
+			return false
+		}) {
+			addFinding(RuleOriginCaller, "%s relies on the tx origin caller", name)
+		}
+	}
+
+	// Compare the source against the audited templates
+	report.Template, report.Similarity = closestTemplate(files)
+
+	if report.Similarity < similarityThreshold {
+		addFinding(
+			RuleUnknownTemplate,
+			"source does not resemble any audited template (best similarity %.2f)",
+			report.Similarity,
+		)
+	}
+
+	report.Score = score(report.Findings)
+
+	return report
+}
+
+// analyzeFunc summarizes the package-level function
+func analyzeFunc(decl *ast.FuncDecl) *funcInfo {
+	info := &funcInfo{}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			switch fn := node.Fun.(type) {
+			case *ast.Ident:
+				info.calls = append(info.calls, fn.Name)
+
+				if callerCheckRegex.MatchString(fn.Name) {
+					info.checksCaller = true
+				}
+			case *ast.SelectorExpr:
+				if pkg, ok := fn.X.(*ast.Ident); ok && pkg.Name == "std" {
+					info.stdCalls = append(info.stdCalls, fn.Sel.Name)
+
+					break
+				}
+
+				info.methods = append(info.methods, fn.Sel.Name)
+
+				if callerCheckRegex.MatchString(fn.Sel.Name) {
+					info.checksCaller = true
+				}
+			}
+		case *ast.IfStmt:
+			checkCondition(node.Cond, info)
+		}
+
+		return true
+	})
+
+	return info
+}
+
+// checkCondition checks the if-statement condition for
+// caller assertions and transfer blocking state
+func checkCondition(cond ast.Expr, info *funcInfo) {
+	ast.Inspect(cond, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+
+			operands := types.ExprString(node.X) + " " + types.ExprString(node.Y)
+
+			if privilegedRegex.MatchString(operands) || callsCaller(node) {
+				info.checksCaller = true
+				info.blocksTransfer = true
+			}
+		case *ast.Ident:
+			if blockingRegex.MatchString(node.Name) {
+				info.blocksTransfer = true
+			}
+		}
+
+		return true
+	})
+}
+
+// callsCaller checks if the expression fetches the caller
+func callsCaller(expr ast.Expr) bool {
+	found := false
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "std" {
+			if _, ok := callerFuncs[sel.Sel.Name]; ok {
+				found = true
+			}
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// checkVars checks the package-level variables
+// for exposed and custom token ledgers
+func checkVars(
+	decl *ast.GenDecl,
+	hasGRC20Import bool,
+	addFinding func(rule, format string, args ...any),
+) {
+	if decl.Tok != token.VAR {
+		return
+	}
+
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		definition := ""
+
+		if valueSpec.Type != nil {
+			definition = types.ExprString(valueSpec.Type)
+		}
+
+		for _, value := range valueSpec.Values {
+			definition += " " + types.ExprString(value)
+		}
+
+		for _, name := range valueSpec.Names {
+			if name.IsExported() && ledgerTypeRegex.MatchString(definition) {
+				addFinding(RuleExposedLedger, "exported variable %s holds the privileged ledger", name.Name)
+			}
+
+			if !hasGRC20Import && ledgerVarRegex.MatchString(name.Name) {
+				addFinding(RuleCustomLedger, "variable %s is a custom ledger, outside of the grc20 API", name.Name)
+			}
+		}
+	}
+}
+
+// reach returns the package-level functions reachable
+// from the given function, including itself
+func reach(funcs map[string]*funcInfo, name string) []string {
+	var (
+		visited = map[string]struct{}{name: {}}
+		queue   = []string{name}
+		result  = make([]string, 0)
+	)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		result = append(result, current)
+
+		info, ok := funcs[current]
+		if !ok {
+			continue
+		}
+
+		for _, call := range info.calls {
+			if _, seen := visited[call]; seen {
+				continue
+			}
+
+			visited[call] = struct{}{}
+			queue = append(queue, call)
+		}
+	}
+
+	return result
+}
+
+// anyFunc checks if any of the given functions matches the predicate
+func anyFunc(funcs map[string]*funcInfo, names []string, predicate func(*funcInfo) bool) bool {
+	for _, name := range names {
+		if info, ok := funcs[name]; ok && predicate(info) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closestTemplate returns the audited template most similar
+// to the given source files, and the similarity
+func closestTemplate(files []File) (string, float64) {
+	source := make([]string, 0, len(files))
+
+	for _, file := range sourceFiles(files) {
+		source = append(source, file.Body)
+	}
+
+	lines := normalizedLines(strings.Join(source, "\n"))
+
+	entries, err := templatesFS.ReadDir("templates")
+	if err != nil {
+		return "", 0
+	}
+
+	var (
+		bestName       string
+		bestSimilarity float64
+	)
+
+	for _, entry := range entries {
+		body, err := templatesFS.ReadFile(path.Join("templates", entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		similarity := jaccard(lines, normalizedLines(string(body)))
+		if similarity > bestSimilarity {
+			bestName = strings.TrimSuffix(entry.Name(), ".gno")
+			bestSimilarity = similarity
+		}
+	}
+
+	return bestName, bestSimilarity
+}
+
+// normalizedLines returns the set of the normalized source lines,
+// without comments, blank lines and the package clause
+func normalizedLines(source string) map[string]struct{} {
+	lines := make(map[string]struct{})
+
+	for _, line := range strings.Split(source, "\n") {
+		if index := strings.Index(line, "//"); index >= 0 {
+			line = line[:index]
+		}
+
+		line = strings.Join(strings.Fields(line), " ")
+
+		if line == "" || strings.HasPrefix(line, "package ") {
+			continue
+		}
+
+		lines[line] = struct{}{}
+	}
+
+	return lines
+}
+
+// jaccard returns the Jaccard similarity of the given sets
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+
+	for item := range a {
+		if _, ok := b[item]; ok {
+			intersection++
+		}
+	}
+
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// score computes the risk score from the findings
+func score(findings []ledger.RiskFinding) int {
+	var (
+		total int
+		rules = make(map[string]struct{})
+	)
+
+	for _, finding := range findings {
+		if _, counted := rules[finding.Rule]; counted {
+			continue
+		}
+
+		rules[finding.Rule] = struct{}{}
+		total += ruleWeights[finding.Rule]
+	}
+
+	if total > maxRiskScore {
+		return maxRiskScore
+	}
+
+	return total
+}
+
+// sourceFiles returns the package source files, without the tests
+func sourceFiles(files []File) []File {
+	source := make([]File, 0, len(files))
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, ".gno") ||
+			strings.HasSuffix(file.Name, "_test.gno") ||
+			strings.HasSuffix(file.Name, "_filetest.gno") {
+			continue
+		}
+
+		source = append(source, file)
+	}
+
+	return source
+}
+
+// sortedKeys returns the sorted function names
+func sortedKeys(funcs map[string]*funcInfo) []string {
+	keys := make([]string, 0, len(funcs))
+
+	for key := range funcs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,197 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// rules returns the rules of the given findings
+func rules(findings []ledger.RiskFinding) []string {
+	list := make([]string, 0, len(findings))
+
+	for _, finding := range findings {
+		list = append(list, finding.Rule)
+	}
+
+	return list
+}
+
+func TestAnalyzeRisk_Template(t *testing.T) {
+	t.Parallel()
+
+	body, err := templatesFS.ReadFile("templates/foo20.gno")
+	require.NoError(t, err)
+
+	report := AnalyzeRisk([]File{
+		{Name: "foo20.gno", Body: string(body)},
+		{Name: "foo20_test.gno", Body: "package foo20\n\nfunc Broken( {"},
+	})
+
+	assert.Empty(t, report.Findings)
+	assert.Equal(t, 0, report.Score)
+	assert.Equal(t, "foo20", report.Template)
+	assert.InDelta(t, 1.0, report.Similarity, 0.001)
+}
+
+func TestAnalyzeRisk_Findings(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name          string
+		source        string
+		expectedRules []string
+	}{
+		{
+			"unrestricted mint",
+			`package foo
+
+import (
+	"std"
+
+	"gno.land/p/demo/grc/grc20"
+)
+
+var banker = grc20.NewBanker("Foo", "FOO", 4)
+
+func Mint(to std.Address, amount uint64) {
+	mint(to, amount)
+}
+
+func mint(to std.Address, amount uint64) {
+	banker.Mint(to, amount)
+}
+`,
+			[]string{RuleUnrestrictedMint},
+		},
+		{
+			"restricted mint and burn",
+			`package foo
+
+import (
+	"std"
+
+	"gno.land/p/demo/grc/grc20"
+)
+
+var (
+	banker = grc20.NewBanker("Foo", "FOO", 4)
+	owner  std.Address
+)
+
+func Mint(to std.Address, amount uint64) {
+	if std.PrevRealm().Addr() != owner {
+		panic("unauthorized")
+	}
+
+	banker.Mint(to, amount)
+}
+
+func Burn(from std.Address, amount uint64) {
+	onlyOwner()
+	banker.Burn(from, amount)
+}
+
+func onlyOwner() {}
+`,
+			[]string{},
+		},
+		{
+			"transfer blocking and origin caller",
+			`package foo
+
+import (
+	"std"
+
+	"gno.land/p/demo/grc/grc20"
+)
+
+var (
+	banker    = grc20.NewBanker("Foo", "FOO", 4)
+	blacklist = map[std.Address]bool{}
+)
+
+func Transfer(to std.Address, amount uint64) {
+	caller := std.GetOrigCaller()
+	if blacklist[caller] {
+		panic("blocked")
+	}
+
+	banker.Transfer(caller, to, amount)
+}
+`,
+			[]string{RuleTransferBlocking, RuleOriginCaller},
+		},
+		{
+			"exposed ledger",
+			`package foo
+
+import "gno.land/p/demo/grc/grc20"
+
+var Banker = grc20.NewBanker("Foo", "FOO", 4)
+
+func GetBanker() *grc20.Banker {
+	return Banker
+}
+`,
+			[]string{RuleExposedLedger, RuleExposedLedger},
+		},
+		{
+			"custom ledger",
+			`package foo
+
+import "std"
+
+var balances = map[std.Address]uint64{}
+
+func BalanceOf(owner std.Address) uint64 {
+	return balances[owner]
+}
+`,
+			[]string{RuleCustomLedger},
+		},
+		{
+			"unparsable source",
+			`package foo
+
+func Broken( {
+`,
+			[]string{RuleUnparsableSource},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := AnalyzeRisk([]File{{Name: "foo.gno", Body: testCase.source}})
+
+			// None of the sources resemble the audited templates
+			expectedRules := append(testCase.expectedRules, RuleUnknownTemplate)
+
+			assert.Equal(t, expectedRules, rules(report.Findings))
+			assert.Positive(t, report.Score)
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 40, score([]ledger.RiskFinding{
+		{Rule: RuleUnrestrictedMint},
+		{Rule: RuleUnrestrictedMint},
+	}))
+
+	assert.Equal(t, maxRiskScore, score([]ledger.RiskFinding{
+		{Rule: RuleUnrestrictedMint},
+		{Rule: RuleUnrestrictedBurn},
+		{Rule: RuleExposedLedger},
+		{Rule: RuleTransferBlocking},
+	}))
+}
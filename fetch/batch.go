@@ -0,0 +1,138 @@
+package fetch
+
+import (
+	"errors"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+var errProcessorCommit = errors.New("processor writes are committed with the block batch")
+
+var _ storage.Batch = &processorBatch{}
+
+// processorBatch is the write set of a single block processor.
+// The writes are buffered, and only applied to the block batch
+// once the processor succeeds, so a failing processor
+// leaves no partial writes in the committed block
+type processorBatch struct {
+	writes []func(storage.Batch) error
+}
+
+// apply applies the buffered writes to the given batch, in order
+func (b *processorBatch) apply(wb storage.Batch) error {
+	for _, write := range b.writes {
+		if err := write(wb); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add buffers the write
+func (b *processorBatch) add(write func(storage.Batch) error) error {
+	b.writes = append(b.writes, write)
+
+	return nil
+}
+
+func (b *processorBatch) SetLatestHeight(height uint64) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetLatestHeight(height)
+	})
+}
+
+func (b *processorBatch) SetBlock(block *types.Block) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetBlock(block)
+	})
+}
+
+func (b *processorBatch) SetTx(tx *types.TxResult) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetTx(tx)
+	})
+}
+
+func (b *processorBatch) SetToken(token *ledger.Token) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetToken(token)
+	})
+}
+
+func (b *processorBatch) SetIntent(token *ledger.Token) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetIntent(token)
+	})
+}
+
+func (b *processorBatch) DeleteIntent(pkgPath string) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.DeleteIntent(pkgPath)
+	})
+}
+
+func (b *processorBatch) SetRegistrationTx(tx *ledger.RegistrationTx) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetRegistrationTx(tx)
+	})
+}
+
+func (b *processorBatch) SetSpend(spend *ledger.Spend) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetSpend(spend)
+	})
+}
+
+func (b *processorBatch) SetNotification(notification *ledger.Notification) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetNotification(notification)
+	})
+}
+
+func (b *processorBatch) DeleteNotification(id uint64) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.DeleteNotification(id)
+	})
+}
+
+func (b *processorBatch) SetPackage(pkg *std.MemPackage) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetPackage(pkg)
+	})
+}
+
+func (b *processorBatch) SetRegistryVersion(version string) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetRegistryVersion(version)
+	})
+}
+
+func (b *processorBatch) SetGenesisImported() error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetGenesisImported()
+	})
+}
+
+func (b *processorBatch) SetCheckpoint(name string, height uint64) error {
+	return b.add(func(wb storage.Batch) error {
+		return wb.SetCheckpoint(name, height)
+	})
+}
+
+// Commit is not supported, the writes are
+// committed with the block batch they are applied to
+func (b *processorBatch) Commit() error {
+	return errProcessorCommit
+}
+
+// Rollback discards the buffered writes
+func (b *processorBatch) Rollback() error {
+	b.writes = nil
+
+	return nil
+}
@@ -5,19 +5,21 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	queue "github.com/madz-lab/insertion-queue"
 	"github.com/tidwall/gjson"
 	"go.uber.org/zap"
 
-	mapset "github.com/deckarep/golang-set"
-
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/std"
-	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/rules"
 	"github.com/gnolang/tx-indexer/storage"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 
@@ -29,12 +31,6 @@ const (
 	DefaultMaxChunkSize = 100
 )
 
-const (
-	bankerPattern = `grc20\.NewBanker\("([^"]+)",\s*"([^"]+)",\s*(\d+)\)`
-)
-
-var bankerRegex_ = regexp.MustCompile(bankerPattern)
-
 // Fetcher is an instance of the block indexer
 // fetcher
 type Fetcher struct {
@@ -42,6 +38,16 @@ type Fetcher struct {
 	client    Client
 	rpcClient rpcClient.RPCClient // the rpc client
 	events    Events
+	registrar Registrar
+
+	eventDetector *detector.EventDetector // the event-based token detector, if any
+	grc721        bool                    // flag indicating if the GRC721 collections are detected
+	rules         *rules.Engine           // the detection rules engine, if any
+	outbox        Outbox                  // the lifecycle webhook outbox, if any
+
+	processors  []BlockProcessor
+	checkpoints map[string]uint64   // the latest height processed by each processor
+	stalled     map[string]struct{} // the processors that failed, until they catch up
 
 	logger      *zap.Logger
 	chunkBuffer *slots
@@ -59,6 +65,7 @@ func New(
 	client Client,
 	rpcClient rpcClient.RPCClient,
 	events Events,
+	registrar Registrar,
 	opts ...Option,
 ) *Fetcher {
 	f := &Fetcher{
@@ -66,6 +73,7 @@ func New(
 		client:        client,
 		rpcClient:     rpcClient,
 		events:        events,
+		registrar:     registrar,
 		queryInterval: 1 * time.Second,
 		logger:        zap.NewNop(),
 		maxSlots:      DefaultMaxSlots,
@@ -148,11 +156,18 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 		return nil
 	}
 
+	// Load the block processor checkpoints
+	if err := f.loadCheckpoints(); err != nil {
+		return err
+	}
+
 	// Start a listener for monitoring new blocks
 	ticker := time.NewTicker(f.queryInterval)
 	defer ticker.Stop()
 
 	// Execute the initial "catch up" with the chain
+	f.catchUpProcessors()
+
 	if err := attemptRangeFetch(); err != nil {
 		return err
 	}
@@ -165,6 +180,8 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 
 			return nil
 		case <-ticker.C:
+			f.catchUpProcessors()
+
 			if err := attemptRangeFetch(); err != nil {
 				return err
 			}
@@ -227,65 +244,20 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 							continue
 						}
 
-						// START REGISTER
-						success := txResult.Response.Error == nil
-						if success {
-							// ITER MSG
-							for _, tx := range block.Data.Txs {
-								stdTx := std.Tx{}
-								amino.MustUnmarshal(tx, &stdTx)
-
-								// iterate msgs in single tx
-								for _, msg := range stdTx.GetMsgs() {
-									msgType := msg.Type()
-									// bank.MsgSend == send
-									// vm.m_addpkg == add_package
-									// vm.m_call == exec
-									// vm.m_run == run
-
-									// package deploy success
-									if msgType == "add_package" {
-										byteMsg := amino.MustMarshalJSON(msg)
-										jsonMsg := gjson.ParseBytes(byteMsg)
-
-										pkgPath := jsonMsg.Get("package.path").String()
-
-										// get public functions
-										funcsResponse, err := f.rpcClient.ABCIQuery("vm/qfuncs", []byte(pkgPath))
-										if err != nil {
-											f.logger.Error("unable to fetch package info", zap.Error(err))
-										}
-										funcListStr := string(funcsResponse.Response.ResponseBase.Data)
-										funcList := gjson.Parse(funcListStr).Array()
-
-										var funcNameList []string
-										for _, funcInfo := range funcList {
-											funcName := funcInfo.Get("FuncName").String()
-											funcNameList = append(funcNameList, funcName)
-										}
-
-										// fileContent
-										fileContents := []string{}
-										for _, file := range jsonMsg.Get("package.files").Array() {
-											fileContent := file.Get("body").String()
-											b64enc := base64.StdEncoding.EncodeToString([]byte(fileContent))
-											fileContents = append(fileContents, b64enc)
-										}
-										has := hasMeta(fileContents)
-
-										if isGRC20(funcNameList) && has {
-											if err := addpkg.RegisterGrc20Token(pkgPath); err != nil {
-												f.logger.Error("unable to register grc20 token", zap.Error(err))
-											} else {
-												f.logger.Info("registered grc20 token", zap.String("pkgPath", pkgPath))
-											}
-										}
-									}
-								}
+						// Save the packages deployed in the tx, and register
+						// the grc20 tokens among them, if any. The batch is not
+						// committed without the intents, so no detection is lost
+						if err := f.registerTokens(wb, txResult); err != nil {
+							if rErr := wb.Rollback(); rErr != nil {
+								return fmt.Errorf("unable to register tokens, %w, %w", err, rErr)
 							}
-						}
 
+							return fmt.Errorf("unable to register tokens, %w", err)
+						}
 					}
+
+					// Run the block processors, within the block batch
+					f.processBlock(wb, block, txResults, item.chunkRange.from)
 				}
 
 				f.logger.Info(
@@ -306,42 +278,274 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 				if err := wb.Commit(); err != nil {
 					return fmt.Errorf("error persisting block information into storage, %w", err)
 				}
+
+				// Signal the committed registration intents, if any
+				f.registrar.NotifyIntents()
 			}
 		}
 	}
 }
 
-func isGRC20(mainSlice []string) bool {
-	// REF: https://github.com/gnolang/gno/blob/0f2e7551b43c18d27b63cbbadecf07ee48f185f9/examples/gno.land/p/demo/grc/grc20/imustgrc20.gno#L13-L21
-	grc20List := []string{"TotalSupply", "BalanceOf", "Transfer", "Allowance", "Approve", "TransferFrom"}
+// registerTokens saves the packages deployed by a successful tx,
+// and the registration intents of the grc20 tokens among them,
+// alongside the tokens referenced by the tx events, if any.
+// With detection rules, the tx messages are handled by the rule actions
+func (f *Fetcher) registerTokens(wb storage.Batch, txResult *types.TxResult) error {
+	if txResult.Response.Error != nil {
+		return nil
+	}
 
-	mainSet := sliceToSet(mainSlice)
-	grc20Set := sliceToSet(grc20List)
+	var stdTx std.Tx
+	if err := amino.Unmarshal(txResult.Tx, &stdTx); err != nil {
+		f.logger.Error("unable to decode tx", zap.Error(err))
 
-	return grc20Set.IsSubset(mainSet)
-}
+		return nil
+	}
 
-func hasMeta(srcCode []string) bool {
-	for _, src := range srcCode {
-		decode, err := base64.StdEncoding.DecodeString(src)
-		if err != nil {
-			return false
+	// deployed are the packages deployed in the tx
+	deployed := make(map[string]struct{})
+
+	// eventTypes are the types of the tx events, matched by the rules
+	var eventTypes []string
+	if f.rules != nil {
+		eventTypes = detector.EventTypes(txResult.Response.Events)
+	}
+
+	// iterate msgs in single tx
+	for _, msg := range stdTx.GetMsgs() {
+		// bank.MsgSend == send
+		// vm.m_addpkg == add_package
+		// vm.m_call == exec
+		// vm.m_run == run
+
+		// Realm calls are only matched by the rules
+		if call, ok := msg.(vm.MsgCall); ok && f.rules != nil {
+			if err := f.applyRules(wb, &rules.Subject{
+				PkgPath:  call.PkgPath,
+				Deployer: call.Caller.String(),
+				Call:     true,
+				Events:   eventTypes,
+			}, txResult); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// package deploy success
+		if msg.Type() != "add_package" {
+			continue
+		}
+
+		// Save the deployed package, so it can be imported
+		// when type-checking the register packages
+		if addPkg, ok := msg.(vm.MsgAddPackage); ok && addPkg.Package != nil {
+			if err := wb.SetPackage(addPkg.Package); err != nil {
+				f.logger.Error("unable to save package", zap.Error(err))
+			}
+		}
+
+		jsonMsg := gjson.ParseBytes(amino.MustMarshalJSON(msg))
+		pkgPath := jsonMsg.Get("package.path").String()
+
+		deployed[pkgPath] = struct{}{}
+
+		// Registry realm deployments activate new registry versions
+		f.registrar.Deployed(pkgPath)
+
+		// fileContent
+		files := make([]detector.File, 0)
+		for _, file := range jsonMsg.Get("package.files").Array() {
+			files = append(files, detector.File{
+				Name: file.Get("name").String(),
+				Body: file.Get("body").String(),
+			})
+		}
+
+		if f.rules != nil {
+			if err := f.applyRules(wb, &rules.Subject{
+				PkgPath:  pkgPath,
+				Deployer: jsonMsg.Get("creator").String(),
+				Files:    files,
+				Events:   eventTypes,
+			}, txResult); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := f.registerPackage(wb, pkgPath, jsonMsg.Get("creator").String(), files, txResult); err != nil {
+			return err
+		}
+	}
+
+	if f.eventDetector == nil {
+		return nil
+	}
+
+	// The tx signer is considered the deployer
+	// of the tokens referenced by the tx events
+	var deployer string
+	if signers := stdTx.GetSigners(); len(signers) > 0 {
+		deployer = signers[0].String()
+	}
+
+	for _, match := range f.eventDetector.Detect(txResult.Response.Events) {
+		// The tokens deployed in the tx are already handled
+		if _, ok := deployed[match.PkgPath]; ok && match.Key == "" {
+			continue
 		}
 
-		matches := bankerRegex_.FindStringSubmatch(string(decode))
+		// The tokens can be referenced again, ex. by registry events
+		if _, err := f.storage.GetToken(match.ID()); err == nil {
+			continue
+		}
+
+		if match.Key != "" {
+			if err := f.registerKeyedToken(wb, match, deployer, txResult); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		files, err := f.packageFiles(match.PkgPath)
+		if err != nil {
+			f.logger.Error(
+				"unable to fetch package files",
+				zap.String("pkgPath", match.PkgPath),
+				zap.Error(err),
+			)
+
+			continue
+		}
 
-		if len(matches) > 0 {
-			return true
+		if err := f.registerPackage(wb, match.PkgPath, deployer, files, txResult); err != nil {
+			return err
 		}
 	}
 
-	return false
+	return nil
 }
 
-func sliceToSet(mySlice []string) mapset.Set {
-	mySet := mapset.NewSet()
-	for _, ele := range mySlice {
-		mySet.Add(ele)
+// registerPackage writes the registration intent of the package,
+// if it is a grc20 token, or a grc721 collection if enabled.
+// The exported functions are derived from the package source,
+// so the detection doesn't depend on a chain query succeeding
+func (f *Fetcher) registerPackage(
+	wb storage.Batch,
+	pkgPath,
+	deployer string,
+	files []detector.File,
+	txResult *types.TxResult,
+) error {
+	detection := detector.Detect(detector.ExportedFuncs(files), files, f.grc721)
+	if !detection.IsToken() {
+		return nil
 	}
-	return mySet
+
+	token := detection.Token
+	token.PkgPath = pkgPath
+	token.Deployer = deployer
+	token.DeployTxHash = base64.StdEncoding.EncodeToString(txResult.Tx.Hash())
+	token.Height = txResult.Height
+
+	return f.intend(wb, token)
+}
+
+// registerKeyedToken writes the registration intent
+// of the token kept by key in its (factory) package
+func (f *Fetcher) registerKeyedToken(
+	wb storage.Batch,
+	match *detector.EventMatch,
+	deployer string,
+	txResult *types.TxResult,
+) error {
+	return f.intend(wb, &ledger.Token{
+		PkgPath:      match.ID(),
+		Kind:         ledger.KindGRC20,
+		Key:          match.Key,
+		Name:         match.Name,
+		Symbol:       match.Symbol,
+		Decimals:     match.Decimals,
+		Deployer:     deployer,
+		DeployTxHash: base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+		Height:       txResult.Height,
+	})
+}
+
+// intend writes the registration intent of the detected token, in the batch
+// committing the block it was detected in. The registrar consumes the
+// intents once they are committed, so no detection is lost or duplicated.
+// The batch is not committed if the intent is unable to be written
+func (f *Fetcher) intend(wb storage.Batch, token *ledger.Token) error {
+	if err := wb.SetIntent(token); err != nil {
+		return fmt.Errorf("unable to save registration intent of %s, %w", token.PkgPath, err)
+	}
+
+	if f.outbox == nil {
+		return nil
+	}
+
+	notification := &ledger.Notification{
+		Event: ledger.EventDetected,
+		Time:  time.Now(),
+		Token: token,
+	}
+
+	if err := f.outbox.Enqueue(wb, notification); err != nil {
+		return fmt.Errorf("unable to enqueue detection notification of %s, %w", token.PkgPath, err)
+	}
+
+	return nil
+}
+
+// packageFiles fetches the source files of the deployed package,
+// from the storage, or from the chain if it is not saved
+func (f *Fetcher) packageFiles(pkgPath string) ([]detector.File, error) {
+	files := make([]detector.File, 0)
+
+	if pkg, err := f.storage.GetPackage(pkgPath); err == nil {
+		for _, file := range pkg.Files {
+			files = append(files, detector.File{
+				Name: file.Name,
+				Body: file.Body,
+			})
+		}
+
+		return files, nil
+	}
+
+	// The package query returns the newline-separated file names
+	res, err := f.rpcClient.ABCIQuery("vm/qfile", []byte(pkgPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Response.Error != nil {
+		return nil, res.Response.Error
+	}
+
+	for _, name := range strings.Split(string(res.Response.Data), "\n") {
+		if !strings.HasSuffix(name, ".gno") {
+			continue
+		}
+
+		fileRes, err := f.rpcClient.ABCIQuery("vm/qfile", []byte(pkgPath+"/"+name))
+		if err != nil {
+			return nil, err
+		}
+
+		if fileRes.Response.Error != nil {
+			return nil, fileRes.Response.Error
+		}
+
+		files = append(files, detector.File{
+			Name: name,
+			Body: string(fileRes.Response.Data),
+		})
+	}
+
+	return files, nil
 }
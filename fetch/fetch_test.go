@@ -0,0 +1,142 @@
+package fetch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// grc20Source is the source of a minimal grc20 token package
+const grc20Source = `package foo
+
+import "gno.land/p/demo/grc/grc20"
+
+var banker = grc20.NewBanker("Foo", "FOO", 4)
+
+func TotalSupply() uint64 { return 0 }
+
+func BalanceOf(owner string) uint64 { return 0 }
+
+func Transfer(to string, amount uint64) {}
+
+func Allowance(owner, spender string) uint64 { return 0 }
+
+func Approve(spender string, amount uint64) {}
+
+func TransferFrom(from, to string, amount uint64) {}
+`
+
+// addPkgTx creates the tx deploying the package with the given source
+func addPkgTx(pkgPath, body string) std.Tx {
+	return std.Tx{
+		Msgs: []std.Msg{
+			vm.MsgAddPackage{
+				Creator: crypto.Address{},
+				Package: &std.MemPackage{
+					Name: "foo",
+					Path: pkgPath,
+					Files: []*std.MemFile{
+						{Name: "foo.gno", Body: body},
+					},
+				},
+			},
+		},
+	}
+}
+
+// addPkgTxResult creates the result of the tx
+// deploying the package with the given source
+func addPkgTxResult(t *testing.T, pkgPath, body string) *types.TxResult {
+	t.Helper()
+
+	encodedTx, err := amino.Marshal(addPkgTx(pkgPath, body))
+	require.NoError(t, err)
+
+	return &types.TxResult{
+		Height: 10,
+		Tx:     encodedTx,
+	}
+}
+
+func TestFetcher_RegisterTokens(t *testing.T) {
+	t.Parallel()
+
+	var (
+		intents  = make([]*ledger.Token, 0)
+		packages = make([]string, 0)
+		deployed = make([]string, 0)
+
+		wb = &mock.WriteBatch{
+			SetIntentFn: func(token *ledger.Token) error {
+				intents = append(intents, token)
+
+				return nil
+			},
+			SetPackageFn: func(pkg *std.MemPackage) error {
+				packages = append(packages, pkg.Path)
+
+				return nil
+			},
+		}
+
+		registrar = &mockRegistrar{
+			deployedFn: func(pkgPath string) {
+				deployed = append(deployed, pkgPath)
+			},
+		}
+	)
+
+	f := &Fetcher{
+		storage:   &mock.Storage{},
+		registrar: registrar,
+		logger:    zap.NewNop(),
+	}
+
+	require.NoError(t, f.registerTokens(wb, addPkgTxResult(t, "gno.land/r/demo/foo", grc20Source)))
+
+	assert.Equal(t, []string{"gno.land/r/demo/foo"}, packages)
+	assert.Equal(t, []string{"gno.land/r/demo/foo"}, deployed)
+
+	// Make sure the token is detected from its source, without a chain query
+	require.Len(t, intents, 1)
+
+	assert.Equal(t, "gno.land/r/demo/foo", intents[0].PkgPath)
+	assert.Equal(t, "FOO", intents[0].Symbol)
+	assert.Equal(t, int64(10), intents[0].Height)
+}
+
+func TestFetcher_RegisterTokens_IntentError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		intentErr = errors.New("unable to write")
+
+		wb = &mock.WriteBatch{
+			SetIntentFn: func(*ledger.Token) error {
+				return intentErr
+			},
+		}
+	)
+
+	f := &Fetcher{
+		storage:   &mock.Storage{},
+		registrar: &mockRegistrar{},
+		logger:    zap.NewNop(),
+	}
+
+	// Make sure the intent error is returned, so the batch is not committed
+	err := f.registerTokens(wb, addPkgTxResult(t, "gno.land/r/demo/foo", grc20Source))
+
+	assert.ErrorIs(t, err, intentErr)
+}
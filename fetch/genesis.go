@@ -0,0 +1,135 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var errNoAppState = errors.New("no app state found in genesis")
+
+// genesisTxsPaths are the paths of the genesis txs, in the genesis
+// file, and in the node /genesis JSON-RPC response
+var genesisTxsPaths = []string{
+	"app_state.txs",
+	"result.genesis.app_state.txs",
+}
+
+// ParseGenesisTxs parses the txs from the raw genesis file,
+// or from the raw node /genesis JSON-RPC response
+func ParseGenesisTxs(raw []byte) ([]std.Tx, error) {
+	rawTxs, err := extractGenesisTxs(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]std.Tx, 0, len(rawTxs))
+
+	for i, rawTx := range rawTxs {
+		var tx std.Tx
+
+		if err := amino.UnmarshalJSON([]byte(rawTx), &tx); err != nil {
+			return nil, fmt.Errorf("unable to decode genesis tx #%d, %w", i, err)
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// extractGenesisTxs extracts the raw JSON txs from the raw genesis.
+// The txs can be wrapped with their metadata, depending on the genesis version
+func extractGenesisTxs(raw []byte) ([]string, error) {
+	for _, path := range genesisTxsPaths {
+		result := gjson.GetBytes(raw, path)
+		if !result.Exists() {
+			continue
+		}
+
+		rawTxs := make([]string, 0)
+
+		for _, tx := range result.Array() {
+			if wrapped := tx.Get("tx"); wrapped.Exists() {
+				tx = wrapped
+			}
+
+			rawTxs = append(rawTxs, tx.Raw)
+		}
+
+		return rawTxs, nil
+	}
+
+	return nil, errNoAppState
+}
+
+// ImportGenesis saves the genesis txs as height 0 tx records, alongside
+// the registration intents of the grc20 tokens they deploy. The genesis
+// is imported once, the import is skipped if its marker is already saved
+func (f *Fetcher) ImportGenesis(txs []std.Tx) error {
+	imported, err := f.storage.GetGenesisImported()
+	if err != nil {
+		return fmt.Errorf("unable to check genesis import, %w", err)
+	}
+
+	if imported {
+		f.logger.Info("genesis already imported")
+
+		return nil
+	}
+
+	wb := f.storage.WriteBatch()
+
+	for i, tx := range txs {
+		encodedTx, err := amino.Marshal(tx)
+		if err != nil {
+			_ = wb.Rollback()
+
+			return fmt.Errorf("unable to encode genesis tx #%d, %w", i, err)
+		}
+
+		txResult := &types.TxResult{
+			Height: 0,
+			Index:  uint32(i),
+			Tx:     encodedTx,
+		}
+
+		if err := wb.SetTx(txResult); err != nil {
+			_ = wb.Rollback()
+
+			return fmt.Errorf("unable to save genesis tx #%d, %w", i, err)
+		}
+
+		// Save the packages deployed in genesis, and register
+		// the grc20 tokens among them, if any
+		if err := f.registerTokens(wb, txResult); err != nil {
+			_ = wb.Rollback()
+
+			return fmt.Errorf("unable to register genesis tx #%d tokens, %w", i, err)
+		}
+	}
+
+	// The genesis is marked as imported with its txs,
+	// so a genesis without txs is not imported again
+	if err := wb.SetGenesisImported(); err != nil {
+		_ = wb.Rollback()
+
+		return fmt.Errorf("unable to mark genesis as imported, %w", err)
+	}
+
+	if err := wb.Commit(); err != nil {
+		return fmt.Errorf("unable to persist genesis txs, %w", err)
+	}
+
+	f.registrar.NotifyIntents()
+
+	f.logger.Info("imported genesis", zap.Int("txs", len(txs)))
+
+	return nil
+}
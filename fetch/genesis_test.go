@@ -0,0 +1,212 @@
+package fetch
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+func TestExtractGenesisTxs(t *testing.T) {
+	t.Parallel()
+
+	const tx = `{"msg":[{"@type":"/vm.m_addpkg","creator":"g1alice","package":{"name":"foo","path":"gno.land/r/demo/foo"}}]}`
+
+	testTable := []struct {
+		name    string
+		genesis string
+	}{
+		{
+			"genesis file",
+			`{"chain_id":"dev","app_state":{"balances":[],"txs":[` + tx + `]}}`,
+		},
+		{
+			"genesis file with tx metadata",
+			`{"chain_id":"dev","app_state":{"balances":[],"txs":[{"tx":` + tx + `,"metadata":{"timestamp":0}}]}}`,
+		},
+		{
+			"remote genesis",
+			`{"jsonrpc":"2.0","id":"","result":{"genesis":{"app_state":{"txs":[` + tx + `]}}}}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			txs, err := extractGenesisTxs([]byte(testCase.genesis))
+			require.NoError(t, err)
+
+			require.Len(t, txs, 1)
+			assert.JSONEq(t, tx, txs[0])
+		})
+	}
+}
+
+func TestExtractGenesisTxs_NoAppState(t *testing.T) {
+	t.Parallel()
+
+	_, err := extractGenesisTxs([]byte(`{"chain_id":"dev"}`))
+
+	assert.ErrorIs(t, err, errNoAppState)
+}
+
+func TestFetcher_ImportGenesis(t *testing.T) {
+	t.Parallel()
+
+	var (
+		txs      = make([]*types.TxResult, 0)
+		packages = make([]*std.MemPackage, 0)
+		intents  = make([]*ledger.Token, 0)
+
+		committed = false
+		notified  = false
+		marked    = false
+
+		wb = &mock.WriteBatch{
+			SetTxFn: func(tx *types.TxResult) error {
+				txs = append(txs, tx)
+
+				return nil
+			},
+			SetPackageFn: func(pkg *std.MemPackage) error {
+				packages = append(packages, pkg)
+
+				return nil
+			},
+			SetIntentFn: func(token *ledger.Token) error {
+				intents = append(intents, token)
+
+				return nil
+			},
+			SetGenesisFn: func() error {
+				marked = true
+
+				return nil
+			},
+			CommitFn: func() error {
+				committed = true
+
+				return nil
+			},
+		}
+
+		s = &mock.Storage{
+			GetGenesisImportedFn: func() (bool, error) {
+				return false, nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return wb
+			},
+		}
+	)
+
+	f := &Fetcher{
+		storage: s,
+		registrar: &mockRegistrar{
+			notifyIntentsFn: func() {
+				notified = true
+			},
+		},
+		logger: zap.NewNop(),
+	}
+
+	require.NoError(t, f.ImportGenesis([]std.Tx{
+		addPkgTx("gno.land/r/demo/foo", grc20Source),
+	}))
+
+	assert.True(t, committed)
+	assert.True(t, notified)
+	assert.True(t, marked)
+
+	// Make sure the genesis tx is saved as a height 0 record
+	require.Len(t, txs, 1)
+
+	assert.Equal(t, int64(0), txs[0].Height)
+	assert.Equal(t, uint32(0), txs[0].Index)
+
+	// Make sure the deployed package is saved
+	require.Len(t, packages, 1)
+
+	assert.Equal(t, "gno.land/r/demo/foo", packages[0].Path)
+
+	// Make sure the registration intent of the token is saved
+	require.Len(t, intents, 1)
+
+	assert.Equal(t, "gno.land/r/demo/foo", intents[0].PkgPath)
+	assert.Equal(t, "FOO", intents[0].Symbol)
+	assert.Equal(t, ledger.KindGRC20, intents[0].Kind)
+	assert.Equal(t, int64(0), intents[0].Height)
+}
+
+func TestFetcher_ImportGenesis_Imported(t *testing.T) {
+	t.Parallel()
+
+	s := &mock.Storage{
+		GetGenesisImportedFn: func() (bool, error) {
+			return true, nil
+		},
+		GetWriteBatchFn: func() storage.Batch {
+			require.FailNow(t, "genesis imported again")
+
+			return nil
+		},
+	}
+
+	f := &Fetcher{
+		storage: s,
+		registrar: &mockRegistrar{
+			notifyIntentsFn: func() {
+				require.FailNow(t, "intents notified again")
+			},
+		},
+		logger: zap.NewNop(),
+	}
+
+	// Make sure the import is skipped, if the genesis is marked as imported
+	assert.NoError(t, f.ImportGenesis([]std.Tx{
+		addPkgTx("gno.land/r/demo/foo", grc20Source),
+	}))
+}
+
+func TestFetcher_ImportGenesis_NoTxs(t *testing.T) {
+	t.Parallel()
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	notified := 0
+
+	f := &Fetcher{
+		storage: s,
+		registrar: &mockRegistrar{
+			notifyIntentsFn: func() {
+				notified++
+			},
+		},
+		logger: zap.NewNop(),
+	}
+
+	// Make sure a genesis without txs is only imported once
+	require.NoError(t, f.ImportGenesis(nil))
+	require.NoError(t, f.ImportGenesis(nil))
+
+	imported, err := s.GetGenesisImported()
+	require.NoError(t, err)
+
+	assert.True(t, imported)
+	assert.Equal(t, 1, notified)
+}
@@ -0,0 +1,58 @@
+package fetch
+
+import (
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+type processBlockDelegate func(storage.Batch, *BlockData) error
+
+type mockProcessor struct {
+	name           string
+	processBlockFn processBlockDelegate
+}
+
+func (m *mockProcessor) Name() string {
+	return m.name
+}
+
+func (m *mockProcessor) ProcessBlock(wb storage.Batch, block *BlockData) error {
+	if m.processBlockFn != nil {
+		return m.processBlockFn(wb, block)
+	}
+
+	return nil
+}
+
+type deployedDelegate func(string)
+
+type mockRegistrar struct {
+	notifyIntentsFn func()
+	deployedFn      deployedDelegate
+}
+
+func (m *mockRegistrar) NotifyIntents() {
+	if m.notifyIntentsFn != nil {
+		m.notifyIntentsFn()
+	}
+}
+
+func (m *mockRegistrar) Deployed(pkgPath string) {
+	if m.deployedFn != nil {
+		m.deployedFn(pkgPath)
+	}
+}
+
+type enqueueDelegate func(storage.Batch, *ledger.Notification) error
+
+type mockOutbox struct {
+	enqueueFn enqueueDelegate
+}
+
+func (m *mockOutbox) Enqueue(wb storage.Batch, notification *ledger.Notification) error {
+	if m.enqueueFn != nil {
+		return m.enqueueFn(wb, notification)
+	}
+
+	return nil
+}
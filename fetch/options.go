@@ -1,6 +1,11 @@
 package fetch
 
-import "go.uber.org/zap"
+import (
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/rules"
+)
 
 type Option func(f *Fetcher)
 
@@ -27,3 +32,43 @@ func WithMaxChunkSize(maxChunkSize int64) Option {
 		f.maxChunkSize = maxChunkSize
 	}
 }
+
+// WithEventDetector sets the detector of the tokens
+// created without a package deployment, ex. by factory realms
+func WithEventDetector(eventDetector *detector.EventDetector) Option {
+	return func(f *Fetcher) {
+		f.eventDetector = eventDetector
+	}
+}
+
+// WithGRC721Detection enables the detection of the deployed
+// GRC721 collections, handed off to the registrar along the grc20 tokens
+func WithGRC721Detection(enabled bool) Option {
+	return func(f *Fetcher) {
+		f.grc721 = enabled
+	}
+}
+
+// WithRules sets the detection rules engine. The tx messages are
+// evaluated against the rules, instead of the built-in detection
+func WithRules(engine *rules.Engine) Option {
+	return func(f *Fetcher) {
+		f.rules = engine
+	}
+}
+
+// WithOutbox sets the lifecycle webhook outbox, notified
+// of the detected tokens and the webhook rule actions
+func WithOutbox(outbox Outbox) Option {
+	return func(f *Fetcher) {
+		f.outbox = outbox
+	}
+}
+
+// WithBlockProcessor adds a block processor,
+// run on every block after it is saved
+func WithBlockProcessor(processor BlockProcessor) Option {
+	return func(f *Fetcher) {
+		f.processors = append(f.processors, processor)
+	}
+}
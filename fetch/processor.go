@@ -0,0 +1,234 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+var errDuplicateProcessor = errors.New("duplicate block processor name")
+
+// loadCheckpoints loads the checkpoints of the block processors.
+// Processors without a checkpoint start from the first block
+func (f *Fetcher) loadCheckpoints() error {
+	f.checkpoints = make(map[string]uint64, len(f.processors))
+	f.stalled = make(map[string]struct{})
+
+	for _, processor := range f.processors {
+		name := processor.Name()
+
+		if _, exists := f.checkpoints[name]; exists {
+			return fmt.Errorf("%w, %s", errDuplicateProcessor, name)
+		}
+
+		height, err := f.storage.GetCheckpoint(name)
+		if err != nil && !errors.Is(err, storageErrors.ErrNotFound) {
+			return fmt.Errorf("unable to load checkpoint of processor %s, %w", name, err)
+		}
+
+		f.checkpoints[name] = height
+	}
+
+	return nil
+}
+
+// processBlock runs the block processors that are caught up with the saved
+// blocks, within the block batch. Each processor writes to its own write set,
+// merged into the block batch only when the processor succeeds.
+// The processors that are lagging behind, or that failed,
+// catch up separately, from the saved blocks
+func (f *Fetcher) processBlock(
+	wb storage.Batch,
+	block *types.Block,
+	results []*types.TxResult,
+	from uint64,
+) {
+	var data *BlockData
+
+	for _, processor := range f.processors {
+		name := processor.Name()
+
+		if _, stalled := f.stalled[name]; stalled {
+			continue
+		}
+
+		// The processor is live if it has processed
+		// every block saved before the chunk
+		checkpoint := f.checkpoints[name]
+		if checkpoint+1 < from || checkpoint >= uint64(block.Height) {
+			continue
+		}
+
+		if data == nil {
+			data = newBlockData(block, results)
+		}
+
+		// The processor writes are kept in its own write set,
+		// and only merged into the block batch when it succeeds
+		pb := &processorBatch{}
+
+		if err := processor.ProcessBlock(pb, data); err != nil {
+			f.logger.Error(
+				"unable to process block",
+				zap.String("processor", name),
+				zap.Int64("height", block.Height),
+				zap.Error(err),
+			)
+
+			f.stalled[name] = struct{}{}
+
+			continue
+		}
+
+		if err := pb.SetCheckpoint(name, uint64(block.Height)); err != nil {
+			f.logger.Error("unable to save processor checkpoint", zap.String("processor", name), zap.Error(err))
+
+			f.stalled[name] = struct{}{}
+
+			continue
+		}
+
+		if err := pb.apply(wb); err != nil {
+			f.logger.Error("unable to save processor writes", zap.String("processor", name), zap.Error(err))
+
+			f.stalled[name] = struct{}{}
+
+			continue
+		}
+
+		f.checkpoints[name] = uint64(block.Height)
+	}
+}
+
+// catchUpProcessors replays the saved blocks to the processors lagging
+// behind, up to a chunk of blocks per processor, so the fetching is not blocked
+func (f *Fetcher) catchUpProcessors() {
+	if len(f.processors) == 0 {
+		return
+	}
+
+	latest, err := f.storage.GetLatestHeight()
+	if err != nil {
+		if !errors.Is(err, storageErrors.ErrNotFound) {
+			f.logger.Error("unable to fetch latest block height", zap.Error(err))
+		}
+
+		return
+	}
+
+	for _, processor := range f.processors {
+		from := f.checkpoints[processor.Name()] + 1
+		if from > latest {
+			continue
+		}
+
+		to := min(latest, from+uint64(f.maxChunkSize)-1)
+
+		if err := f.replay(processor, from, to); err != nil {
+			f.logger.Error(
+				"unable to catch up block processor",
+				zap.String("processor", processor.Name()),
+				zap.Uint64("from", from),
+				zap.Uint64("to", to),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// replay replays the saved blocks in the given range to the processor,
+// in a single batch. The range is replayed again if any block fails
+func (f *Fetcher) replay(processor BlockProcessor, from, to uint64) error {
+	name := processor.Name()
+	wb := f.storage.WriteBatch()
+
+	for height := from; height <= to; height++ {
+		block, err := f.storage.GetBlock(height)
+		if errors.Is(err, storageErrors.ErrNotFound) {
+			// The blocks that were unable to be saved are skipped
+			continue
+		}
+
+		if err != nil {
+			_ = wb.Rollback()
+
+			return fmt.Errorf("unable to fetch block %d, %w", height, err)
+		}
+
+		results := make([]*types.TxResult, 0, len(block.Txs))
+
+		for index := range block.Txs {
+			result, err := f.storage.GetTx(height, uint32(index))
+			if errors.Is(err, storageErrors.ErrNotFound) {
+				continue
+			}
+
+			if err != nil {
+				_ = wb.Rollback()
+
+				return fmt.Errorf("unable to fetch tx %d of block %d, %w", index, height, err)
+			}
+
+			results = append(results, result)
+		}
+
+		if err := processor.ProcessBlock(wb, newBlockData(block, results)); err != nil {
+			_ = wb.Rollback()
+
+			return fmt.Errorf("unable to process block %d, %w", height, err)
+		}
+	}
+
+	if err := wb.SetCheckpoint(name, to); err != nil {
+		_ = wb.Rollback()
+
+		return fmt.Errorf("unable to save checkpoint, %w", err)
+	}
+
+	if err := wb.Commit(); err != nil {
+		return fmt.Errorf("unable to commit processed blocks, %w", err)
+	}
+
+	f.checkpoints[name] = to
+	delete(f.stalled, name)
+
+	f.logger.Info(
+		"block processor caught up",
+		zap.String("processor", name),
+		zap.Uint64("from", from),
+		zap.Uint64("to", to),
+	)
+
+	return nil
+}
+
+// newBlockData creates the processed block data,
+// decoding the block txs from their results
+func newBlockData(block *types.Block, results []*types.TxResult) *BlockData {
+	txs := make([]*std.Tx, 0, len(results))
+
+	for _, result := range results {
+		var tx std.Tx
+
+		if err := amino.Unmarshal(result.Tx, &tx); err != nil {
+			txs = append(txs, nil)
+
+			continue
+		}
+
+		txs = append(txs, &tx)
+	}
+
+	return &BlockData{
+		Block:   block,
+		Txs:     txs,
+		Results: results,
+	}
+}
@@ -0,0 +1,219 @@
+package fetch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// recordingProcessor creates a processor that records the processed heights
+func recordingProcessor(name string, heights *[]int64) *mockProcessor {
+	return &mockProcessor{
+		name: name,
+		processBlockFn: func(_ storage.Batch, block *BlockData) error {
+			*heights = append(*heights, block.Block.Height)
+
+			return nil
+		},
+	}
+}
+
+func TestFetcher_CatchUpProcessors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lateHeights = make([]int64, 0)
+		newHeights  = make([]int64, 0)
+
+		saved = make(map[string]uint64)
+
+		wb = &mock.WriteBatch{
+			SetCheckpointFn: func(name string, height uint64) error {
+				saved[name] = height
+
+				return nil
+			},
+		}
+
+		s = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				return 5, nil
+			},
+			GetCheckpointFn: func(name string) (uint64, error) {
+				if name == "late" {
+					return 2, nil
+				}
+
+				return 0, storageErrors.ErrNotFound
+			},
+			GetBlockFn: func(height uint64) (*types.Block, error) {
+				return &types.Block{
+					Header: types.Header{Height: int64(height)},
+					Data:   types.Data{Txs: types.Txs{[]byte("tx")}},
+				}, nil
+			},
+			GetTxFn: func(height uint64, index uint32) (*types.TxResult, error) {
+				return &types.TxResult{Height: int64(height), Index: index}, nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return wb
+			},
+		}
+	)
+
+	f := &Fetcher{
+		storage:      s,
+		logger:       zap.NewNop(),
+		maxChunkSize: 2,
+		processors: []BlockProcessor{
+			recordingProcessor("late", &lateHeights),
+			recordingProcessor("new", &newHeights),
+		},
+	}
+
+	require.NoError(t, f.loadCheckpoints())
+
+	// Make sure each processor catches up on a chunk, from its own checkpoint
+	f.catchUpProcessors()
+
+	assert.Equal(t, []int64{3, 4}, lateHeights)
+	assert.Equal(t, []int64{1, 2}, newHeights)
+	assert.Equal(t, map[string]uint64{"late": 4, "new": 2}, saved)
+
+	// Make sure the processors catch up to the latest height
+	f.catchUpProcessors()
+	f.catchUpProcessors()
+	f.catchUpProcessors()
+
+	assert.Equal(t, []int64{3, 4, 5}, lateHeights)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, newHeights)
+	assert.Equal(t, map[string]uint64{"late": 5, "new": 5}, saved)
+}
+
+func TestFetcher_ProcessBlock(t *testing.T) {
+	t.Parallel()
+
+	var (
+		liveHeights = make([]int64, 0)
+		lateHeights = make([]int64, 0)
+
+		saved = make(map[string]uint64)
+
+		wb = &mock.WriteBatch{
+			SetCheckpointFn: func(name string, height uint64) error {
+				saved[name] = height
+
+				return nil
+			},
+		}
+
+		failing = &mockProcessor{
+			name: "failing",
+			processBlockFn: func(_ storage.Batch, block *BlockData) error {
+				if block.Block.Height == 11 {
+					return errors.New("unable to process")
+				}
+
+				return nil
+			},
+		}
+	)
+
+	f := &Fetcher{
+		logger: zap.NewNop(),
+		processors: []BlockProcessor{
+			recordingProcessor("live", &liveHeights),
+			recordingProcessor("late", &lateHeights),
+			failing,
+		},
+		checkpoints: map[string]uint64{
+			"live":    9,
+			"late":    3,
+			"failing": 9,
+		},
+		stalled: make(map[string]struct{}),
+	}
+
+	// Process the chunk of blocks 10 to 12
+	for height := int64(10); height <= 12; height++ {
+		f.processBlock(wb, &types.Block{Header: types.Header{Height: height}}, nil, 10)
+	}
+
+	// Make sure only the caught up processors process the blocks
+	assert.Equal(t, []int64{10, 11, 12}, liveHeights)
+	assert.Empty(t, lateHeights)
+
+	// Make sure the failed processor is stalled at its last processed block
+	assert.Equal(t, map[string]uint64{"live": 12, "failing": 10}, saved)
+	assert.Contains(t, f.stalled, "failing")
+}
+
+func TestFetcher_ProcessBlock_FailedWrites(t *testing.T) {
+	t.Parallel()
+
+	var (
+		saved  = make([]string, 0)
+		writes = make(map[string]uint64)
+
+		wb = &mock.WriteBatch{
+			SetTokenFn: func(token *ledger.Token) error {
+				saved = append(saved, token.PkgPath)
+
+				return nil
+			},
+			SetCheckpointFn: func(name string, height uint64) error {
+				writes[name] = height
+
+				return nil
+			},
+		}
+
+		// writingProcessor creates a processor that saves a token,
+		// and fails after its write, if set
+		writingProcessor = func(name string, fail bool) *mockProcessor {
+			return &mockProcessor{
+				name: name,
+				processBlockFn: func(wb storage.Batch, _ *BlockData) error {
+					if err := wb.SetToken(&ledger.Token{PkgPath: "gno.land/r/" + name}); err != nil {
+						return err
+					}
+
+					if fail {
+						return errors.New("unable to process")
+					}
+
+					return nil
+				},
+			}
+		}
+	)
+
+	f := &Fetcher{
+		logger: zap.NewNop(),
+		processors: []BlockProcessor{
+			writingProcessor("failing", true),
+			writingProcessor("live", false),
+		},
+		checkpoints: map[string]uint64{
+			"failing": 9,
+			"live":    9,
+		},
+		stalled: make(map[string]struct{}),
+	}
+
+	f.processBlock(wb, &types.Block{Header: types.Header{Height: 10}}, nil, 10)
+
+	// Make sure only the writes of the successful processor are in the block batch
+	assert.Equal(t, []string{"gno.land/r/live"}, saved)
+	assert.Equal(t, map[string]uint64{"live": 10}, writes)
+	assert.Contains(t, f.stalled, "failing")
+}
@@ -0,0 +1,185 @@
+package fetch
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/rules"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// applyRules evaluates the tx message against the detection rules,
+// and takes the actions of the matched rules. The packages already
+// in the token ledger are only tagged, and not registered again.
+// The called realms are never registered, since the called realm
+// is not the token, ex. a token factory. The tokens referenced by
+// the tx events are registered by the event detector instead
+func (f *Fetcher) applyRules(wb storage.Batch, subject *rules.Subject, txResult *types.TxResult) error {
+	matches := f.rules.Evaluate(subject)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var (
+		txHash   = base64.StdEncoding.EncodeToString(txResult.Tx.Hash())
+		register *rules.Action
+		tags     = make([]string, 0)
+	)
+
+	for _, match := range matches {
+		f.logger.Debug(
+			"rule matched",
+			zap.String("rule", match.Rule),
+			zap.String("action", match.Action.Name),
+			zap.String("pkgPath", subject.PkgPath),
+		)
+
+		switch match.Action.Type {
+		case rules.ActionRegister:
+			if subject.Call {
+				f.logger.Warn(
+					"register action ignored for a realm call",
+					zap.String("action", match.Action.Name),
+					zap.String("pkgPath", subject.PkgPath),
+				)
+
+				continue
+			}
+
+			// The token is registered once, with the first matched action
+			if register == nil {
+				register = match.Action
+			}
+		case rules.ActionTag:
+			tags = appendTags(tags, match.Action.Tags...)
+		case rules.ActionWebhook:
+			if f.outbox == nil {
+				f.logger.Warn("no webhook outbox configured", zap.String("action", match.Action.Name))
+
+				continue
+			}
+
+			// The match is delivered once the block batch is committed
+			notification := &ledger.Notification{
+				Endpoint: match.Action.URL,
+				Event:    ledger.EventRuleMatched,
+				Time:     time.Now(),
+				Match: &ledger.RuleMatch{
+					Rule:     match.Rule,
+					Action:   match.Action.Name,
+					PkgPath:  subject.PkgPath,
+					Deployer: subject.Deployer,
+					TxHash:   txHash,
+					Height:   txResult.Height,
+				},
+			}
+
+			if err := f.outbox.Enqueue(wb, notification); err != nil {
+				return fmt.Errorf("unable to enqueue rule match notification of %s, %w", subject.PkgPath, err)
+			}
+		}
+	}
+
+	existing, err := f.storage.GetToken(subject.PkgPath)
+	if err == nil {
+		tags = appendTags(append([]string{}, existing.Tags...), tags...)
+	}
+
+	// Tag the token already in the ledger. Only
+	// the tagged packages can still be registered
+	if err == nil && (register == nil || existing.Status != ledger.StatusTagged) {
+		if len(tags) > len(existing.Tags) {
+			existing.Tags = tags
+			existing.UpdatedAt = time.Now()
+
+			if err := wb.SetToken(existing); err != nil {
+				f.logger.Error("unable to tag token", zap.String("pkgPath", subject.PkgPath), zap.Error(err))
+			}
+		}
+
+		return nil
+	}
+
+	if register == nil && len(tags) == 0 {
+		return nil
+	}
+
+	token := ruleToken(subject, register)
+	token.DeployTxHash = txHash
+	token.Height = txResult.Height
+
+	if len(tags) > 0 {
+		token.Tags = tags
+	}
+
+	if register != nil {
+		return f.intend(wb, token)
+	}
+
+	// The tagged packages are kept in the ledger, without a registration
+	token.Status = ledger.StatusTagged
+	token.UpdatedAt = time.Now()
+
+	if err := wb.SetToken(token); err != nil {
+		f.logger.Error("unable to save tagged package", zap.String("pkgPath", subject.PkgPath), zap.Error(err))
+	}
+
+	return nil
+}
+
+// ruleToken creates the ledger record of the matched package,
+// with the token metadata detected in its source, if any
+func ruleToken(subject *rules.Subject, register *rules.Action) *ledger.Token {
+	token := &ledger.Token{}
+
+	if len(subject.Files) > 0 {
+		detection := detector.Detect(detector.ExportedFuncs(subject.Files), subject.Files, true)
+		if detection.Token != nil {
+			token = detection.Token
+		}
+	}
+
+	token.PkgPath = subject.PkgPath
+	token.Deployer = subject.Deployer
+
+	if register != nil && register.Kind != "" {
+		token.Kind = register.Kind
+	}
+
+	if register != nil {
+		token.Targets = register.Targets
+	}
+
+	if token.Kind == "" {
+		token.Kind = ledger.KindGRC20
+	}
+
+	return token
+}
+
+// appendTags appends the tags that are not already set
+func appendTags(tags []string, added ...string) []string {
+	for _, tag := range added {
+		exists := false
+
+		for _, existing := range tags {
+			if existing == tag {
+				exists = true
+
+				break
+			}
+		}
+
+		if !exists {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,262 @@
+package fetch
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/rules"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const testRules = `{
+	"actions": [
+		{"name": "register", "type": "register", "targets": ["gnoswap"]},
+		{"name": "tag-grc20", "type": "tag", "tags": ["grc20"]},
+		{"name": "tag-factory", "type": "tag", "tags": ["factory"]}
+	],
+	"rules": [
+		{"name": "grc20", "match": {"source": "grc20\\.NewBanker\\("}, "action": "register"},
+		{"name": "grc20 tag", "match": {"source": "grc20\\.NewBanker\\("}, "action": "tag-grc20"},
+		{"name": "factory", "match": {"event": "TokenCreated"}, "action": "register"},
+		{"name": "factory tag", "match": {"event": "TokenCreated"}, "action": "tag-factory"}
+	]
+}`
+
+// newRulesEngine creates the rules engine, with the test rules
+func newRulesEngine(t *testing.T) *rules.Engine {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(testRules), 0o600))
+
+	engine, err := rules.New(path)
+	require.NoError(t, err)
+
+	return engine
+}
+
+func TestFetcher_ApplyRules(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tokenFiles = []detector.File{{Name: "foo.gno", Body: grc20Source}}
+		plainFiles = []detector.File{{Name: "foo.gno", Body: "package foo"}}
+	)
+
+	testTable := []struct {
+		name     string
+		subject  *rules.Subject
+		existing *ledger.Token
+
+		intent *ledger.Token // the expected registration intent, if any
+		saved  *ledger.Token // the expected saved token, if any
+	}{
+		{
+			"token deployment",
+			&rules.Subject{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Files: tokenFiles},
+			nil,
+			&ledger.Token{
+				PkgPath: "gno.land/r/demo/foo",
+				Symbol:  "FOO",
+				Tags:    []string{"grc20"},
+				Targets: []string{"gnoswap"},
+			},
+			nil,
+		},
+		{
+			"realm call, only tagged",
+			&rules.Subject{PkgPath: "gno.land/r/demo/factory", Deployer: "g1alice", Call: true, Events: []string{"TokenCreated"}},
+			nil,
+			nil,
+			&ledger.Token{
+				PkgPath: "gno.land/r/demo/factory",
+				Status:  ledger.StatusTagged,
+				Tags:    []string{"factory"},
+			},
+		},
+		{
+			"tagged package registered",
+			&rules.Subject{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Files: tokenFiles},
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Status: ledger.StatusTagged, Tags: []string{"factory"}},
+			&ledger.Token{
+				PkgPath: "gno.land/r/demo/foo",
+				Symbol:  "FOO",
+				Tags:    []string{"factory", "grc20"},
+				Targets: []string{"gnoswap"},
+			},
+			nil,
+		},
+		{
+			"registered token only tagged",
+			&rules.Subject{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Files: tokenFiles},
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Status: ledger.StatusRegistered},
+			nil,
+			&ledger.Token{
+				PkgPath: "gno.land/r/demo/foo",
+				Status:  ledger.StatusRegistered,
+				Tags:    []string{"grc20"},
+			},
+		},
+		{
+			"no match",
+			&rules.Subject{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Files: plainFiles},
+			nil,
+			nil,
+			nil,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				intents = make([]*ledger.Token, 0)
+				saved   = make([]*ledger.Token, 0)
+
+				wb = &mock.WriteBatch{
+					SetIntentFn: func(token *ledger.Token) error {
+						intents = append(intents, token)
+
+						return nil
+					},
+					SetTokenFn: func(token *ledger.Token) error {
+						saved = append(saved, token)
+
+						return nil
+					},
+				}
+
+				s = &mock.Storage{
+					GetTokenFn: func(pkgPath string) (*ledger.Token, error) {
+						if testCase.existing == nil {
+							return nil, storageErrors.ErrNotFound
+						}
+
+						return testCase.existing, nil
+					},
+				}
+			)
+
+			f := &Fetcher{
+				storage: s,
+				rules:   newRulesEngine(t),
+				logger:  zap.NewNop(),
+			}
+
+			require.NoError(t, f.applyRules(wb, testCase.subject, &types.TxResult{Height: 10, Tx: []byte("tx")}))
+
+			if testCase.intent == nil {
+				assert.Empty(t, intents)
+			} else {
+				require.Len(t, intents, 1)
+
+				assert.Equal(t, testCase.intent.PkgPath, intents[0].PkgPath)
+				assert.Equal(t, testCase.intent.Symbol, intents[0].Symbol)
+				assert.Equal(t, testCase.intent.Tags, intents[0].Tags)
+				assert.Equal(t, testCase.intent.Targets, intents[0].Targets)
+				assert.Equal(t, int64(10), intents[0].Height)
+			}
+
+			if testCase.saved == nil {
+				assert.Empty(t, saved)
+
+				return
+			}
+
+			require.Len(t, saved, 1)
+
+			assert.Equal(t, testCase.saved.PkgPath, saved[0].PkgPath)
+			assert.Equal(t, testCase.saved.Status, saved[0].Status)
+			assert.Equal(t, testCase.saved.Tags, saved[0].Tags)
+			assert.Empty(t, saved[0].Targets)
+		})
+	}
+}
+
+func TestFetcher_ApplyRules_Webhook(t *testing.T) {
+	t.Parallel()
+
+	const webhookRules = `{
+		"actions": [{"name": "notify", "type": "webhook", "url": "http://localhost/hook"}],
+		"rules": [{"name": "factory", "match": {"event": "TokenCreated"}, "action": "notify"}]
+	}`
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(webhookRules), 0o600))
+
+	engine, err := rules.New(path)
+	require.NoError(t, err)
+
+	var (
+		wb = &mock.WriteBatch{}
+
+		notifications = make([]*ledger.Notification, 0)
+	)
+
+	f := &Fetcher{
+		storage: &mock.Storage{
+			GetTokenFn: func(string) (*ledger.Token, error) {
+				return nil, storageErrors.ErrNotFound
+			},
+		},
+		rules: engine,
+		outbox: &mockOutbox{
+			enqueueFn: func(batch storage.Batch, notification *ledger.Notification) error {
+				// Make sure the match is enqueued in the block batch
+				assert.Equal(t, wb, batch)
+
+				notifications = append(notifications, notification)
+
+				return nil
+			},
+		},
+		logger: zap.NewNop(),
+	}
+
+	subject := &rules.Subject{
+		PkgPath:  "gno.land/r/demo/factory",
+		Deployer: "g1alice",
+		Call:     true,
+		Events:   []string{"TokenCreated"},
+	}
+
+	require.NoError(t, f.applyRules(wb, subject, &types.TxResult{Height: 10, Tx: []byte("tx")}))
+
+	// Make sure the match is notified to the action endpoint, through the outbox
+	require.Len(t, notifications, 1)
+
+	assert.Equal(t, "http://localhost/hook", notifications[0].Endpoint)
+	assert.Equal(t, ledger.EventRuleMatched, notifications[0].Event)
+	assert.Equal(t, &ledger.RuleMatch{
+		Rule:     "factory",
+		Action:   "notify",
+		PkgPath:  subject.PkgPath,
+		Deployer: subject.Deployer,
+		TxHash:   base64.StdEncoding.EncodeToString(types.Tx("tx").Hash()),
+		Height:   10,
+	}, notifications[0].Match)
+
+	// Make sure the batch is not committed if the match is unable to be enqueued
+	f.outbox = &mockOutbox{
+		enqueueFn: func(storage.Batch, *ledger.Notification) error {
+			return errors.New("unknown webhook endpoint")
+		},
+	}
+
+	assert.Error(t, f.applyRules(wb, subject, &types.TxResult{Height: 10, Tx: []byte("tx")}))
+}
@@ -2,9 +2,13 @@ package fetch
 
 import (
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
 
 	clientTypes "github.com/gnolang/tx-indexer/client/types"
 	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
 )
 
 // Client defines the interface for the node (client) communication
@@ -28,3 +32,44 @@ type Events interface {
 	// SignalEvent signals a new event to the event manager
 	SignalEvent(events.Event)
 }
+
+// Registrar is the token registration API
+type Registrar interface {
+	// NotifyIntents signals the newly committed registration intents
+	NotifyIntents()
+
+	// Deployed handles the deployment of a package on chain
+	Deployed(pkgPath string)
+}
+
+// Outbox is the lifecycle webhook outbox API
+type Outbox interface {
+	// Enqueue writes the lifecycle notification to the outbox,
+	// in the batch, so it is only delivered once the batch is committed
+	Enqueue(wb storage.Batch, notification *ledger.Notification) error
+}
+
+// BlockProcessor processes the fetched blocks, in height order.
+// Each processor keeps its own checkpoint, so it catches up
+// on the saved blocks independently, ex. when it is added later
+type BlockProcessor interface {
+	// Name returns the unique name of the processor, its checkpoint key
+	Name() string
+
+	// ProcessBlock processes the block. The processor writes are saved
+	// in the same storage batch as the block, and its checkpoint,
+	// only if it succeeds. The processor must not commit the batch
+	ProcessBlock(wb storage.Batch, block *BlockData) error
+}
+
+// BlockData is a committed block, with its decoded txs and results
+type BlockData struct {
+	Block *types.Block
+
+	// Txs are the decoded block txs, nil
+	// for the txs that are unable to be decoded
+	Txs []*std.Tx
+
+	// Results are the block tx results, in tx index order
+	Results []*types.TxResult
+}
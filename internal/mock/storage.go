@@ -2,7 +2,9 @@ package mock
 
 import (
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
 
+	"github.com/gnolang/tx-indexer/ledger"
 	"github.com/gnolang/tx-indexer/storage"
 )
 
@@ -14,6 +16,16 @@ type Storage struct {
 	GetBlockFn             func(uint64) (*types.Block, error)
 	GetTxFn                func(uint64, uint32) (*types.TxResult, error)
 	GetTxByHashFn          func(string) (*types.TxResult, error)
+	GetTokenFn             func(string) (*ledger.Token, error)
+	GetTokensFn            func() ([]*ledger.Token, error)
+	GetSpendsFn            func() ([]*ledger.Spend, error)
+	GetPackageFn           func(string) (*std.MemPackage, error)
+	GetRegistryVersionFn   func() (string, error)
+	GetGenesisImportedFn   func() (bool, error)
+	GetCheckpointFn        func(string) (uint64, error)
+	GetIntentsFn           func() ([]*ledger.Token, error)
+	GetRegistrationTxsFn   func(string) ([]*ledger.RegistrationTx, error)
+	GetNotificationsFn     func() ([]*ledger.Notification, error)
 }
 
 func (m *Storage) GetLatestHeight() (uint64, error) {
@@ -50,6 +62,96 @@ func (m *Storage) GetTxByHash(txHash string) (*types.TxResult, error) {
 	panic("not implemented")
 }
 
+// GetToken fetches the token ledger record using the token package path
+func (m *Storage) GetToken(pkgPath string) (*ledger.Token, error) {
+	if m.GetTokenFn != nil {
+		return m.GetTokenFn(pkgPath)
+	}
+
+	panic("not implemented")
+}
+
+// GetTokens fetches all the token ledger records
+func (m *Storage) GetTokens() ([]*ledger.Token, error) {
+	if m.GetTokensFn != nil {
+		return m.GetTokensFn()
+	}
+
+	panic("not implemented")
+}
+
+// GetSpends fetches all the registrar spend records
+func (m *Storage) GetSpends() ([]*ledger.Spend, error) {
+	if m.GetSpendsFn != nil {
+		return m.GetSpendsFn()
+	}
+
+	panic("not implemented")
+}
+
+// GetPackage fetches the deployed package using its package path
+func (m *Storage) GetPackage(pkgPath string) (*std.MemPackage, error) {
+	if m.GetPackageFn != nil {
+		return m.GetPackageFn(pkgPath)
+	}
+
+	panic("not implemented")
+}
+
+// GetRegistryVersion returns the active registry version
+func (m *Storage) GetRegistryVersion() (string, error) {
+	if m.GetRegistryVersionFn != nil {
+		return m.GetRegistryVersionFn()
+	}
+
+	panic("not implemented")
+}
+
+// GetGenesisImported returns the genesis import marker
+func (m *Storage) GetGenesisImported() (bool, error) {
+	if m.GetGenesisImportedFn != nil {
+		return m.GetGenesisImportedFn()
+	}
+
+	panic("not implemented")
+}
+
+// GetIntents returns the registration intents
+func (m *Storage) GetIntents() ([]*ledger.Token, error) {
+	if m.GetIntentsFn != nil {
+		return m.GetIntentsFn()
+	}
+
+	panic("not implemented")
+}
+
+// GetRegistrationTxs returns the registration txs linked to the token
+func (m *Storage) GetRegistrationTxs(tokenID string) ([]*ledger.RegistrationTx, error) {
+	if m.GetRegistrationTxsFn != nil {
+		return m.GetRegistrationTxsFn(tokenID)
+	}
+
+	panic("not implemented")
+}
+
+// GetNotifications returns the undelivered webhook notifications
+func (m *Storage) GetNotifications() ([]*ledger.Notification, error) {
+	if m.GetNotificationsFn != nil {
+		return m.GetNotificationsFn()
+	}
+
+	panic("not implemented")
+}
+
+// GetCheckpoint returns the latest height processed by the block processor
+func (m *Storage) GetCheckpoint(name string) (uint64, error) {
+	if m.GetCheckpointFn != nil {
+		return m.GetCheckpointFn(name)
+	}
+
+	panic("not implemented")
+}
+
 // BlockIterator iterates over Blocks, limiting the results to be between the provided block numbers
 func (m *Storage) BlockIterator(_, _ uint64) (storage.Iterator[*types.Block], error) {
 	panic("not implemented") // TODO: Implement
@@ -84,6 +186,20 @@ type WriteBatch struct {
 	SetLatestHeightFn func(uint64) error
 	SetBlockFn        func(*types.Block) error
 	SetTxFn           func(*types.TxResult) error
+	SetTokenFn        func(*ledger.Token) error
+	SetSpendFn        func(*ledger.Spend) error
+	SetPackageFn      func(*std.MemPackage) error
+	SetVersionFn      func(string) error
+	SetGenesisFn      func() error
+	SetCheckpointFn   func(string, uint64) error
+	SetIntentFn       func(*ledger.Token) error
+	DeleteIntentFn    func(string) error
+
+	SetRegistrationTxFn  func(*ledger.RegistrationTx) error
+	SetNotificationFn    func(*ledger.Notification) error
+	DeleteNotificationFn func(uint64) error
+
+	CommitFn func() error
 }
 
 // SetLatestHeight saves the latest block height to the storage
@@ -113,9 +229,112 @@ func (mb *WriteBatch) SetTx(tx *types.TxResult) error {
 	return nil
 }
 
+// SetToken saves the token ledger record to the permanent storage
+func (mb *WriteBatch) SetToken(token *ledger.Token) error {
+	if mb.SetTokenFn != nil {
+		return mb.SetTokenFn(token)
+	}
+
+	return nil
+}
+
+// SetSpend saves the registrar spend record to the permanent storage
+func (mb *WriteBatch) SetSpend(spend *ledger.Spend) error {
+	if mb.SetSpendFn != nil {
+		return mb.SetSpendFn(spend)
+	}
+
+	return nil
+}
+
+// SetPackage saves the deployed package to the permanent storage
+func (mb *WriteBatch) SetPackage(pkg *std.MemPackage) error {
+	if mb.SetPackageFn != nil {
+		return mb.SetPackageFn(pkg)
+	}
+
+	return nil
+}
+
+// SetRegistryVersion saves the active registry version to the storage
+func (mb *WriteBatch) SetRegistryVersion(version string) error {
+	if mb.SetVersionFn != nil {
+		return mb.SetVersionFn(version)
+	}
+
+	return nil
+}
+
+// SetGenesisImported marks the genesis as imported
+func (mb *WriteBatch) SetGenesisImported() error {
+	if mb.SetGenesisFn != nil {
+		return mb.SetGenesisFn()
+	}
+
+	return nil
+}
+
+// SetIntent saves the registration intent
+func (mb *WriteBatch) SetIntent(token *ledger.Token) error {
+	if mb.SetIntentFn != nil {
+		return mb.SetIntentFn(token)
+	}
+
+	return nil
+}
+
+// DeleteIntent removes the registration intent
+func (mb *WriteBatch) DeleteIntent(pkgPath string) error {
+	if mb.DeleteIntentFn != nil {
+		return mb.DeleteIntentFn(pkgPath)
+	}
+
+	return nil
+}
+
+// SetRegistrationTx saves the registration tx linked to the token
+func (mb *WriteBatch) SetRegistrationTx(tx *ledger.RegistrationTx) error {
+	if mb.SetRegistrationTxFn != nil {
+		return mb.SetRegistrationTxFn(tx)
+	}
+
+	return nil
+}
+
+// SetNotification saves the undelivered webhook notification
+func (mb *WriteBatch) SetNotification(notification *ledger.Notification) error {
+	if mb.SetNotificationFn != nil {
+		return mb.SetNotificationFn(notification)
+	}
+
+	return nil
+}
+
+// DeleteNotification removes the delivered webhook notification
+func (mb *WriteBatch) DeleteNotification(id uint64) error {
+	if mb.DeleteNotificationFn != nil {
+		return mb.DeleteNotificationFn(id)
+	}
+
+	return nil
+}
+
+// SetCheckpoint saves the latest height processed by the block processor
+func (mb *WriteBatch) SetCheckpoint(name string, height uint64) error {
+	if mb.SetCheckpointFn != nil {
+		return mb.SetCheckpointFn(name, height)
+	}
+
+	return nil
+}
+
 // Commit stores all the provided info on the storage and make
 // it available for other storage readers
 func (mb *WriteBatch) Commit() error {
+	if mb.CommitFn != nil {
+		return mb.CommitFn()
+	}
+
 	return nil
 }
 
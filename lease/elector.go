@@ -0,0 +1,239 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultTTL is the default lease duration
+const DefaultTTL = 15 * time.Second
+
+// Elector runs the leader-only services while it holds the single-writer
+// lease. The lease is renewed periodically, and the leader steps down if
+// a renewal fails before the lease expires. The standby instances keep
+// trying to acquire the lease, and take over once it expires
+type Elector struct {
+	locker Locker
+	holder string
+
+	ttl    time.Duration
+	logger *zap.Logger
+
+	lease *Lease // the held lease, if any
+	leads int    // the number of running Lead wrappers
+	mux   sync.RWMutex
+}
+
+// New creates a new lease elector, for the given holder
+func New(locker Locker, holder string, opts ...Option) *Elector {
+	e := &Elector{
+		locker: locker,
+		holder: holder,
+		ttl:    DefaultTTL,
+		logger: zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Lead wraps the leader-only service, so it runs only while the lease is
+// held. The service context is cancelled as soon as the lease is lost, and
+// the service is started again once the lease is acquired again.
+// Every wrapped service shares the same lease, which is only
+// released once the last of them returns
+func (e *Elector) Lead(service func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+
+		var (
+			stop context.CancelFunc
+			done chan error // the running service result, if leading
+		)
+
+		// stepDown stops the running service, and waits for it to return
+		stepDown := func() error {
+			stop()
+
+			err := <-done
+			done = nil
+
+			return err
+		}
+
+		e.join()
+		defer e.leave()
+
+		for {
+			leading := e.renew()
+
+			switch {
+			case leading && done == nil:
+				stop, done = start(ctx, service)
+			case !leading && done != nil:
+				if err := stepDown(); err != nil {
+					return err
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				e.logger.Info("lease elector shut down")
+
+				if done != nil {
+					return stepDown()
+				}
+
+				return nil
+			case err := <-done:
+				// The service stopped on its own
+				stop()
+
+				return err
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// start starts the leader-only service, returning
+// its stop function, and its result channel
+func start(ctx context.Context, service func(ctx context.Context) error) (context.CancelFunc, chan error) {
+	serviceCtx, stop := context.WithCancel(ctx)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- service(serviceCtx)
+	}()
+
+	return stop, done
+}
+
+// Validate checks that the lease is still held by the elector, with the
+// fencing token it was acquired with. It is called before every side effect
+// of the leader, so a stale leader never acts on a lease taken over by the standby
+func (e *Elector) Validate() error {
+	e.mux.RLock()
+	held := e.lease
+	e.mux.RUnlock()
+
+	if held == nil {
+		return ErrNotLeader
+	}
+
+	current, err := e.locker.Current()
+	if err != nil {
+		return fmt.Errorf("unable to validate lease, %w", err)
+	}
+
+	if current.Token != held.Token || !current.HeldBy(e.holder, time.Now()) {
+		return fmt.Errorf("%w, fencing token %d superseded by %d", ErrNotLeader, held.Token, current.Token)
+	}
+
+	return nil
+}
+
+// Leading checks if the elector holds the lease
+func (e *Elector) Leading() bool {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	return e.lease != nil
+}
+
+// renew acquires, or renews the lease. It returns a flag
+// indicating if the lease is held after the renewal
+func (e *Elector) renew() bool {
+	acquired, err := e.locker.Acquire(e.holder, e.ttl)
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	switch {
+	case err == nil:
+		if e.lease == nil || e.lease.Token != acquired.Token {
+			e.logger.Info("acquired lease", zap.String("holder", e.holder), zap.Uint64("token", acquired.Token))
+		}
+
+		e.lease = acquired
+
+		return true
+	case errors.Is(err, ErrHeld):
+		if e.lease != nil {
+			e.logger.Warn("lease taken over, stepping down", zap.Error(err))
+		}
+
+		e.lease = nil
+
+		return false
+	default:
+		e.logger.Error("unable to renew lease", zap.Error(err))
+
+		// The lease is kept only while it is safe to assume it has
+		// not expired before the next renewal. Otherwise, the leader
+		// steps down before the standby is able to take over
+		if e.lease != nil && time.Now().Add(e.ttl/3).Before(e.lease.Expires) {
+			return true
+		}
+
+		if e.lease != nil {
+			e.logger.Warn("lease about to expire, stepping down")
+		}
+
+		e.lease = nil
+
+		return false
+	}
+}
+
+// join registers a running Lead wrapper, sharing the lease
+func (e *Elector) join() {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.leads++
+}
+
+// leave unregisters the returned Lead wrapper, and releases
+// the lease once no other wrapped service is running
+func (e *Elector) leave() {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.leads--
+
+	if e.leads > 0 {
+		return
+	}
+
+	e.release()
+}
+
+// release releases the held lease, if any, so the standby
+// is able to take over without waiting for it to expire.
+// The elector mutex must be held
+func (e *Elector) release() {
+	if e.lease == nil {
+		return
+	}
+
+	e.lease = nil
+
+	if err := e.locker.Release(e.holder); err != nil {
+		e.logger.Error("unable to release lease", zap.Error(err))
+
+		return
+	}
+
+	e.logger.Info("released lease", zap.String("holder", e.holder))
+}
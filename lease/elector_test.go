@@ -0,0 +1,179 @@
+package lease
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runElector runs the leader-only service under the elector,
+// and returns the flag indicating if the service is running
+func runElector(t *testing.T, ctx context.Context, e *Elector) (*atomic.Bool, <-chan error) {
+	t.Helper()
+
+	var (
+		running = &atomic.Bool{}
+		done    = make(chan error, 1)
+	)
+
+	service := e.Lead(func(ctx context.Context) error {
+		running.Store(true)
+		defer running.Store(false)
+
+		<-ctx.Done()
+
+		return nil
+	})
+
+	go func() {
+		done <- service(ctx)
+	}()
+
+	return running, done
+}
+
+func TestElector_Lead(t *testing.T) {
+	t.Parallel()
+
+	var (
+		locker = NewFileLocker(filepath.Join(t.TempDir(), "lease.json"))
+		ttl    = 150 * time.Millisecond
+
+		primary = New(locker, "primary", WithTTL(ttl))
+		standby = New(locker, "standby", WithTTL(ttl))
+	)
+
+	primaryCtx, cancelPrimary := context.WithCancel(context.Background())
+	defer cancelPrimary()
+
+	standbyCtx, cancelStandby := context.WithCancel(context.Background())
+	defer cancelStandby()
+
+	primaryRunning, primaryDone := runElector(t, primaryCtx, primary)
+
+	require.Eventually(t, primaryRunning.Load, time.Second, 10*time.Millisecond)
+	require.NoError(t, primary.Validate())
+
+	standbyRunning, standbyDone := runElector(t, standbyCtx, standby)
+
+	// Make sure the standby doesn't run the service,
+	// while the primary renews the lease
+	time.Sleep(2 * ttl)
+
+	assert.False(t, standbyRunning.Load())
+	assert.ErrorIs(t, standby.Validate(), ErrNotLeader)
+
+	// Make sure the standby takes over once the primary stops
+	cancelPrimary()
+	require.NoError(t, <-primaryDone)
+
+	assert.False(t, primaryRunning.Load())
+
+	require.Eventually(t, standbyRunning.Load, time.Second, 10*time.Millisecond)
+	require.NoError(t, standby.Validate())
+
+	assert.ErrorIs(t, primary.Validate(), ErrNotLeader)
+
+	current, err := locker.Current()
+	require.NoError(t, err)
+
+	assert.Equal(t, "standby", current.Holder)
+	assert.Equal(t, uint64(2), current.Token)
+
+	cancelStandby()
+	require.NoError(t, <-standbyDone)
+}
+
+func TestElector_Validate_Superseded(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Now()
+		locker = newTestLocker(t, &now)
+
+		e = New(locker, "primary", WithTTL(time.Minute))
+	)
+
+	require.True(t, e.renew())
+	require.NoError(t, e.Validate())
+
+	// Simulate a takeover, after the lease expired
+	// without the primary noticing
+	now = now.Add(2 * time.Minute)
+
+	_, err := locker.Acquire("standby", time.Minute)
+	require.NoError(t, err)
+
+	// Make sure the stale leader is fenced off
+	assert.ErrorIs(t, e.Validate(), ErrNotLeader)
+	assert.True(t, e.Leading())
+
+	assert.False(t, e.renew())
+	assert.False(t, e.Leading())
+}
+
+func TestElector_Lead_Shared(t *testing.T) {
+	t.Parallel()
+
+	var (
+		locker = NewFileLocker(filepath.Join(t.TempDir(), "lease.json"))
+		ttl    = 150 * time.Millisecond
+
+		e = New(locker, "primary", WithTTL(ttl))
+
+		exit   = make(chan struct{})
+		exited = make(chan error, 1)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Run a service that exits on its own,
+	// next to a service that keeps running
+	exiting := e.Lead(func(ctx context.Context) error {
+		select {
+		case <-exit:
+		case <-ctx.Done():
+		}
+
+		return nil
+	})
+
+	go func() {
+		exited <- exiting(ctx)
+	}()
+
+	running, done := runElector(t, ctx, e)
+
+	require.Eventually(t, running.Load, time.Second, 10*time.Millisecond)
+
+	close(exit)
+	require.NoError(t, <-exited)
+
+	// Make sure the lease is still held for the running service
+	time.Sleep(2 * ttl)
+
+	assert.True(t, running.Load())
+	require.NoError(t, e.Validate())
+
+	current, err := locker.Current()
+	require.NoError(t, err)
+
+	assert.Equal(t, "primary", current.Holder)
+	assert.Equal(t, uint64(1), current.Token)
+
+	// Make sure the lease is released once the last service returns
+	cancel()
+	require.NoError(t, <-done)
+
+	current, err = locker.Current()
+	require.NoError(t, err)
+
+	assert.Empty(t, current.Holder)
+	assert.False(t, e.Leading())
+}
@@ -0,0 +1,188 @@
+package lease
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// staleGuard is the age after which the update guard
+// is considered left behind by a crashed update
+const staleGuard = 10 * time.Second
+
+// ErrBusy is returned when the lease file is being updated by another holder
+var ErrBusy = errors.New("lease file is being updated")
+
+// FileLocker is the local lease storage, a JSON lock file
+// on a (shared) volume. The lease updates are guarded by an
+// exclusively created guard file, and written by an atomic rename
+type FileLocker struct {
+	path string
+
+	now func() time.Time
+}
+
+// NewFileLocker creates a new lock file lease storage
+func NewFileLocker(path string) *FileLocker {
+	return &FileLocker{
+		path: path,
+		now:  time.Now,
+	}
+}
+
+// Acquire acquires the lease for the holder, or renews the lease already
+// held by it. The fencing token is incremented if the lease changes hands
+func (f *FileLocker) Acquire(holder string, ttl time.Duration) (*Lease, error) {
+	var acquired *Lease
+
+	err := f.update(func(current *Lease, now time.Time) (*Lease, error) {
+		if current.Holder != holder && current.HeldBy(current.Holder, now) {
+			return nil, fmt.Errorf("%w, %s", ErrHeld, current.Holder)
+		}
+
+		acquired = &Lease{
+			Holder:  holder,
+			Token:   current.Token,
+			Expires: now.Add(ttl),
+		}
+
+		if current.Holder != holder {
+			acquired.Token++
+		}
+
+		return acquired, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return acquired, nil
+}
+
+// Release releases the lease, if it is held by the holder.
+// The fencing token is kept, so the next holder increments it
+func (f *FileLocker) Release(holder string) error {
+	return f.update(func(current *Lease, _ time.Time) (*Lease, error) {
+		if current.Holder != holder {
+			return nil, nil
+		}
+
+		return &Lease{
+			Token: current.Token,
+		}, nil
+	})
+}
+
+// Current returns the current lease, which is empty if the lease file doesn't exist
+func (f *FileLocker) Current() (*Lease, error) {
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Lease{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read lease file, %w", err)
+	}
+
+	var lease Lease
+
+	if err := json.Unmarshal(raw, &lease); err != nil {
+		return nil, fmt.Errorf("unable to parse lease file, %w", err)
+	}
+
+	return &lease, nil
+}
+
+// update applies the change to the current lease, under the update
+// guard. A nil lease returned by the change leaves the lease file untouched
+func (f *FileLocker) update(change func(current *Lease, now time.Time) (*Lease, error)) error {
+	release, err := f.guard()
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	current, err := f.Current()
+	if err != nil {
+		return err
+	}
+
+	next, err := change(current, f.now())
+	if err != nil || next == nil {
+		return err
+	}
+
+	return f.write(next)
+}
+
+// guard exclusively creates the update guard file,
+// returning the function that removes it
+func (f *FileLocker) guard() (func(), error) {
+	path := f.path + ".lock"
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		// The guard left by a crashed update is removed,
+		// and the update is attempted again by the next call
+		if info, statErr := os.Stat(path); statErr == nil && f.now().Sub(info.ModTime()) > staleGuard {
+			_ = os.Remove(path)
+		}
+
+		return nil, ErrBusy
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create lease guard, %w", err)
+	}
+
+	_ = file.Close()
+
+	return func() {
+		_ = os.Remove(path)
+	}, nil
+}
+
+// write atomically replaces the lease file
+func (f *FileLocker) write(lease *Lease) error {
+	raw, err := json.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("unable to marshal lease, %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to create lease file, %w", err)
+	}
+
+	// The temporary file is already renamed on success
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("unable to write lease file, %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("unable to sync lease file, %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to close lease file, %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("unable to replace lease file, %w", err)
+	}
+
+	return nil
+}
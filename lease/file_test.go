@@ -0,0 +1,137 @@
+package lease
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLocker creates a file locker with a controlled clock
+func newTestLocker(t *testing.T, now *time.Time) *FileLocker {
+	t.Helper()
+
+	f := NewFileLocker(filepath.Join(t.TempDir(), "lease.json"))
+	f.now = func() time.Time {
+		return *now
+	}
+
+	return f
+}
+
+func TestFileLocker_Acquire(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now = time.Now()
+		ttl = 10 * time.Second
+
+		f = newTestLocker(t, &now)
+	)
+
+	// Make sure the lease is acquired with the first fencing token
+	lease, err := f.Acquire("primary", ttl)
+	require.NoError(t, err)
+
+	assert.Equal(t, "primary", lease.Holder)
+	assert.Equal(t, uint64(1), lease.Token)
+	assert.WithinDuration(t, now.Add(ttl), lease.Expires, 0)
+
+	// Make sure the standby is unable to acquire the held lease
+	_, err = f.Acquire("standby", ttl)
+	assert.ErrorIs(t, err, ErrHeld)
+
+	// Make sure the renewal keeps the fencing token
+	now = now.Add(ttl / 2)
+
+	lease, err = f.Acquire("primary", ttl)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), lease.Token)
+	assert.WithinDuration(t, now.Add(ttl), lease.Expires, 0)
+
+	// Make sure the standby takes over the expired lease,
+	// with the next fencing token
+	now = now.Add(ttl)
+
+	lease, err = f.Acquire("standby", ttl)
+	require.NoError(t, err)
+
+	assert.Equal(t, "standby", lease.Holder)
+	assert.Equal(t, uint64(2), lease.Token)
+
+	// Make sure the stale holder is unable to renew
+	_, err = f.Acquire("primary", ttl)
+	assert.ErrorIs(t, err, ErrHeld)
+
+	current, err := f.Current()
+	require.NoError(t, err)
+
+	assert.Equal(t, lease.Holder, current.Holder)
+	assert.Equal(t, lease.Token, current.Token)
+	assert.WithinDuration(t, lease.Expires, current.Expires, 0)
+}
+
+func TestFileLocker_Release(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now = time.Now()
+		ttl = 10 * time.Second
+
+		f = newTestLocker(t, &now)
+	)
+
+	_, err := f.Acquire("primary", ttl)
+	require.NoError(t, err)
+
+	// Make sure the lease is not released by another holder
+	require.NoError(t, f.Release("standby"))
+
+	_, err = f.Acquire("standby", ttl)
+	assert.ErrorIs(t, err, ErrHeld)
+
+	// Make sure the released lease is taken over
+	// before it expires, with the next fencing token
+	require.NoError(t, f.Release("primary"))
+
+	lease, err := f.Acquire("standby", ttl)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), lease.Token)
+}
+
+func TestFileLocker_Guard(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now = time.Now()
+
+		f = newTestLocker(t, &now)
+	)
+
+	// Simulate an update in progress
+	release, err := f.guard()
+	require.NoError(t, err)
+
+	_, err = f.Acquire("primary", time.Second)
+	assert.ErrorIs(t, err, ErrBusy)
+
+	release()
+
+	// Simulate a guard left by a crashed update
+	_, err = f.guard()
+	require.NoError(t, err)
+
+	now = now.Add(2 * staleGuard)
+
+	// Make sure the stale guard is removed,
+	// and the lease is acquired on the next attempt
+	_, err = f.Acquire("primary", time.Second)
+	assert.ErrorIs(t, err, ErrBusy)
+
+	_, err = f.Acquire("primary", time.Second)
+	assert.NoError(t, err)
+}
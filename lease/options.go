@@ -0,0 +1,25 @@
+package lease
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(e *Elector)
+
+// WithLogger sets the logger to be used
+// with the lease elector
+func WithLogger(logger *zap.Logger) Option {
+	return func(e *Elector) {
+		e.logger = logger
+	}
+}
+
+// WithTTL sets the lease duration. The lease is renewed
+// three times per duration, and taken over by the standby once it expires
+func WithTTL(ttl time.Duration) Option {
+	return func(e *Elector) {
+		e.ttl = ttl
+	}
+}
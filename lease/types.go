@@ -0,0 +1,43 @@
+package lease
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrHeld is returned when the lease is held by another holder
+	ErrHeld = errors.New("lease held by another holder")
+
+	// ErrNotLeader is returned when the lease is not held,
+	// or was taken over by another holder
+	ErrNotLeader = errors.New("registration lease not held")
+)
+
+// Lease is the single-writer lease. The fencing token is
+// incremented every time the lease changes hands, so a stale
+// holder is able to tell its lease was taken over
+type Lease struct {
+	Holder  string    `json:"holder"`  // the lease holder, empty if released
+	Token   uint64    `json:"token"`   // the fencing token
+	Expires time.Time `json:"expires"` // the lease expiration time
+}
+
+// HeldBy checks if the lease is held by the holder at the given time
+func (l *Lease) HeldBy(holder string, now time.Time) bool {
+	return l.Holder == holder && now.Before(l.Expires)
+}
+
+// Locker defines the interface for the shared lease storage
+type Locker interface {
+	// Acquire acquires the lease for the holder, or renews the lease
+	// already held by it. ErrHeld is returned if the lease
+	// is held by another holder, and has not expired
+	Acquire(holder string, ttl time.Duration) (*Lease, error)
+
+	// Release releases the lease, if it is held by the holder
+	Release(holder string) error
+
+	// Current returns the current lease
+	Current() (*Lease, error)
+}
@@ -0,0 +1,258 @@
+package ledger
+
+import "time"
+
+// Status is the registration status of a detected token
+type Status string
+
+const (
+	// StatusRegistered marks a token whose registration tx was committed,
+	// or that was found to be already registered on the target realm
+	StatusRegistered Status = "registered"
+
+	// StatusSimulationFailed marks a token whose registration tx was
+	// rejected by the pre-broadcast simulation, and was never sent
+	StatusSimulationFailed Status = "simulation_failed"
+
+	// StatusFailed marks a token whose registration tx failed
+	// during (or after) the broadcast
+	StatusFailed Status = "failed"
+
+	// StatusQueued marks a token whose registration is postponed,
+	// because it would exceed the registrar fee budget
+	StatusQueued Status = "queued"
+
+	// StatusRejected marks a token whose registration
+	// was rejected by the registration policy
+	StatusRejected Status = "rejected"
+
+	// StatusNeedsReview marks a token whose registration
+	// requires a manual approval, per the registration policy
+	StatusNeedsReview Status = "needs_review"
+
+	// StatusProbeFailed marks a token whose deployed package
+	// failed the behavioral probes, and was never registered
+	StatusProbeFailed Status = "probe_failed"
+
+	// StatusTemplateError marks a token whose register packages
+	// failed to type-check, and was never registered
+	StatusTemplateError Status = "template_error"
+
+	// StatusBatched marks a token waiting for the batch
+	// registration window to close
+	StatusBatched Status = "batched"
+
+	// StatusRegistering marks a token whose registration tx is being
+	// broadcast. Tokens left registering by a crash are verified on chain
+	StatusRegistering Status = "registering"
+
+	// StatusTagged marks a package that was only tagged
+	// by the detection rules, and is not registered
+	StatusTagged Status = "tagged"
+)
+
+// Kind is the token standard of a detected token
+type Kind string
+
+const (
+	// KindGRC20 marks a fungible GRC20 token
+	KindGRC20 Kind = "grc20"
+
+	// KindGRC721 marks a GRC721 NFT collection
+	KindGRC721 Kind = "grc721"
+)
+
+// Token is the ledger record of a detected token,
+// and the outcome of its registration
+type Token struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	PkgPath      string `json:"pkgPath"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	Deployer     string `json:"deployer"`
+	DeployTxHash string `json:"deployTxHash"`
+
+	// Kind is the token standard, GRC20 if empty
+	Kind Kind `json:"kind,omitempty"`
+
+	// Key is the token key within its package, for the tokens
+	// kept by key in factory realms. The package path of keyed
+	// tokens is in the "pkgPath.key" format
+	Key string `json:"key,omitempty"`
+
+	Status Status `json:"status"`
+
+	// RegisterTxHash is the hash of the committed registration tx, if any
+	RegisterTxHash string `json:"registerTxHash,omitempty"`
+
+	// PendingTx is the signed registration tx of a token
+	// being registered, until the outcome is known
+	PendingTx *PendingTx `json:"pendingTx,omitempty"`
+
+	// Error is the (parsed) error of the last failed
+	// registration attempt, if any
+	Error string `json:"error,omitempty"`
+
+	// PolicyReason is the explanation of the
+	// registration policy decision for the token
+	PolicyReason string `json:"policyReason,omitempty"`
+
+	// Impersonates is the token (or protected symbol)
+	// the token is suspected to impersonate, if any
+	Impersonates string `json:"impersonates,omitempty"`
+
+	// Tags are the tags set by the detection rules, if any
+	Tags []string `json:"tags,omitempty"`
+
+	// Targets are the names of the registration targets the token
+	// is registered to, set by the detection rules. Every target
+	// of the token kind is used if empty
+	Targets []string `json:"targets,omitempty"`
+
+	// Risk is the source risk report of the token package, if any
+	Risk *Risk `json:"risk,omitempty"`
+
+	// Version is the registry version the
+	// registration status refers to, if any
+	Version string `json:"version,omitempty"`
+
+	// Registrations are the registration outcomes
+	// of the token, per registry version
+	Registrations map[string]*Registration `json:"registrations,omitempty"`
+
+	Height   int64 `json:"height"`
+	Decimals int   `json:"decimals"`
+}
+
+// Registration is the registration outcome
+// of a token, for a single registry version
+type Registration struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Status         Status `json:"status"`
+	RegisterTxHash string `json:"registerTxHash,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// PendingTx is the signed registration tx of a token, persisted before its
+// broadcast, so an uncertain broadcast is resolved by the precomputed hash
+type PendingTx struct {
+	Hash string `json:"hash"` // the base64 encoded tx hash
+	Raw  []byte `json:"raw"`  // the amino encoded signed tx
+
+	// Denom and Amount are the tx fee, and Share
+	// is the part of the fee attributed to the token
+	Denom  string `json:"denom"`
+	Amount int64  `json:"amount"`
+	Share  int64  `json:"share"`
+}
+
+// RegistrationTx is a committed registration tx, linked to
+// its token by the registration memo of the tx
+type RegistrationTx struct {
+	TxHash       string `json:"txHash"`
+	TokenID      string `json:"tokenId"`                // the token package path, "pkgPath.key" for keyed tokens
+	DeployTxHash string `json:"deployTxHash,omitempty"` // the source deploy tx of the token, if carried by the memo
+	Version      string `json:"version,omitempty"`      // the registry version the token was registered to, if any
+	Height       int64  `json:"height"`
+	Index        uint32 `json:"index"`
+}
+
+// Event is the token lifecycle event, notified to the webhooks
+type Event string
+
+const (
+	// EventDetected is notified when a token is detected,
+	// in the same batch that commits its block
+	EventDetected Event = "token.detected"
+
+	// EventSubmitted is notified when the registration tx is about to be broadcast
+	EventSubmitted Event = "registration.submitted"
+
+	// EventConfirmed is notified when the token is registered
+	EventConfirmed Event = "registration.confirmed"
+
+	// EventFailed is notified when the token registration failed
+	EventFailed Event = "registration.failed"
+
+	// EventNeedsReview is notified when the token
+	// registration requires a manual approval
+	EventNeedsReview Event = "token.needs_review"
+
+	// EventLowBalance is notified when the signer
+	// balance drops below the configured threshold
+	EventLowBalance Event = "signer.low_balance"
+
+	// EventRuleMatched is notified to the endpoint
+	// of a matched detection rule "webhook" action
+	EventRuleMatched Event = "rule.matched"
+)
+
+// Notification is a lifecycle event notification for a single webhook
+// endpoint. It is kept in the outbox until it is delivered
+type Notification struct {
+	ID       uint64    `json:"id"`       // the unique outbox sequence
+	EventID  string    `json:"eventId"`  // the event ID, the same on every indexer instance
+	Endpoint string    `json:"endpoint"` // the webhook endpoint URL
+	Event    Event     `json:"event"`
+	Time     time.Time `json:"time"`
+
+	Token   *Token     `json:"token,omitempty"`   // the token, for the token events
+	Balance *Balance   `json:"balance,omitempty"` // the signer balance, for the low balance event
+	Match   *RuleMatch `json:"match,omitempty"`   // the matched rule, for the rule match event
+
+	Attempts    int       `json:"attempts"`            // the failed delivery attempts
+	NextAttempt time.Time `json:"nextAttempt"`         // the earliest time of the next delivery attempt
+	LastError   string    `json:"lastError,omitempty"` // the last delivery error, if any
+}
+
+// RuleMatch is a tx message matched by a detection rule
+type RuleMatch struct {
+	Rule     string `json:"rule"`
+	Action   string `json:"action"`
+	PkgPath  string `json:"pkgPath"`
+	Deployer string `json:"deployer"`
+	TxHash   string `json:"txHash"`
+	Height   int64  `json:"height"`
+}
+
+// Balance is the signer account balance
+type Balance struct {
+	Signer    string `json:"signer"`
+	Denom     string `json:"denom"`
+	Amount    int64  `json:"amount"`
+	Threshold int64  `json:"threshold"`
+}
+
+// Risk is the static analysis risk report of a token package
+type Risk struct {
+	// Template is the most similar audited template, if any
+	Template string `json:"template,omitempty"`
+
+	Findings []RiskFinding `json:"findings"`
+
+	// Similarity is the similarity to the template, in [0, 1]
+	Similarity float64 `json:"similarity"`
+
+	// Score is the risk score, in [0, 100]
+	Score int `json:"score"`
+}
+
+// RiskFinding is a single risk analysis finding
+type RiskFinding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Spend is the ledger record of a fee paid by the registrar
+type Spend struct {
+	Time time.Time `json:"time"`
+
+	PkgPath  string `json:"pkgPath"`
+	Deployer string `json:"deployer"`
+	TxHash   string `json:"txHash"`
+	Denom    string `json:"denom"`
+
+	Amount int64 `json:"amount"`
+}
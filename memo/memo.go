@@ -0,0 +1,98 @@
+package memo
+
+import (
+	"strings"
+)
+
+// DefaultTag is the default tag of the registration tx memos
+const DefaultTag = "grc20-register"
+
+const (
+	tagSeparator     = ":"
+	versionSeparator = "@"
+	idSeparator      = ","
+	deploySeparator  = "#"
+)
+
+// Link is a token registered by the tx, linked to its source deploy tx
+type Link struct {
+	TokenID      string // the token package path, "pkgPath.key" for keyed tokens
+	DeployTxHash string // the base64 encoded deploy tx hash, if known
+}
+
+// Format formats the registration tx memo, as the tag and the registry
+// version, if any, followed by the IDs of the registered tokens and their
+// source deploy tx hashes, ex. "grc20-register@v2:<id>#<deploy hash>,<id>".
+// The memo is empty without token IDs
+func Format(tag, version string, links ...Link) string {
+	entries := make([]string, 0, len(links))
+
+	for _, link := range links {
+		if link.TokenID == "" {
+			continue
+		}
+
+		entry := link.TokenID
+		if link.DeployTxHash != "" {
+			entry += deploySeparator + link.DeployTxHash
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return ""
+	}
+
+	if version != "" {
+		tag += versionSeparator + version
+	}
+
+	return tag + tagSeparator + strings.Join(entries, idSeparator)
+}
+
+// Parse parses the registration tx memo with the given tag, returning
+// the registry version, if any, and the links of the registered tokens
+func Parse(tag, memo string) (string, []Link, bool) {
+	raw, found := strings.CutPrefix(strings.TrimSpace(memo), tag)
+	if !found {
+		return "", nil, false
+	}
+
+	var version string
+
+	if versioned, ok := strings.CutPrefix(raw, versionSeparator); ok {
+		version, raw, found = strings.Cut(versioned, tagSeparator)
+	} else {
+		raw, found = strings.CutPrefix(raw, tagSeparator)
+	}
+
+	if !found || raw == "" {
+		return "", nil, false
+	}
+
+	links := make([]Link, 0)
+
+	for _, entry := range strings.Split(raw, idSeparator) {
+		entry = strings.TrimSpace(entry)
+
+		// The base64 deploy tx hash never contains the separator
+		var link Link
+
+		if i := strings.LastIndex(entry, deploySeparator); i >= 0 {
+			link.TokenID, link.DeployTxHash = entry[:i], entry[i+len(deploySeparator):]
+		} else {
+			link.TokenID = entry
+		}
+
+		if link.TokenID != "" {
+			links = append(links, link)
+		}
+	}
+
+	if len(links) == 0 {
+		return "", nil, false
+	}
+
+	return version, links, true
+}
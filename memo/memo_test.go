@@ -0,0 +1,134 @@
+package memo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		version  string
+		links    []Link
+		expected string
+	}{
+		{
+			"single token",
+			"",
+			[]Link{{TokenID: "gno.land/r/demo/foo", DeployTxHash: "ZGVwbG95+/=="}},
+			"grc20-register:gno.land/r/demo/foo#ZGVwbG95+/==",
+		},
+		{
+			"batch, with a keyed token",
+			"",
+			[]Link{
+				{TokenID: "gno.land/r/demo/foo", DeployTxHash: "Zm9v"},
+				{DeployTxHash: "ZW1wdHk="},
+				{TokenID: "gno.land/r/demo/factory.bar", DeployTxHash: "YmFy"},
+			},
+			"grc20-register:gno.land/r/demo/foo#Zm9v,gno.land/r/demo/factory.bar#YmFy",
+		},
+		{
+			"unknown deploy tx",
+			"",
+			[]Link{{TokenID: "gno.land/r/demo/foo"}},
+			"grc20-register:gno.land/r/demo/foo",
+		},
+		{
+			"registry version",
+			"v2",
+			[]Link{{TokenID: "gno.land/r/demo/foo", DeployTxHash: "Zm9v"}},
+			"grc20-register@v2:gno.land/r/demo/foo#Zm9v",
+		},
+		{
+			"no token IDs",
+			"v2",
+			[]Link{{DeployTxHash: "Zm9v"}},
+			"",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, Format(DefaultTag, testCase.version, testCase.links...))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		memo     string
+		version  string
+		expected []Link
+	}{
+		{
+			"single token",
+			"grc20-register:gno.land/r/demo/foo#ZGVwbG95+/==",
+			"",
+			[]Link{{TokenID: "gno.land/r/demo/foo", DeployTxHash: "ZGVwbG95+/=="}},
+		},
+		{
+			"batch, sent by hand",
+			" grc20-register:gno.land/r/demo/foo#Zm9v, gno.land/r/demo/factory.bar ",
+			"",
+			[]Link{
+				{TokenID: "gno.land/r/demo/foo", DeployTxHash: "Zm9v"},
+				{TokenID: "gno.land/r/demo/factory.bar"},
+			},
+		},
+		{
+			"registry version",
+			"grc20-register@v2:gno.land/r/demo/foo#Zm9v",
+			"v2",
+			[]Link{{TokenID: "gno.land/r/demo/foo", DeployTxHash: "Zm9v"}},
+		},
+		{
+			"other tag",
+			"other-register:gno.land/r/demo/foo",
+			"",
+			nil,
+		},
+		{
+			"tag prefix",
+			"grc20-registered:gno.land/r/demo/foo",
+			"",
+			nil,
+		},
+		{
+			"no token IDs",
+			"grc20-register@v2:#Zm9v",
+			"",
+			nil,
+		},
+		{
+			"empty memo",
+			"",
+			"",
+			nil,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			version, links, ok := Parse(DefaultTag, testCase.memo)
+
+			assert.Equal(t, testCase.expected != nil, ok)
+			assert.Equal(t, testCase.version, version)
+			assert.Equal(t, testCase.expected, links)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package memo
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type registersDelegate func(string, string, std.Msg) bool
+
+type mockMatcher struct {
+	registersFn registersDelegate
+}
+
+func (m *mockMatcher) Registers(version, tokenID string, msg std.Msg) bool {
+	if m.registersFn != nil {
+		return m.registersFn(version, tokenID, msg)
+	}
+
+	return false
+}
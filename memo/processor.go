@@ -0,0 +1,100 @@
+package memo
+
+import (
+	"encoding/base64"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// ProcessorName is the name of the registration memo block processor
+const ProcessorName = "registration-memos"
+
+// Matcher matches the registration msgs of the tokens
+type Matcher interface {
+	// Registers checks if the msg is a registration of the token
+	// with the given ID, to one of the targets of the registry version
+	Registers(version, tokenID string, msg std.Msg) bool
+}
+
+// Processor is the block processor that links the committed
+// registration txs to their tokens, by their memo.
+// The txs are linked regardless of their sender, so the registrations
+// sent by other instances, or by hand, are linked as well. A token is
+// only linked if the tx carries one of its registration msgs
+type Processor struct {
+	tag     string
+	matcher Matcher
+}
+
+// NewProcessor creates a new registration memo block processor,
+// for the memos with the given tag
+func NewProcessor(tag string, matcher Matcher) *Processor {
+	return &Processor{
+		tag:     tag,
+		matcher: matcher,
+	}
+}
+
+// Name returns the processor name
+func (p *Processor) Name() string {
+	return ProcessorName
+}
+
+// ProcessBlock links the successful registration txs of the block
+func (p *Processor) ProcessBlock(wb storage.Batch, block *fetch.BlockData) error {
+	links := make([]*ledger.RegistrationTx, 0)
+
+	for i, tx := range block.Txs {
+		result := block.Results[i]
+
+		if tx == nil || result.Response.IsErr() {
+			continue
+		}
+
+		version, tokens, ok := Parse(p.tag, tx.Memo)
+		if !ok {
+			continue
+		}
+
+		txHash := base64.StdEncoding.EncodeToString(result.Tx.Hash())
+
+		for _, token := range tokens {
+			if !p.registers(tx, version, token.TokenID) {
+				continue
+			}
+
+			links = append(links, &ledger.RegistrationTx{
+				TxHash:       txHash,
+				TokenID:      token.TokenID,
+				DeployTxHash: token.DeployTxHash,
+				Version:      version,
+				Height:       result.Height,
+				Index:        result.Index,
+			})
+		}
+	}
+
+	for _, link := range links {
+		if err := wb.SetRegistrationTx(link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registers checks if the tx carries a registration msg of the token,
+// so the memo alone doesn't mark the token registered
+func (p *Processor) registers(tx *std.Tx, version, tokenID string) bool {
+	for _, msg := range tx.Msgs {
+		if p.matcher.Registers(version, tokenID, msg) {
+			return true
+		}
+	}
+
+	return false
+}
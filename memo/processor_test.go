@@ -0,0 +1,114 @@
+package memo
+
+import (
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// mockMsg is the registration msg of the token
+// with the given ID, to the given registry version
+type mockMsg struct {
+	std.Msg
+
+	version string
+	tokenID string
+}
+
+func TestProcessor_ProcessBlock(t *testing.T) {
+	t.Parallel()
+
+	var (
+		links = make([]*ledger.RegistrationTx, 0)
+
+		wb = &mock.WriteBatch{
+			SetRegistrationTxFn: func(tx *ledger.RegistrationTx) error {
+				links = append(links, tx)
+
+				return nil
+			},
+		}
+
+		block = &fetch.BlockData{
+			Block: &types.Block{},
+			Txs: []*std.Tx{
+				{
+					Memo: Format(
+						DefaultTag,
+						"v2",
+						Link{TokenID: "gno.land/r/demo/foo", DeployTxHash: "Zm9v"},
+						Link{TokenID: "gno.land/r/demo/factory.bar", DeployTxHash: "ZmFjdG9yeQ=="},
+						Link{TokenID: "gno.land/r/demo/baz", DeployTxHash: "YmF6"},
+					),
+					Msgs: []std.Msg{
+						mockMsg{version: "v2", tokenID: "gno.land/r/demo/foo"},
+						mockMsg{version: "v2", tokenID: "gno.land/r/demo/factory.bar"},
+						// The registration to another version doesn't link the token
+						mockMsg{version: "v1", tokenID: "gno.land/r/demo/baz"},
+					},
+				},
+				{Memo: "unrelated memo"},
+				{
+					Memo: Format(DefaultTag, "", Link{TokenID: "gno.land/r/demo/qux"}),
+					Msgs: []std.Msg{mockMsg{tokenID: "gno.land/r/demo/qux"}},
+				},
+				nil,
+				{
+					// The memo alone doesn't link the token
+					Memo: Format(DefaultTag, "", Link{TokenID: "gno.land/r/demo/qux"}),
+					Msgs: []std.Msg{mockMsg{tokenID: "gno.land/r/demo/other"}},
+				},
+			},
+			Results: []*types.TxResult{
+				{Height: 10, Index: 0},
+				{Height: 10, Index: 1},
+				{
+					Height: 10,
+					Index:  2,
+					Response: abci.ResponseDeliverTx{
+						ResponseBase: abci.ResponseBase{
+							Error: abci.StringError("registration failed"),
+						},
+					},
+				},
+				{Height: 10, Index: 3},
+				{Height: 10, Index: 4},
+			},
+		}
+	)
+
+	matcher := &mockMatcher{
+		registersFn: func(version, tokenID string, msg std.Msg) bool {
+			registration := msg.(mockMsg)
+
+			return registration.version == version && registration.tokenID == tokenID
+		},
+	}
+
+	p := NewProcessor(DefaultTag, matcher)
+
+	require.NoError(t, p.ProcessBlock(wb, block))
+
+	// Make sure only the successful registration txs were linked, to the
+	// tokens in their memo they carry the registration msgs of, and their deploy txs
+	require.Len(t, links, 2)
+
+	assert.Equal(t, "gno.land/r/demo/foo", links[0].TokenID)
+	assert.Equal(t, "Zm9v", links[0].DeployTxHash)
+	assert.Equal(t, "gno.land/r/demo/factory.bar", links[1].TokenID)
+	assert.Equal(t, "ZmFjdG9yeQ==", links[1].DeployTxHash)
+
+	for _, link := range links {
+		assert.Equal(t, "v2", link.Version)
+		assert.Equal(t, int64(10), link.Height)
+		assert.Equal(t, uint32(0), link.Index)
+	}
+}
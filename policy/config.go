@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// regexPrefix marks a pattern as a regular expression,
+// instead of a glob pattern
+const regexPrefix = "re:"
+
+// Config is the registration policy configuration
+type Config struct {
+	// Decimals are the accepted token decimals bounds
+	Decimals DecimalsConfig `json:"decimals"`
+
+	// SymbolFormat is the regular expression
+	// the token symbol is required to match, if set
+	SymbolFormat string `json:"symbolFormat,omitempty"`
+
+	// PkgPath are the package path rules.
+	// Patterns are globs (* matches within a path segment,
+	// ** matches across segments), or regular expressions
+	// if they start with "re:"
+	PkgPath ListConfig `json:"pkgPath"`
+
+	// Deployer are the deployer address rules
+	Deployer ListConfig `json:"deployer"`
+
+	// Risk are the source risk score thresholds
+	Risk RiskConfig `json:"risk"`
+
+	// ReviewUnknownDeployers requires a manual approval for tokens
+	// of deployers that are not on the deployer allowlist
+	ReviewUnknownDeployers bool `json:"reviewUnknownDeployers"`
+}
+
+// ListConfig is an allow / deny list configuration.
+// The deny list has precedence over the allow list
+type ListConfig struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// DecimalsConfig are the accepted token decimals bounds
+type DecimalsConfig struct {
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+}
+
+// RiskConfig are the source risk score thresholds, in [0, 100]
+type RiskConfig struct {
+	// Reject is the score above which the token is rejected
+	Reject *int `json:"reject,omitempty"`
+
+	// Review is the score above which the token
+	// requires a manual approval
+	Review *int `json:"review,omitempty"`
+}
+
+// Load loads the registration policy
+// from the given JSON config file
+func Load(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read policy file, %w", err)
+	}
+
+	var cfg Config
+
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("unable to parse policy file, %w", err)
+	}
+
+	return New(cfg)
+}
@@ -0,0 +1,258 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// Decision is the outcome of the policy evaluation
+type Decision string
+
+const (
+	// DecisionAllow allows the token registration
+	DecisionAllow Decision = "allow"
+
+	// DecisionReject rejects the token registration
+	DecisionReject Decision = "reject"
+
+	// DecisionReview requires a manual approval
+	// for the token registration
+	DecisionReview Decision = "review"
+)
+
+// Result is the policy evaluation result
+type Result struct {
+	Decision Decision `json:"decision"`
+	Reason   string   `json:"reason"` // the human-readable explanation
+}
+
+// pattern is a compiled pkgPath pattern
+type pattern struct {
+	re     *regexp.Regexp
+	source string // the pattern, as configured
+}
+
+// Policy is the token registration policy
+type Policy struct {
+	symbolFormat *regexp.Regexp
+
+	pkgPathAllow []pattern
+	pkgPathDeny  []pattern
+
+	deployerAllow map[string]struct{}
+	deployerDeny  map[string]struct{}
+
+	cfg Config
+}
+
+// New creates a new registration policy
+// from the given configuration
+func New(cfg Config) (*Policy, error) {
+	p := &Policy{
+		cfg:           cfg,
+		deployerAllow: toSet(cfg.Deployer.Allow),
+		deployerDeny:  toSet(cfg.Deployer.Deny),
+	}
+
+	var err error
+
+	if p.pkgPathAllow, err = compilePatterns(cfg.PkgPath.Allow); err != nil {
+		return nil, fmt.Errorf("invalid pkgPath allow pattern, %w", err)
+	}
+
+	if p.pkgPathDeny, err = compilePatterns(cfg.PkgPath.Deny); err != nil {
+		return nil, fmt.Errorf("invalid pkgPath deny pattern, %w", err)
+	}
+
+	if cfg.SymbolFormat != "" {
+		if p.symbolFormat, err = regexp.Compile(cfg.SymbolFormat); err != nil {
+			return nil, fmt.Errorf("invalid symbol format, %w", err)
+		}
+	}
+
+	if cfg.Decimals.Min != nil && cfg.Decimals.Max != nil && *cfg.Decimals.Min > *cfg.Decimals.Max {
+		return nil, fmt.Errorf(
+			"invalid decimals bounds, min %d is greater than max %d",
+			*cfg.Decimals.Min,
+			*cfg.Decimals.Max,
+		)
+	}
+
+	return p, nil
+}
+
+// Evaluate evaluates the registration policy for the given token
+func (p *Policy) Evaluate(token *ledger.Token) Result {
+	// Check the deny lists
+	if pattern, ok := matchAny(p.pkgPathDeny, token.PkgPath); ok {
+		return reject("pkgPath %q matches deny pattern %q", token.PkgPath, pattern)
+	}
+
+	if _, ok := p.deployerDeny[token.Deployer]; ok {
+		return reject("deployer %s is on the deny list", token.Deployer)
+	}
+
+	// Check the pkgPath allow list
+	if len(p.pkgPathAllow) > 0 {
+		if _, ok := matchAny(p.pkgPathAllow, token.PkgPath); !ok {
+			return reject("pkgPath %q does not match any allow pattern", token.PkgPath)
+		}
+	}
+
+	// Check the token metadata
+	if min := p.cfg.Decimals.Min; min != nil && token.Decimals < *min {
+		return reject("decimals %d are below the minimum of %d", token.Decimals, *min)
+	}
+
+	if max := p.cfg.Decimals.Max; max != nil && token.Decimals > *max {
+		return reject("decimals %d are above the maximum of %d", token.Decimals, *max)
+	}
+
+	if p.symbolFormat != nil && !p.symbolFormat.MatchString(token.Symbol) {
+		return reject("symbol %q does not match the required format %q", token.Symbol, p.cfg.SymbolFormat)
+	}
+
+	// Check the source risk
+	if token.Risk != nil {
+		if threshold := p.cfg.Risk.Reject; threshold != nil && token.Risk.Score > *threshold {
+			return reject("risk score %d is above the rejection threshold of %d", token.Risk.Score, *threshold)
+		}
+
+		if threshold := p.cfg.Risk.Review; threshold != nil && token.Risk.Score > *threshold {
+			return Result{
+				Decision: DecisionReview,
+				Reason: fmt.Sprintf(
+					"risk score %d is above the review threshold of %d, approval required",
+					token.Risk.Score,
+					*threshold,
+				),
+			}
+		}
+	}
+
+	// Check the deployer allow list
+	if _, ok := p.deployerAllow[token.Deployer]; ok {
+		return allow("deployer %s is on the allow list", token.Deployer)
+	}
+
+	if p.cfg.ReviewUnknownDeployers {
+		return Result{
+			Decision: DecisionReview,
+			Reason:   fmt.Sprintf("deployer %s is unknown, approval required", token.Deployer),
+		}
+	}
+
+	if len(p.deployerAllow) > 0 {
+		return reject("deployer %s is not on the allow list", token.Deployer)
+	}
+
+	return allow("token passed all policy rules")
+}
+
+// reject creates a rejecting result with the formatted reason
+func reject(format string, args ...any) Result {
+	return Result{
+		Decision: DecisionReject,
+		Reason:   fmt.Sprintf(format, args...),
+	}
+}
+
+// allow creates an allowing result with the formatted reason
+func allow(format string, args ...any) Result {
+	return Result{
+		Decision: DecisionAllow,
+		Reason:   fmt.Sprintf(format, args...),
+	}
+}
+
+// compilePatterns compiles the given glob / regex patterns
+func compilePatterns(sources []string) ([]pattern, error) {
+	compiled := make([]pattern, 0, len(sources))
+
+	for _, source := range sources {
+		re, err := CompilePattern(source)
+		if err != nil {
+			return nil, err
+		}
+
+		compiled = append(compiled, pattern{
+			re:     re,
+			source: source,
+		})
+	}
+
+	return compiled, nil
+}
+
+// CompilePattern compiles the given pkgPath pattern. Patterns are globs
+// (* matches within a path segment, ** matches across segments),
+// or regular expressions if they start with "re:"
+func CompilePattern(source string) (*regexp.Regexp, error) {
+	expr := globToRegex(source)
+
+	if strings.HasPrefix(source, regexPrefix) {
+		expr = strings.TrimPrefix(source, regexPrefix)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%q, %w", source, err)
+	}
+
+	return re, nil
+}
+
+// globToRegex converts the glob pattern into an anchored regular expression.
+// A single * matches within a path segment, while ** matches across segments
+func globToRegex(glob string) string {
+	var b strings.Builder
+
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+
+				continue
+			}
+
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// matchAny returns the source of the first pattern matching the value
+func matchAny(patterns []pattern, value string) (string, bool) {
+	for _, p := range patterns {
+		if p.re.MatchString(value) {
+			return p.source, true
+		}
+	}
+
+	return "", false
+}
+
+// toSet converts the given list into a lookup set
+func toSet(list []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(list))
+
+	for _, item := range list {
+		set[item] = struct{}{}
+	}
+
+	return set
+}
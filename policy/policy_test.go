@@ -0,0 +1,239 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// intPtr returns a pointer to the given int
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Decimals: DecimalsConfig{
+			Min: intPtr(4),
+			Max: intPtr(18),
+		},
+		SymbolFormat: "^[A-Z]{2,8}$",
+		PkgPath: ListConfig{
+			Allow: []string{"gno.land/r/**"},
+			Deny:  []string{"gno.land/r/*/scam*", `re:^gno\.land/r/test/`},
+		},
+		Deployer: ListConfig{
+			Allow: []string{"g1alice"},
+			Deny:  []string{"g1mallory"},
+		},
+	}
+
+	testTable := []struct {
+		name     string
+		token    *ledger.Token
+		expected Decision
+	}{
+		{
+			"allowed deployer",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Symbol: "FOO", Decimals: 6},
+			DecisionAllow,
+		},
+		{
+			"denied glob pkgPath",
+			&ledger.Token{PkgPath: "gno.land/r/demo/scamcoin", Deployer: "g1alice", Symbol: "FOO", Decimals: 6},
+			DecisionReject,
+		},
+		{
+			"denied regex pkgPath",
+			&ledger.Token{PkgPath: "gno.land/r/test/foo", Deployer: "g1alice", Symbol: "FOO", Decimals: 6},
+			DecisionReject,
+		},
+		{
+			"pkgPath not allowed",
+			&ledger.Token{PkgPath: "gno.land/p/demo/foo", Deployer: "g1alice", Symbol: "FOO", Decimals: 6},
+			DecisionReject,
+		},
+		{
+			"denied deployer",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1mallory", Symbol: "FOO", Decimals: 6},
+			DecisionReject,
+		},
+		{
+			"decimals below minimum",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Symbol: "FOO", Decimals: 2},
+			DecisionReject,
+		},
+		{
+			"decimals above maximum",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Symbol: "FOO", Decimals: 24},
+			DecisionReject,
+		},
+		{
+			"invalid symbol format",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice", Symbol: "foo", Decimals: 6},
+			DecisionReject,
+		},
+		{
+			"unknown deployer",
+			&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1bob", Symbol: "FOO", Decimals: 6},
+			DecisionReject,
+		},
+	}
+
+	p, err := New(cfg)
+	require.NoError(t, err)
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := p.Evaluate(testCase.token)
+
+			assert.Equal(t, testCase.expected, result.Decision)
+			assert.NotEmpty(t, result.Reason)
+		})
+	}
+}
+
+func TestPolicy_ReviewUnknownDeployers(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(Config{
+		Deployer: ListConfig{
+			Allow: []string{"g1alice"},
+		},
+		ReviewUnknownDeployers: true,
+	})
+	require.NoError(t, err)
+
+	known := p.Evaluate(&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice"})
+	assert.Equal(t, DecisionAllow, known.Decision)
+
+	unknown := p.Evaluate(&ledger.Token{PkgPath: "gno.land/r/demo/bar", Deployer: "g1bob"})
+	assert.Equal(t, DecisionReview, unknown.Decision)
+	assert.Contains(t, unknown.Reason, "g1bob")
+}
+
+func TestPolicy_Risk(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(Config{
+		Risk: RiskConfig{
+			Reject: intPtr(50),
+			Review: intPtr(20),
+		},
+	})
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name     string
+		risk     *ledger.Risk
+		expected Decision
+	}{
+		{
+			"no risk report",
+			nil,
+			DecisionAllow,
+		},
+		{
+			"low risk",
+			&ledger.Risk{Score: 10},
+			DecisionAllow,
+		},
+		{
+			"review risk",
+			&ledger.Risk{Score: 30},
+			DecisionReview,
+		},
+		{
+			"high risk",
+			&ledger.Risk{Score: 70},
+			DecisionReject,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := p.Evaluate(&ledger.Token{PkgPath: "gno.land/r/demo/foo", Risk: testCase.risk})
+
+			assert.Equal(t, testCase.expected, result.Decision)
+		})
+	}
+}
+
+func TestPolicy_Empty(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(Config{})
+	require.NoError(t, err)
+
+	result := p.Evaluate(&ledger.Token{PkgPath: "gno.land/r/demo/foo", Deployer: "g1bob"})
+
+	assert.Equal(t, DecisionAllow, result.Decision)
+}
+
+func TestPolicy_New_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name string
+		cfg  Config
+	}{
+		{
+			"invalid pkgPath regex",
+			Config{PkgPath: ListConfig{Deny: []string{"re:("}}},
+		},
+		{
+			"invalid symbol format",
+			Config{SymbolFormat: "["},
+		},
+		{
+			"invalid decimals bounds",
+			Config{Decimals: DecimalsConfig{Min: intPtr(18), Max: intPtr(6)}},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(testCase.cfg)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"pkgPath": {"deny": ["gno.land/r/**/scam"]},
+		"decimals": {"max": 18}
+	}`), 0o600))
+
+	p, err := Load(path)
+	require.NoError(t, err)
+
+	result := p.Evaluate(&ledger.Token{PkgPath: "gno.land/r/a/b/scam"})
+
+	assert.Equal(t, DecisionReject, result.Decision)
+	assert.Equal(t, `pkgPath "gno.land/r/a/b/scam" matches deny pattern "gno.land/r/**/scam"`, result.Reason)
+}
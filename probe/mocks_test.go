@@ -0,0 +1,17 @@
+package probe
+
+import ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+
+type abciQueryDelegate func(string, []byte) (*ctypes.ResultABCIQuery, error)
+
+type mockClient struct {
+	abciQueryFn abciQueryDelegate
+}
+
+func (m *mockClient) ABCIQuery(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	if m.abciQueryFn != nil {
+		return m.abciQueryFn(path, data)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,212 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// qevalPath is the ABCI query path used for the read-only evaluation
+const qevalPath = "vm/qeval"
+
+// qevalResultRegex matches a single typed qeval result,
+// ex. (100000000 uint64) or ("Foo" string)
+var qevalResultRegex = regexp.MustCompile(`^\((.*) ([\w.*\[\]]+)\)$`)
+
+// errNotDeclared is returned when the probed function is not declared
+var errNotDeclared = errors.New("function not declared")
+
+// Error is the error returned when a behavioral probe fails
+type Error struct {
+	Probe  string // the probed expression
+	Reason string // the failure reason
+	Output string // the raw query output
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("probe %s failed, %s (output: %q)", e.Probe, e.Reason, e.Output)
+}
+
+// Prober validates the behavior of a deployed token,
+// using read-only qeval probes
+type Prober struct {
+	client Client
+}
+
+// New creates a new token prober
+func New(client Client) *Prober {
+	return &Prober{
+		client: client,
+	}
+}
+
+// Probe runs the behavioral probes against the deployed token package,
+// and checks the results are consistent with the token metadata
+func (p *Prober) Probe(token *ledger.Token) error {
+	// Keyed tokens don't expose package-level getters
+	if token.Key != "" {
+		return nil
+	}
+
+	// Fetch the total supply
+	supplyExpr := "TotalSupply()"
+
+	supply, output, err := p.evalUint(token.PkgPath, supplyExpr)
+	if err != nil {
+		return probeError(supplyExpr, err, output)
+	}
+
+	// Check the deployer balance, if the deployer is known
+	if err := p.probeBalance(token, supply); err != nil {
+		return err
+	}
+
+	// Check the optional metadata getters
+	decimals, output, err := p.evalUint(token.PkgPath, "Decimals()")
+
+	switch {
+	case errors.Is(err, errNotDeclared):
+	case err != nil:
+		return probeError("Decimals()", err, output)
+	case decimals != uint64(token.Decimals):
+		return &Error{
+			Probe:  "Decimals()",
+			Reason: fmt.Sprintf("decimals %d differ from the source metadata %d", decimals, token.Decimals),
+			Output: output,
+		}
+	}
+
+	for _, getter := range []struct {
+		expr     string
+		expected string
+	}{
+		{"GetName()", token.Name},
+		{"GetSymbol()", token.Symbol},
+	} {
+		value, output, err := p.evalString(token.PkgPath, getter.expr)
+
+		switch {
+		case errors.Is(err, errNotDeclared):
+		case err != nil:
+			return probeError(getter.expr, err, output)
+		case value != getter.expected:
+			return &Error{
+				Probe:  getter.expr,
+				Reason: fmt.Sprintf("value %q differs from the source metadata %q", value, getter.expected),
+				Output: output,
+			}
+		}
+	}
+
+	return nil
+}
+
+// probeBalance checks the deployer balance doesn't exceed the supply.
+// The probe is skipped if the deployer is unknown, since the balance
+// lookup of an empty address panics in most grc20 implementations
+func (p *Prober) probeBalance(token *ledger.Token, supply uint64) error {
+	if token.Deployer == "" {
+		return nil
+	}
+
+	balanceExpr := fmt.Sprintf("BalanceOf(%q)", token.Deployer)
+
+	balance, output, err := p.evalUint(token.PkgPath, balanceExpr)
+	if err != nil {
+		return probeError(balanceExpr, err, output)
+	}
+
+	if balance > supply {
+		return &Error{
+			Probe:  balanceExpr,
+			Reason: fmt.Sprintf("deployer balance %d exceeds the total supply %d", balance, supply),
+			Output: output,
+		}
+	}
+
+	return nil
+}
+
+// evalUint evaluates the expression, and parses the unsigned integer result
+func (p *Prober) evalUint(pkgPath, expr string) (uint64, string, error) {
+	value, output, err := p.eval(pkgPath, expr)
+	if err != nil {
+		return 0, output, err
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, output, fmt.Errorf("unable to parse result as uint, %w", err)
+	}
+
+	return parsed, output, nil
+}
+
+// evalString evaluates the expression, and parses the string result
+func (p *Prober) evalString(pkgPath, expr string) (string, string, error) {
+	value, output, err := p.eval(pkgPath, expr)
+	if err != nil {
+		return "", output, err
+	}
+
+	parsed, err := strconv.Unquote(value)
+	if err != nil {
+		return "", output, fmt.Errorf("unable to parse result as string, %w", err)
+	}
+
+	return parsed, output, nil
+}
+
+// eval evaluates the expression in the package,
+// returning the result value and the raw output
+func (p *Prober) eval(pkgPath, expr string) (string, string, error) {
+	res, err := p.client.ABCIQuery(qevalPath, []byte(fmt.Sprintf("%s.%s", pkgPath, expr)))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to query, %w", err)
+	}
+
+	if res.Response.Error != nil {
+		output := res.Response.Log
+		if output == "" {
+			output = res.Response.Error.Error()
+		}
+
+		if strings.Contains(output, "not declared") {
+			return "", output, errNotDeclared
+		}
+
+		return "", output, errors.New("query returned an error")
+	}
+
+	output := string(res.Response.Data)
+
+	value, err := parseResult(output)
+	if err != nil {
+		return "", output, err
+	}
+
+	return value, output, nil
+}
+
+// parseResult parses the value of a single typed qeval result
+func parseResult(output string) (string, error) {
+	matches := qevalResultRegex.FindStringSubmatch(strings.TrimSpace(output))
+	if len(matches) == 0 {
+		return "", errors.New("unexpected result format")
+	}
+
+	return matches[1], nil
+}
+
+// probeError wraps the evaluation error into a probe error
+func probeError(expr string, err error, output string) *Error {
+	return &Error{
+		Probe:  expr,
+		Reason: err.Error(),
+		Output: output,
+	}
+}
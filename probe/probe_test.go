@@ -0,0 +1,182 @@
+package probe
+
+import (
+	"strings"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// newQevalClient creates a mock client that
+// serves the given qeval outputs, by expression
+func newQevalClient(outputs map[string]string) *mockClient {
+	return &mockClient{
+		abciQueryFn: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+			expr := strings.TrimPrefix(string(data), "gno.land/r/demo/foo.")
+
+			output, ok := outputs[expr]
+			if !ok {
+				return &ctypes.ResultABCIQuery{
+					Response: abci.ResponseQuery{
+						ResponseBase: abci.ResponseBase{
+							Error: abci.StringError("name " + expr + " not declared"),
+						},
+					},
+				}, nil
+			}
+
+			return &ctypes.ResultABCIQuery{
+				Response: abci.ResponseQuery{
+					ResponseBase: abci.ResponseBase{
+						Data: []byte(output),
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestProber_Probe(t *testing.T) {
+	t.Parallel()
+
+	token := &ledger.Token{
+		PkgPath:  "gno.land/r/demo/foo",
+		Name:     "Foo",
+		Symbol:   "FOO",
+		Deployer: "g1alice",
+		Decimals: 4,
+	}
+
+	testTable := []struct {
+		name          string
+		outputs       map[string]string
+		expectedProbe string
+	}{
+		{
+			"valid token",
+			map[string]string{
+				"TotalSupply()":        "(10000 uint64)",
+				`BalanceOf("g1alice")`: "(5000 uint64)",
+				"Decimals()":           "(4 uint)",
+				"GetName()":            `("Foo" string)`,
+				"GetSymbol()":          `("FOO" string)`,
+			},
+			"",
+		},
+		{
+			"valid token without getters",
+			map[string]string{
+				"TotalSupply()":        "(10000 uint64)",
+				`BalanceOf("g1alice")`: "(5000 uint64)",
+			},
+			"",
+		},
+		{
+			"missing total supply",
+			map[string]string{
+				`BalanceOf("g1alice")`: "(5000 uint64)",
+			},
+			"TotalSupply()",
+		},
+		{
+			"unparsable total supply",
+			map[string]string{
+				"TotalSupply()":        "(nil *uint64)",
+				`BalanceOf("g1alice")`: "(5000 uint64)",
+			},
+			"TotalSupply()",
+		},
+		{
+			"balance exceeds supply",
+			map[string]string{
+				"TotalSupply()":        "(10000 uint64)",
+				`BalanceOf("g1alice")`: "(50000 uint64)",
+			},
+			`BalanceOf("g1alice")`,
+		},
+		{
+			"inconsistent decimals",
+			map[string]string{
+				"TotalSupply()":        "(10000 uint64)",
+				`BalanceOf("g1alice")`: "(5000 uint64)",
+				"Decimals()":           "(6 uint)",
+			},
+			"Decimals()",
+		},
+		{
+			"inconsistent symbol",
+			map[string]string{
+				"TotalSupply()":        "(10000 uint64)",
+				`BalanceOf("g1alice")`: "(5000 uint64)",
+				"GetSymbol()":          `("BAR" string)`,
+			},
+			"GetSymbol()",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := New(newQevalClient(testCase.outputs))
+
+			err := p.Probe(token)
+			if testCase.expectedProbe == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var probeErr *Error
+
+			require.ErrorAs(t, err, &probeErr)
+
+			assert.Equal(t, testCase.expectedProbe, probeErr.Probe)
+
+			// Make sure the raw output is reported
+			if output, ok := testCase.outputs[testCase.expectedProbe]; ok {
+				assert.Equal(t, output, probeErr.Output)
+			} else {
+				assert.Contains(t, probeErr.Output, "not declared")
+			}
+		})
+	}
+}
+
+func TestProber_Probe_UnknownDeployer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = &ledger.Token{
+			PkgPath: "gno.land/r/demo/foo",
+			Name:    "Foo",
+			Symbol:  "FOO",
+		}
+
+		// The balance lookup of the empty address panics
+		outputs = map[string]string{
+			"TotalSupply()": "(10000 uint64)",
+			"GetName()":     `("Foo" string)`,
+			"GetSymbol()":   `("FOO" string)`,
+		}
+	)
+
+	client := newQevalClient(outputs)
+	lookup := client.abciQueryFn
+
+	client.abciQueryFn = func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+		require.NotContains(t, string(data), "BalanceOf")
+
+		return lookup(path, data)
+	}
+
+	// Make sure the balance probe is skipped
+	assert.NoError(t, New(client).Probe(token))
+}
@@ -0,0 +1,9 @@
+package probe
+
+import ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+
+// Client is the read-only chain query client
+type Client interface {
+	// ABCIQuery runs the ABCI query on the given path
+	ABCIQuery(path string, data []byte) (*ctypes.ResultABCIQuery, error)
+}
@@ -0,0 +1,156 @@
+package registrar
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// ErrInvalidStatus is the error returned when the admin action
+// is not applicable to the current token status
+var ErrInvalidStatus = errors.New("invalid token status")
+
+// ListPending returns the tokens pending a manual approval
+func (r *Registrar) ListPending() ([]*ledger.Token, error) {
+	tokens, err := r.storage.GetTokens()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch tokens, %w", err)
+	}
+
+	pending := make([]*ledger.Token, 0)
+
+	for _, token := range tokens {
+		if token.Status == ledger.StatusNeedsReview {
+			pending = append(pending, token)
+		}
+	}
+
+	return pending, nil
+}
+
+// Approve approves the pending token, and adds it
+// to the registration queue
+func (r *Registrar) Approve(pkgPath string) (*ledger.Token, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	token, err := r.getToken(pkgPath, ledger.StatusNeedsReview)
+	if err != nil {
+		return nil, err
+	}
+
+	// The queued token is registered with the
+	// next queue run, without the policy evaluation
+	token.Status = ledger.StatusQueued
+	token.Error = ""
+
+	r.logger.Info("grc20 token approved", zap.String("pkgPath", pkgPath))
+
+	r.save(token, nil)
+
+	return token, nil
+}
+
+// Reject rejects the pending (or queued) token, with the given reason
+func (r *Registrar) Reject(pkgPath, reason string) (*ledger.Token, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	token, err := r.getToken(pkgPath, ledger.StatusNeedsReview, ledger.StatusQueued)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = "rejected by admin"
+	}
+
+	token.Status = ledger.StatusRejected
+	token.PolicyReason = reason
+
+	r.logger.Info("grc20 token rejected", zap.String("pkgPath", pkgPath), zap.String("reason", reason))
+
+	r.save(token, nil)
+
+	return token, nil
+}
+
+// Retry retries the registration of the failed token right away,
+// without the policy evaluation. The fee budget still applies
+func (r *Registrar) Retry(pkgPath string) (*ledger.Token, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	token, err := r.getToken(
+		pkgPath,
+		ledger.StatusFailed,
+		ledger.StatusSimulationFailed,
+		ledger.StatusProbeFailed,
+		ledger.StatusTemplateError,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := r.registerer.EstimateFee()
+
+	if err := r.budget.Check(token.Deployer, fee.Amount, time.Now()); err != nil {
+		r.queue(token, err)
+
+		return token, nil
+	}
+
+	r.register(token)
+
+	return token, nil
+}
+
+// Requeue sends the token through the registration pipeline again,
+// from the policy evaluation. The token status is checked and moved under
+// the registrar lock, so the tokens registering, or registered meanwhile,
+// are not requeued
+func (r *Registrar) Requeue(pkgPath string) (*ledger.Token, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	token, err := r.getToken(
+		pkgPath,
+		ledger.StatusFailed,
+		ledger.StatusSimulationFailed,
+		ledger.StatusProbeFailed,
+		ledger.StatusTemplateError,
+		ledger.StatusRejected,
+		ledger.StatusNeedsReview,
+		ledger.StatusQueued,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Error = ""
+	token.PolicyReason = ""
+
+	r.handle(token)
+
+	return token, nil
+}
+
+// getToken fetches the token ledger record,
+// and verifies it has one of the given statuses
+func (r *Registrar) getToken(pkgPath string, statuses ...ledger.Status) (*ledger.Token, error) {
+	token, err := r.storage.GetToken(pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch token %s, %w", pkgPath, err)
+	}
+
+	if !slices.Contains(statuses, token.Status) {
+		return nil, fmt.Errorf("%w, token %s is %s", ErrInvalidStatus, pkgPath, token.Status)
+	}
+
+	return token, nil
+}
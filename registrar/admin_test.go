@@ -0,0 +1,181 @@
+package registrar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+)
+
+func TestRegistrar_Approve(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return nil, nil
+			},
+		}
+
+		p = &mockPolicy{
+			evaluateFn: func(_ *ledger.Token) policy.Result {
+				return policy.Result{Decision: policy.DecisionReview, Reason: "unknown deployer"}
+			},
+		}
+
+		pkgPath = "gno.land/r/demo/foo"
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithPolicy(p))
+
+	r.Register(&ledger.Token{PkgPath: pkgPath})
+
+	// Make sure the token is pending
+	pending, err := r.ListPending()
+	require.NoError(t, err)
+
+	require.Len(t, pending, 1)
+	assert.Equal(t, pkgPath, pending[0].PkgPath)
+
+	// Make sure a pending token can't be retried
+	_, err = r.Retry(pkgPath)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+
+	// Approve the token, and run the queue
+	token, err := r.Approve(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusQueued, token.Status)
+
+	r.processQueue()
+
+	assert.Equal(t, []string{pkgPath}, registered)
+
+	token, err = s.GetToken(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, token.Status)
+
+	// Make sure the registered token can't be rejected
+	_, err = r.Reject(pkgPath, "")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+func TestRegistrar_Reject(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		p = &mockPolicy{
+			evaluateFn: func(_ *ledger.Token) policy.Result {
+				return policy.Result{Decision: policy.DecisionReview}
+			},
+		}
+
+		pkgPath = "gno.land/r/demo/foo"
+	)
+
+	r := New(s, &mockRegisterer{}, &mockEvents{}, WithPolicy(p))
+
+	r.Register(&ledger.Token{PkgPath: pkgPath})
+
+	token, err := r.Reject(pkgPath, "impersonation")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRejected, token.Status)
+	assert.Equal(t, "impersonation", token.PolicyReason)
+
+	// Requeue the token, which is evaluated again
+	token, err = r.Requeue(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusNeedsReview, token.Status)
+}
+
+func TestRegistrar_Requeue_Registering(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		probing = make(chan struct{})
+		unblock = make(chan struct{})
+
+		registered = make([]string, 0)
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return nil, nil
+			},
+		}
+
+		probes = 0
+		prober = &mockProber{
+			probeFn: func(_ *ledger.Token) error {
+				probes++
+
+				if probes == 1 {
+					close(probing)
+					<-unblock
+				}
+
+				return nil
+			},
+		}
+
+		pkgPath = "gno.land/r/demo/foo"
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithProber(prober))
+
+	writeTokens(t, s, false, &ledger.Token{PkgPath: pkgPath, Status: ledger.StatusFailed})
+
+	// Retry the failed token, which blocks in the probes,
+	// before its status is moved from failed
+	retried := make(chan error, 1)
+
+	go func() {
+		_, err := r.Retry(pkgPath)
+
+		retried <- err
+	}()
+
+	<-probing
+
+	// Requeue the token while the retry is in flight
+	requeued := make(chan error, 1)
+
+	go func() {
+		_, err := r.Requeue(pkgPath)
+
+		requeued <- err
+	}()
+
+	// Let the requeue reach the registrar lock
+	time.Sleep(50 * time.Millisecond)
+
+	close(unblock)
+
+	require.NoError(t, <-retried)
+
+	// Make sure the token registered meanwhile is not registered again
+	assert.ErrorIs(t, <-requeued, ErrInvalidStatus)
+	assert.Equal(t, []string{pkgPath}, registered)
+
+	token, err := s.GetToken(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, token.Status)
+}
@@ -0,0 +1,234 @@
+package registrar
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// DefaultBatchSize is the default maximum number
+// of tokens registered in a single batch tx
+const DefaultBatchSize = 10
+
+// batch is the pending registration batch
+type batch struct {
+	tokens []*ledger.Token
+	timer  *time.Timer
+}
+
+// contains returns a flag indicating if the token is in the pending batch
+func (b *batch) contains(pkgPath string) bool {
+	for _, token := range b.tokens {
+		if token.PkgPath == pkgPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// batchRegisterer returns the batch registration tx broadcaster,
+// if the registrar is configured for batch registrations
+func (r *Registrar) batchRegisterer() (BatchRegisterer, bool) {
+	if r.batchWindow <= 0 {
+		return nil, false
+	}
+
+	registerer, ok := r.registerer.(BatchRegisterer)
+
+	return registerer, ok
+}
+
+// addToBatch adds the token to the pending registration batch.
+// The batch is registered once the window closes, or once it is full
+func (r *Registrar) addToBatch(token *ledger.Token) {
+	token.Status = ledger.StatusBatched
+	token.Error = ""
+
+	r.save(token, nil)
+
+	r.pending.tokens = append(r.pending.tokens, token)
+
+	if len(r.pending.tokens) >= r.batchSize {
+		r.flush()
+
+		return
+	}
+
+	if r.pending.timer == nil {
+		r.pending.timer = time.AfterFunc(r.batchWindow, func() {
+			r.mux.Lock()
+			defer r.mux.Unlock()
+
+			r.flush()
+		})
+	}
+}
+
+// flush registers the pending registration batch
+func (r *Registrar) flush() {
+	if r.pending.timer != nil {
+		r.pending.timer.Stop()
+	}
+
+	tokens := r.pending.tokens
+	r.pending = batch{}
+
+	r.registerBatch(tokens)
+}
+
+// registerBatch registers the tokens in a single tx, and saves the
+// outcomes and the paid fee to the ledger. If the batch tx fails,
+// the tokens are registered individually
+func (r *Registrar) registerBatch(tokens []*ledger.Token) {
+	probed := make([]*ledger.Token, 0, len(tokens))
+
+	for _, token := range tokens {
+		if r.probe(token) {
+			probed = append(probed, token)
+		}
+	}
+
+	registerer, ok := r.batchRegisterer()
+	if !ok || len(probed) < 2 {
+		for _, token := range probed {
+			r.submit(token)
+		}
+
+		return
+	}
+
+	// The batch is only registered by the running lease holder
+	if err := r.halted(); err != nil {
+		for _, token := range probed {
+			r.queue(token, err)
+		}
+
+		return
+	}
+
+	prepared, canPrepare := r.txRegisterer()
+
+	// The signed registration txs are not checked on chain by the
+	// registerer, so the registered tokens are never signed again
+	if canPrepare {
+		unregistered := make([]*ledger.Token, 0, len(probed))
+
+		for _, token := range probed {
+			if r.unregistered(token) {
+				unregistered = append(unregistered, token)
+			}
+		}
+
+		if len(unregistered) == 0 {
+			return
+		}
+
+		probed = unregistered
+	}
+
+	pkgPaths := make([]string, 0, len(probed))
+	for _, token := range probed {
+		token.Version = r.version
+
+		pkgPaths = append(pkgPaths, token.PkgPath)
+	}
+
+	var (
+		receipt *addpkg.Receipt
+		err     error
+	)
+
+	if canPrepare {
+		receipt, err = r.sendPrepared(prepared, ledger.KindGRC20, probed...)
+	} else {
+		// The batch is never broadcast without its tokens being
+		// marked as registering. The tokens that were marked
+		// are verified on chain by the next queue run
+		for _, token := range probed {
+			if !r.markRegistering(token) {
+				return
+			}
+		}
+
+		receipt, err = registerer.RegisterGrc20Tokens(pkgPaths)
+	}
+
+	// The tokens are left registering, and their outcome is
+	// resolved by the precomputed hash on the next queue run
+	if errors.Is(err, addpkg.ErrBroadcastUncertain) {
+		r.logger.Warn("batch registration outcome unknown", zap.Strings("pkgPaths", pkgPaths), zap.Error(err))
+
+		return
+	}
+
+	// The fee is paid for every committed tx,
+	// and is split between the batched tokens
+	spends := make([]*ledger.Spend, len(probed))
+
+	if receipt != nil {
+		var (
+			now    = time.Now()
+			amount = receipt.Fee.Amount / int64(len(probed))
+		)
+
+		for i, token := range probed {
+			spends[i] = &ledger.Spend{
+				Time:     now,
+				PkgPath:  token.PkgPath,
+				Deployer: token.Deployer,
+				TxHash:   receipt.TxHash,
+				Denom:    receipt.Fee.Denom,
+				Amount:   amount,
+			}
+
+			// The remainder is attributed to the first token
+			if i == 0 {
+				spends[i].Amount += receipt.Fee.Amount % int64(len(probed))
+			}
+
+			r.budget.Record(spends[i])
+		}
+	}
+
+	if err != nil {
+		r.logger.Warn(
+			"batch registration failed, registering the tokens individually",
+			zap.Strings("pkgPaths", pkgPaths),
+			zap.Error(err),
+		)
+
+		fee := r.registerer.EstimateFee()
+
+		for i, token := range probed {
+			if spends[i] != nil {
+				r.save(token, spends[i])
+			}
+
+			// The individual registrations must still fit into the fee budget
+			if err := r.budget.Check(token.Deployer, fee.Amount, time.Now()); err != nil {
+				r.queue(token, err)
+
+				continue
+			}
+
+			r.submit(token)
+		}
+
+		return
+	}
+
+	for i, token := range probed {
+		r.setOutcome(token, nil)
+
+		if receipt != nil {
+			token.RegisterTxHash = receipt.TxHash
+		}
+
+		r.save(token, spends[i])
+	}
+}
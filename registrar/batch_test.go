@@ -0,0 +1,177 @@
+package registrar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestRegistrar_RegisterBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fee = std.NewCoin("ugnot", 1_000_001)
+
+		s = newTestStorage(t)
+
+		batches    = make([][]string, 0)
+		registerer = &mockBatchRegisterer{
+			mockRegisterer: mockRegisterer{
+				estimateFeeFn: func() std.Coin {
+					return fee
+				},
+				registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+					t.Fatalf("unexpected individual registration of %s", pkgPath)
+
+					return nil, nil
+				},
+			},
+			registerGrc20TokensFn: func(pkgPaths []string) (*addpkg.Receipt, error) {
+				batches = append(batches, pkgPaths)
+
+				return &addpkg.Receipt{
+					TxHash: "batch hash",
+					Fee:    fee,
+				}, nil
+			},
+		}
+
+		tokens = []*ledger.Token{
+			{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice"},
+			{PkgPath: "gno.land/r/demo/bar", Deployer: "g1bob"},
+		}
+	)
+
+	r := New(
+		s,
+		registerer,
+		&mockEvents{},
+		WithBatchWindow(time.Hour),
+		WithBatchSize(len(tokens)),
+	)
+
+	// Make sure the first token waits for the batch
+	r.Register(tokens[0])
+
+	foo, err := s.GetToken(tokens[0].PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusBatched, foo.Status)
+	assert.Empty(t, batches)
+
+	// Make sure the full batch is registered in a single tx
+	r.Register(tokens[1])
+
+	require.Len(t, batches, 1)
+	assert.Equal(t, []string{tokens[0].PkgPath, tokens[1].PkgPath}, batches[0])
+
+	for _, token := range tokens {
+		saved, err := s.GetToken(token.PkgPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, ledger.StatusRegistered, saved.Status)
+		assert.Equal(t, "batch hash", saved.RegisterTxHash)
+	}
+
+	// Make sure the fee was split between the tokens
+	spends, err := s.GetSpends()
+	require.NoError(t, err)
+
+	require.Len(t, spends, 2)
+	assert.Equal(t, fee.Amount, spends[0].Amount+spends[1].Amount)
+}
+
+func TestRegistrar_RegisterBatch_Fallback(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockBatchRegisterer{
+			mockRegisterer: mockRegisterer{
+				registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+					registered = append(registered, pkgPath)
+
+					if pkgPath == "gno.land/r/demo/broken" {
+						return nil, errors.New("invalid package")
+					}
+
+					return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+				},
+			},
+			registerGrc20TokensFn: func(_ []string) (*addpkg.Receipt, error) {
+				return nil, errors.New("invalid package")
+			},
+		}
+
+		tokens = []*ledger.Token{
+			{PkgPath: "gno.land/r/demo/foo"},
+			{PkgPath: "gno.land/r/demo/broken"},
+		}
+	)
+
+	r := New(
+		s,
+		registerer,
+		&mockEvents{},
+		WithBatchWindow(time.Hour),
+		WithBatchSize(len(tokens)),
+	)
+
+	for _, token := range tokens {
+		r.Register(token)
+	}
+
+	// Make sure the tokens were registered individually
+	assert.Equal(t, []string{tokens[0].PkgPath, tokens[1].PkgPath}, registered)
+
+	foo, err := s.GetToken(tokens[0].PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "hash gno.land/r/demo/foo", foo.RegisterTxHash)
+
+	broken, err := s.GetToken(tokens[1].PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusFailed, broken.Status)
+	assert.Equal(t, "invalid package", broken.Error)
+}
+
+func TestRegistrar_RegisterBatch_Window(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registerer = &mockBatchRegisterer{}
+		token      = &ledger.Token{PkgPath: "gno.land/r/demo/foo"}
+	)
+
+	r := New(
+		s,
+		registerer,
+		&mockEvents{},
+		WithBatchWindow(10*time.Millisecond),
+	)
+
+	r.Register(token)
+
+	// Make sure the batch is registered once the window closes
+	require.Eventually(t, func() bool {
+		saved, err := s.GetToken(token.PkgPath)
+		if err != nil {
+			return false
+		}
+
+		return saved.Status == ledger.StatusRegistered
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,127 @@
+package registrar
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrPaused is the error of the registrations
+	// that were not broadcast, since the registrar is paused
+	ErrPaused = errors.New("registrations paused")
+
+	// ErrNotDrained is returned when the in-flight registration is not
+	// drained within the drain timeout, on shutdown. It is abandoned,
+	// and is still able to write to the storage until it returns
+	ErrNotDrained = errors.New("in-flight registration not drained in time")
+
+	errDraining = errors.New("registrar shutting down")
+)
+
+// Pause pauses the registrations, without stopping the detection. It returns
+// once the in-flight registration, if any, is done. The detected tokens
+// stay persisted as intents, or queued, and are registered once resumed
+func (r *Registrar) Pause() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.paused.Swap(true) {
+		return
+	}
+
+	r.logger.Info("registrations paused")
+}
+
+// Resume resumes the paused registrations, and hands off
+// the intents and the queued tokens to the registrar service
+func (r *Registrar) Resume() {
+	if !r.paused.Swap(false) {
+		return
+	}
+
+	r.logger.Info("registrations resumed")
+
+	select {
+	case r.resumed <- struct{}{}:
+	default:
+		// The resume is already signaled
+	}
+}
+
+// Paused checks if the registrations are paused
+func (r *Registrar) Paused() bool {
+	return r.paused.Load()
+}
+
+// stopped checks if the registrar is not allowed to pick up new work,
+// since it is paused, or shutting down. The work is left persisted
+func (r *Registrar) stopped() bool {
+	return r.paused.Load() || r.draining()
+}
+
+// draining checks if the registrar service is shutting down
+func (r *Registrar) draining() bool {
+	ctx := r.serveCtx.Load()
+
+	return ctx != nil && (*ctx).Err() != nil
+}
+
+// halted returns the reason the registration txs are not allowed
+// to be broadcast, if the registrar is stopped, or if it doesn't
+// hold the single-writer lease
+func (r *Registrar) halted() error {
+	switch {
+	case r.draining():
+		return errDraining
+	case r.paused.Load():
+		return ErrPaused
+	default:
+		return r.fenced()
+	}
+}
+
+// drain waits for the in-flight registration, if any, up to the drain
+// timeout. The pending batch is left batched, and is registered on the next
+// start. The registration that is not drained in time is abandoned, and
+// its outcome is resolved on the next start, by its persisted pending tx
+func (r *Registrar) drain() error {
+	locked := make(chan struct{})
+
+	go func() {
+		r.mux.Lock()
+		close(locked)
+	}()
+
+	if r.drainTimeout > 0 {
+		timer := time.NewTimer(r.drainTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-locked:
+		case <-timer.C:
+			// The lock is released once the abandoned registration is done
+			go func() {
+				<-locked
+				r.mux.Unlock()
+			}()
+
+			r.logger.Warn("in-flight registration abandoned", zap.Duration("timeout", r.drainTimeout))
+
+			return ErrNotDrained
+		}
+	} else {
+		<-locked
+	}
+
+	defer r.mux.Unlock()
+
+	if r.pending.timer != nil {
+		r.pending.timer.Stop()
+	}
+
+	r.logger.Info("registrations drained", zap.Int("batched", len(r.pending.tokens)))
+
+	return nil
+}
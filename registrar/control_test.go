@@ -0,0 +1,190 @@
+package registrar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestRegistrar_Pause(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.Pause()
+	require.True(t, r.Paused())
+
+	// Make sure the intents are left persisted while paused
+	writeTokens(t, s, true, &ledger.Token{PkgPath: "gno.land/r/demo/foo", Height: 5})
+
+	r.processIntents()
+
+	intents, err := s.GetIntents()
+	require.NoError(t, err)
+
+	assert.Len(t, intents, 1)
+
+	// Make sure the admin registrations are queued while paused
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/bar", Height: 10})
+
+	bar, err := s.GetToken("gno.land/r/demo/bar")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusQueued, bar.Status)
+	assert.Equal(t, ErrPaused.Error(), bar.Error)
+
+	assert.Empty(t, registered)
+
+	// Make sure the resume is signaled to the service
+	r.Resume()
+	require.False(t, r.Paused())
+
+	select {
+	case <-r.resumed:
+	default:
+		t.Fatal("resume not signaled")
+	}
+
+	r.processIntents()
+	r.processQueue()
+
+	assert.Equal(t, []string{"gno.land/r/demo/foo", "gno.land/r/demo/bar"}, registered)
+}
+
+func TestRegistrar_Serve_Drain(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		started = make(chan struct{})
+		release = make(chan struct{})
+
+		registered = make([]string, 0)
+
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				close(started)
+				<-release
+
+				registered = append(registered, pkgPath)
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+	)
+
+	writeTokens(
+		t,
+		s,
+		true,
+		&ledger.Token{PkgPath: "gno.land/r/demo/foo", Height: 5},
+		&ledger.Token{PkgPath: "gno.land/r/demo/bar", Height: 10},
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- r.Serve(ctx)
+	}()
+
+	// Shut down mid-registration
+	<-started
+	cancelFn()
+
+	select {
+	case <-done:
+		t.Fatal("registrar shut down before the in-flight registration was drained")
+	default:
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+
+	// Make sure the in-flight registration was drained,
+	// and the remaining intent was left persisted
+	assert.Equal(t, []string{"gno.land/r/demo/foo"}, registered)
+
+	foo, err := s.GetToken("gno.land/r/demo/foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+
+	intents, err := s.GetIntents()
+	require.NoError(t, err)
+
+	require.Len(t, intents, 1)
+	assert.Equal(t, "gno.land/r/demo/bar", intents[0].PkgPath)
+}
+
+func TestRegistrar_DrainTimeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		started = make(chan struct{})
+		release = make(chan struct{})
+
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				close(started)
+				<-release
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithDrainTimeout(10*time.Millisecond))
+
+	// Register the token outside the service, ex. on an admin approval
+	registered := make(chan struct{})
+
+	go func() {
+		r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+		close(registered)
+	}()
+
+	// Make sure the in-flight registration is abandoned after the timeout
+	<-started
+
+	assert.ErrorIs(t, r.drain(), ErrNotDrained)
+
+	// Make sure the abandoned registration still completes,
+	// and the registrar is drained once it is done
+	close(release)
+	<-registered
+
+	require.NoError(t, r.drain())
+
+	foo, err := s.GetToken("gno.land/r/demo/foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+}
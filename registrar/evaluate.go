@@ -0,0 +1,52 @@
+package registrar
+
+import (
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+)
+
+// Catalog returns the registered tokens, the impersonation check compares against
+type Catalog func() ([]*ledger.Token, error)
+
+// Evaluate evaluates the registration policy, if any, and the impersonation
+// check for the token. Suspected impersonations always require a manual
+// approval. It is shared by the registration pipeline and the offline
+// detection, so their verdicts never drift apart
+func Evaluate(
+	p Policy,
+	impersonation *detector.ImpersonationChecker,
+	token *ledger.Token,
+	catalog Catalog,
+) policy.Result {
+	result := policy.Result{Decision: policy.DecisionAllow}
+
+	if p != nil {
+		result = p.Evaluate(token)
+	}
+
+	if result.Decision != policy.DecisionAllow {
+		return result
+	}
+
+	registered, err := catalog()
+	if err != nil {
+		return policy.Result{
+			Decision: policy.DecisionReview,
+			Reason:   fmt.Sprintf("unable to check for impersonation, %s", err),
+		}
+	}
+
+	if match, found := impersonation.Check(token, registered); found {
+		token.Impersonates = match.Target
+
+		return policy.Result{
+			Decision: policy.DecisionReview,
+			Reason:   fmt.Sprintf("suspected impersonation, %s", match),
+		}
+	}
+
+	return result
+}
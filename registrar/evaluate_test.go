@@ -0,0 +1,94 @@
+package registrar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rejecting = &mockPolicy{
+			evaluateFn: func(_ *ledger.Token) policy.Result {
+				return policy.Result{Decision: policy.DecisionReject, Reason: "rejected"}
+			},
+		}
+
+		registered = func() ([]*ledger.Token, error) {
+			return []*ledger.Token{
+				{PkgPath: "gno.land/r/gnoswap/gns", Name: "Gnoswap", Symbol: "GNS"},
+			}, nil
+		}
+	)
+
+	testTable := []struct {
+		name                 string
+		policy               Policy
+		token                *ledger.Token
+		catalog              Catalog
+		expectedDecision     policy.Decision
+		expectedImpersonates string
+	}{
+		{
+			"allowed token",
+			nil,
+			&ledger.Token{Name: "Foo", Symbol: "FOO"},
+			registered,
+			policy.DecisionAllow,
+			"",
+		},
+		{
+			"rejected by policy",
+			rejecting,
+			&ledger.Token{Name: "Gnoswap", Symbol: "GNS"},
+			func() ([]*ledger.Token, error) {
+				panic("the catalog is only checked for the allowed tokens")
+			},
+			policy.DecisionReject,
+			"",
+		},
+		{
+			"suspected impersonation",
+			&mockPolicy{},
+			&ledger.Token{Name: "Gnoswap", Symbol: "GΝЅ"},
+			registered,
+			policy.DecisionReview,
+			`"GNS" of gno.land/r/gnoswap/gns`,
+		},
+		{
+			"catalog unavailable",
+			nil,
+			&ledger.Token{Name: "Foo", Symbol: "FOO"},
+			func() ([]*ledger.Token, error) {
+				return nil, errors.New("storage closed")
+			},
+			policy.DecisionReview,
+			"",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Evaluate(
+				testCase.policy,
+				detector.NewImpersonationChecker(nil),
+				testCase.token,
+				testCase.catalog,
+			)
+
+			assert.Equal(t, testCase.expectedDecision, result.Decision)
+			assert.Equal(t, testCase.expectedImpersonates, testCase.token.Impersonates)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package registrar
+
+import (
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// fenced checks that the registrar still holds
+// the single-writer lease, if it is configured
+func (r *Registrar) fenced() error {
+	if r.fence == nil {
+		return nil
+	}
+
+	return r.fence.Validate()
+}
+
+// linked marks the token as registered, if it is already linked to a
+// committed registration tx of the active registry version, ex. sent by
+// the previous lease holder. It returns a flag indicating if the token was linked
+func (r *Registrar) linked(token *ledger.Token) bool {
+	links, err := r.storage.GetRegistrationTxs(token.PkgPath)
+	if err != nil {
+		r.logger.Error("unable to fetch registration txs", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+		return false
+	}
+
+	// The registrations to the previous registry
+	// versions don't register the token to the active one
+	index := slices.IndexFunc(links, func(link *ledger.RegistrationTx) bool {
+		return link.Version == r.version
+	})
+
+	if index == -1 {
+		return false
+	}
+
+	token.Status = ledger.StatusRegistered
+	token.Error = ""
+	token.PendingTx = nil
+	token.RegisterTxHash = links[index].TxHash
+
+	r.logger.Info(
+		"token already registered by a linked registration tx",
+		zap.String("pkgPath", token.PkgPath),
+		zap.String("txHash", token.RegisterTxHash),
+	)
+
+	r.save(token, nil)
+
+	return true
+}
@@ -0,0 +1,118 @@
+package registrar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestRegistrar_Register_Fenced(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		pkgPath    = "gno.land/r/demo/foo"
+		leading    = false
+		registered = make([]string, 0)
+
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+
+		fence = &mockFence{
+			validateFn: func() error {
+				if !leading {
+					return errors.New("lease not held")
+				}
+
+				return nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithFence(fence))
+
+	r.Register(&ledger.Token{PkgPath: pkgPath})
+
+	// Make sure the token was queued, without a broadcast
+	token, err := s.GetToken(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusQueued, token.Status)
+	assert.Empty(t, registered)
+
+	// Make sure the token is registered once the lease is held
+	leading = true
+
+	r.processQueue()
+
+	token, err = s.GetToken(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, token.Status)
+	assert.Equal(t, []string{pkgPath}, registered)
+}
+
+func TestRegistrar_ProcessIntents_Linked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+	)
+
+	writeTokens(
+		t,
+		s,
+		true,
+		&ledger.Token{PkgPath: "gno.land/r/demo/foo", DeployTxHash: "deploy foo", Height: 5},
+		&ledger.Token{PkgPath: "gno.land/r/demo/bar", DeployTxHash: "deploy bar", Height: 10},
+	)
+
+	// The foo token was registered by the previous lease holder
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetRegistrationTx(&ledger.RegistrationTx{
+		TxHash:  "registration foo",
+		TokenID: "gno.land/r/demo/foo",
+		Height:  7,
+	}))
+	require.NoError(t, wb.Commit())
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.processIntents()
+
+	// Make sure only the unlinked token was registered
+	assert.Equal(t, []string{"gno.land/r/demo/bar"}, registered)
+
+	foo, err := s.GetToken("gno.land/r/demo/foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "registration foo", foo.RegisterTxHash)
+
+	intents, err := s.GetIntents()
+	require.NoError(t, err)
+
+	assert.Empty(t, intents)
+}
@@ -0,0 +1,180 @@
+package registrar
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// NotifyIntents signals the newly committed registration intents.
+// The intents are consumed by the registrar service, without blocking the caller
+func (r *Registrar) NotifyIntents() {
+	select {
+	case r.intents <- struct{}{}:
+	default:
+		// The intents are already signaled
+	}
+}
+
+// processIntents consumes the committed registration intents,
+// in the order the tokens were detected
+func (r *Registrar) processIntents() {
+	if r.stopped() {
+		return
+	}
+
+	intents, err := r.storage.GetIntents()
+	if err != nil {
+		r.logger.Error("unable to fetch registration intents", zap.Error(err))
+
+		return
+	}
+
+	sort.SliceStable(intents, func(i, j int) bool {
+		return intents[i].Height < intents[j].Height
+	})
+
+	for _, intent := range intents {
+		// The rest of the intents are consumed once resumed, or on the next start
+		if r.stopped() {
+			return
+		}
+
+		r.mux.Lock()
+		r.consume(intent)
+		r.mux.Unlock()
+	}
+}
+
+// consume hands off the intended token to the registration pipeline,
+// unless the token is already in the ledger. Every outcome saved by the
+// pipeline deletes the intent, so each intent is handled exactly once
+func (r *Registrar) consume(intent *ledger.Token) {
+	existing, err := r.storage.GetToken(intent.PkgPath)
+
+	switch {
+	case err == nil && existing.Status != ledger.StatusTagged:
+		// The token was already handled, ex. before a crash
+		r.dropIntent(intent.PkgPath)
+
+		return
+	case err != nil && !errors.Is(err, storageErrors.ErrNotFound):
+		r.logger.Error("unable to fetch token", zap.String("pkgPath", intent.PkgPath), zap.Error(err))
+
+		return
+	}
+
+	r.handle(intent)
+}
+
+// dropIntent deletes the registration intent of the token
+func (r *Registrar) dropIntent(pkgPath string) {
+	wb := r.storage.WriteBatch()
+
+	if err := wb.DeleteIntent(pkgPath); err != nil {
+		r.logger.Error("unable to delete registration intent", zap.String("pkgPath", pkgPath), zap.Error(err))
+
+		_ = wb.Rollback()
+
+		return
+	}
+
+	if err := wb.Commit(); err != nil {
+		r.logger.Error("unable to commit registration intent", zap.String("pkgPath", pkgPath), zap.Error(err))
+	}
+}
+
+// markRegistering marks the token as registering, before its registration
+// tx is broadcast. It returns a flag indicating if the mark was saved
+func (r *Registrar) markRegistering(token *ledger.Token) bool {
+	token.Status = ledger.StatusRegistering
+	token.Error = ""
+
+	return r.save(token, nil)
+}
+
+// recoverRegistering resolves the registrations interrupted by a crash,
+// between the registration tx broadcast and the saved outcome. The tokens
+// are resolved by the hash of their signed tx, or verified on chain, and registered
+// again only if they are not registered. The tokens that are unable to be verified
+// are held for review
+func (r *Registrar) recoverRegistering() {
+	tokens, err := r.storage.GetTokens()
+	if err != nil {
+		r.logger.Error("unable to fetch tokens", zap.Error(err))
+
+		return
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	var (
+		checker, canCheck = r.registerer.(RegistrationChecker)
+		prepared, canSend = r.txRegisterer()
+	)
+
+	for _, token := range tokens {
+		if token.Status != ledger.StatusRegistering {
+			continue
+		}
+
+		// The signed tx of the token is resolved by its precomputed hash
+		if token.PendingTx != nil && canSend {
+			r.recoverPending(prepared, token)
+
+			continue
+		}
+
+		// Only the grc20 tokens registered by their package
+		// path are able to be verified on chain
+		if !canCheck || token.Kind == ledger.KindGRC721 || token.Key != "" {
+			token.Status = ledger.StatusNeedsReview
+			token.PolicyReason = "registration interrupted, the outcome is unable to be verified on chain"
+
+			r.logger.Warn("interrupted registration held for review", zap.String("pkgPath", token.PkgPath))
+
+			r.save(token, nil)
+
+			continue
+		}
+
+		registered, err := checker.IsRegistered(token.PkgPath)
+		if err != nil {
+			// The token is verified again on the next run
+			r.logger.Error(
+				"unable to verify interrupted registration",
+				zap.String("pkgPath", token.PkgPath),
+				zap.Error(err),
+			)
+
+			continue
+		}
+
+		if registered {
+			token.Status = ledger.StatusRegistered
+			token.Error = ""
+
+			r.logger.Info("interrupted registration verified on chain", zap.String("pkgPath", token.PkgPath))
+
+			r.save(token, nil)
+
+			continue
+		}
+
+		fee := r.registerer.EstimateFee()
+
+		if err := r.budget.Check(token.Deployer, fee.Amount, time.Now()); err != nil {
+			r.queue(token, err)
+
+			continue
+		}
+
+		r.submit(token)
+	}
+}
@@ -0,0 +1,148 @@
+package registrar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// writeTokens saves the tokens to the storage, as intents or ledger records
+func writeTokens(t *testing.T, s *storage.Pebble, intents bool, tokens ...*ledger.Token) {
+	t.Helper()
+
+	wb := s.WriteBatch()
+
+	for _, token := range tokens {
+		if intents {
+			require.NoError(t, wb.SetIntent(token))
+
+			continue
+		}
+
+		require.NoError(t, wb.SetToken(token))
+	}
+
+	require.NoError(t, wb.Commit())
+}
+
+func TestRegistrar_ProcessIntents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+	)
+
+	// The bar token was already handled before the restart
+	writeTokens(t, s, false, &ledger.Token{PkgPath: "gno.land/r/demo/bar", Status: ledger.StatusRegistered})
+	writeTokens(
+		t,
+		s,
+		true,
+		&ledger.Token{PkgPath: "gno.land/r/demo/foo", Height: 20},
+		&ledger.Token{PkgPath: "gno.land/r/demo/bar", Height: 10},
+		&ledger.Token{PkgPath: "gno.land/r/demo/baz", Height: 5},
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.processIntents()
+
+	// Make sure only the unhandled intents were registered, in detection order
+	assert.Equal(t, []string{"gno.land/r/demo/baz", "gno.land/r/demo/foo"}, registered)
+
+	for _, pkgPath := range registered {
+		token, err := s.GetToken(pkgPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, ledger.StatusRegistered, token.Status)
+	}
+
+	// Make sure every intent was consumed
+	intents, err := s.GetIntents()
+	require.NoError(t, err)
+
+	assert.Empty(t, intents)
+
+	// Make sure the intents are not registered again
+	r.processIntents()
+
+	assert.Len(t, registered, 2)
+}
+
+func TestRegistrar_RecoverRegistering(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+
+		registerer = &mockRegistrationChecker{
+			mockRegisterer: mockRegisterer{
+				registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+					registered = append(registered, pkgPath)
+
+					return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+				},
+			},
+			isRegisteredFn: func(pkgPath string) (bool, error) {
+				switch pkgPath {
+				case "gno.land/r/demo/foo":
+					return true, nil
+				case "gno.land/r/demo/flaky":
+					return false, errors.New("node unavailable")
+				default:
+					return false, nil
+				}
+			},
+		}
+	)
+
+	// The registrations were interrupted by a crash
+	writeTokens(
+		t,
+		s,
+		false,
+		&ledger.Token{PkgPath: "gno.land/r/demo/foo", Status: ledger.StatusRegistering},
+		&ledger.Token{PkgPath: "gno.land/r/demo/bar", Status: ledger.StatusRegistering},
+		&ledger.Token{PkgPath: "gno.land/r/demo/flaky", Status: ledger.StatusRegistering},
+		&ledger.Token{PkgPath: "gno.land/r/demo/nft", Kind: ledger.KindGRC721, Status: ledger.StatusRegistering},
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.recoverRegistering()
+
+	// Make sure only the token missing on chain was registered again
+	assert.Equal(t, []string{"gno.land/r/demo/bar"}, registered)
+
+	expected := map[string]ledger.Status{
+		"gno.land/r/demo/foo":   ledger.StatusRegistered,
+		"gno.land/r/demo/bar":   ledger.StatusRegistered,
+		"gno.land/r/demo/flaky": ledger.StatusRegistering,
+		"gno.land/r/demo/nft":   ledger.StatusNeedsReview,
+	}
+
+	for pkgPath, status := range expected {
+		token, err := s.GetToken(pkgPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, status, token.Status, pkgPath)
+	}
+}
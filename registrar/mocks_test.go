@@ -0,0 +1,243 @@
+package registrar
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+type (
+	estimateFeeDelegate         func() std.Coin
+	registerGrc20TokenDelegate  func(string) (*addpkg.Receipt, error)
+	registerGrc20TokensDelegate func([]string) (*addpkg.Receipt, error)
+)
+
+type mockRegisterer struct {
+	estimateFeeFn        estimateFeeDelegate
+	registerGrc20TokenFn registerGrc20TokenDelegate
+}
+
+func (m *mockRegisterer) EstimateFee() std.Coin {
+	if m.estimateFeeFn != nil {
+		return m.estimateFeeFn()
+	}
+
+	return std.Coin{}
+}
+
+func (m *mockRegisterer) RegisterGrc20Token(pkgPath string) (*addpkg.Receipt, error) {
+	if m.registerGrc20TokenFn != nil {
+		return m.registerGrc20TokenFn(pkgPath)
+	}
+
+	return nil, nil
+}
+
+type mockBatchRegisterer struct {
+	mockRegisterer
+
+	registerGrc20TokensFn registerGrc20TokensDelegate
+}
+
+func (m *mockBatchRegisterer) RegisterGrc20Tokens(pkgPaths []string) (*addpkg.Receipt, error) {
+	if m.registerGrc20TokensFn != nil {
+		return m.registerGrc20TokensFn(pkgPaths)
+	}
+
+	return nil, nil
+}
+
+type mockGrc721Registerer struct {
+	mockRegisterer
+
+	registerGrc721CollectionFn registerGrc20TokenDelegate
+}
+
+func (m *mockGrc721Registerer) RegisterGrc721Collection(pkgPath string) (*addpkg.Receipt, error) {
+	if m.registerGrc721CollectionFn != nil {
+		return m.registerGrc721CollectionFn(pkgPath)
+	}
+
+	return nil, nil
+}
+
+type setVersionDelegate func(string) error
+
+type mockVersionedRegisterer struct {
+	mockRegisterer
+
+	setVersionFn setVersionDelegate
+}
+
+func (m *mockVersionedRegisterer) SetVersion(version string) error {
+	if m.setVersionFn != nil {
+		return m.setVersionFn(version)
+	}
+
+	return nil
+}
+
+type signalEventDelegate func(events.Event)
+
+type mockEvents struct {
+	signalEventFn signalEventDelegate
+}
+
+func (m *mockEvents) SignalEvent(event events.Event) {
+	if m.signalEventFn != nil {
+		m.signalEventFn(event)
+	}
+}
+
+type evaluateDelegate func(*ledger.Token) policy.Result
+
+type mockPolicy struct {
+	evaluateFn evaluateDelegate
+}
+
+func (m *mockPolicy) Evaluate(token *ledger.Token) policy.Result {
+	if m.evaluateFn != nil {
+		return m.evaluateFn(token)
+	}
+
+	return policy.Result{Decision: policy.DecisionAllow}
+}
+
+type probeDelegate func(*ledger.Token) error
+
+type mockProber struct {
+	probeFn probeDelegate
+}
+
+func (m *mockProber) Probe(token *ledger.Token) error {
+	if m.probeFn != nil {
+		return m.probeFn(token)
+	}
+
+	return nil
+}
+
+type isRegisteredDelegate func(string) (bool, error)
+
+type mockRegistrationChecker struct {
+	mockRegisterer
+
+	isRegisteredFn isRegisteredDelegate
+}
+
+func (m *mockRegistrationChecker) IsRegistered(pkgPath string) (bool, error) {
+	if m.isRegisteredFn != nil {
+		return m.isRegisteredFn(pkgPath)
+	}
+
+	return false, nil
+}
+
+type (
+	prepareDelegate   func(ledger.Kind, ...*ledger.Token) (*addpkg.SignedTx, error)
+	broadcastDelegate func(*addpkg.SignedTx) (*addpkg.Receipt, error)
+	lookupTxDelegate  func(string) (*addpkg.Receipt, error)
+)
+
+type mockTxRegisterer struct {
+	mockRegisterer
+
+	prepareFn   prepareDelegate
+	broadcastFn broadcastDelegate
+	lookupTxFn  lookupTxDelegate
+}
+
+func (m *mockTxRegisterer) Prepare(kind ledger.Kind, tokens ...*ledger.Token) (*addpkg.SignedTx, error) {
+	if m.prepareFn != nil {
+		return m.prepareFn(kind, tokens...)
+	}
+
+	return nil, nil
+}
+
+func (m *mockTxRegisterer) Broadcast(signed *addpkg.SignedTx) (*addpkg.Receipt, error) {
+	if m.broadcastFn != nil {
+		return m.broadcastFn(signed)
+	}
+
+	return nil, nil
+}
+
+func (m *mockTxRegisterer) LookupTx(txHash string) (*addpkg.Receipt, error) {
+	if m.lookupTxFn != nil {
+		return m.lookupTxFn(txHash)
+	}
+
+	return nil, addpkg.ErrTxNotFound
+}
+
+type mockCheckedTxRegisterer struct {
+	mockTxRegisterer
+
+	isRegisteredFn        isRegisteredDelegate
+	registerGrc20TokensFn registerGrc20TokensDelegate
+}
+
+func (m *mockCheckedTxRegisterer) IsRegistered(pkgPath string) (bool, error) {
+	if m.isRegisteredFn != nil {
+		return m.isRegisteredFn(pkgPath)
+	}
+
+	return false, nil
+}
+
+func (m *mockCheckedTxRegisterer) RegisterGrc20Tokens(pkgPaths []string) (*addpkg.Receipt, error) {
+	if m.registerGrc20TokensFn != nil {
+		return m.registerGrc20TokensFn(pkgPaths)
+	}
+
+	return nil, nil
+}
+
+type validateDelegate func() error
+
+type mockFence struct {
+	validateFn validateDelegate
+}
+
+func (m *mockFence) Validate() error {
+	if m.validateFn != nil {
+		return m.validateFn()
+	}
+
+	return nil
+}
+
+type enqueueDelegate func(storage.Batch, *ledger.Notification) error
+
+type mockOutbox struct {
+	enqueueFn enqueueDelegate
+}
+
+func (m *mockOutbox) Enqueue(wb storage.Batch, notification *ledger.Notification) error {
+	if m.enqueueFn != nil {
+		return m.enqueueFn(wb, notification)
+	}
+
+	return nil
+}
+
+type signerBalanceDelegate func() (*ledger.Balance, error)
+
+type mockBalanceRegisterer struct {
+	mockRegisterer
+
+	signerBalanceFn signerBalanceDelegate
+}
+
+func (m *mockBalanceRegisterer) SignerBalance() (*ledger.Balance, error) {
+	if m.signerBalanceFn != nil {
+		return m.signerBalanceFn()
+	}
+
+	return &ledger.Balance{}, nil
+}
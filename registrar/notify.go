@@ -0,0 +1,122 @@
+package registrar
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// notify writes the lifecycle event of the saved token to the outbox,
+// in the batch saving it. A failed notification never fails the save
+func (r *Registrar) notify(wb storage.Batch, token *ledger.Token) {
+	if r.outbox == nil {
+		return
+	}
+
+	event, ok := r.event(token)
+	if !ok {
+		return
+	}
+
+	notification := &ledger.Notification{
+		Event: event,
+		Time:  time.Now(),
+		Token: token,
+	}
+
+	if err := r.outbox.Enqueue(wb, notification); err != nil {
+		r.logger.Error(
+			"unable to enqueue token notification",
+			zap.String("pkgPath", token.PkgPath),
+			zap.String("event", string(event)),
+			zap.Error(err),
+		)
+	}
+}
+
+// event returns the lifecycle event of the token status, if any
+func (r *Registrar) event(token *ledger.Token) (ledger.Event, bool) {
+	switch token.Status {
+	case ledger.StatusRegistering:
+		// The signed tx is persisted right after, so the
+		// registration is notified once its hash is known
+		if _, ok := r.txRegisterer(); ok && token.PendingTx == nil {
+			return "", false
+		}
+
+		return ledger.EventSubmitted, true
+	case ledger.StatusRegistered:
+		return ledger.EventConfirmed, true
+	case ledger.StatusFailed,
+		ledger.StatusSimulationFailed,
+		ledger.StatusProbeFailed,
+		ledger.StatusTemplateError:
+		return ledger.EventFailed, true
+	case ledger.StatusNeedsReview:
+		return ledger.EventNeedsReview, true
+	default:
+		return "", false
+	}
+}
+
+// checkBalance notifies the signer balance once it drops below
+// the threshold. It is notified again only after it is topped up
+func (r *Registrar) checkBalance() {
+	checker, ok := r.registerer.(BalanceChecker)
+	if !ok || r.outbox == nil || r.lowBalance <= 0 {
+		return
+	}
+
+	balance, err := checker.SignerBalance()
+	if err != nil {
+		r.logger.Warn("unable to fetch signer balance", zap.Error(err))
+
+		return
+	}
+
+	if balance.Amount >= r.lowBalance {
+		r.belowLimit.Store(false)
+
+		return
+	}
+
+	if r.belowLimit.Load() {
+		return
+	}
+
+	balance.Threshold = r.lowBalance
+
+	r.logger.Warn(
+		"signer balance below threshold",
+		zap.String("signer", balance.Signer),
+		zap.Int64("amount", balance.Amount),
+		zap.Int64("threshold", balance.Threshold),
+	)
+
+	wb := r.storage.WriteBatch()
+
+	notification := &ledger.Notification{
+		Event:   ledger.EventLowBalance,
+		Time:    time.Now(),
+		Balance: balance,
+	}
+
+	if err := r.outbox.Enqueue(wb, notification); err != nil {
+		r.logger.Error("unable to enqueue balance notification", zap.Error(err))
+
+		_ = wb.Rollback()
+
+		return
+	}
+
+	if err := wb.Commit(); err != nil {
+		r.logger.Error("unable to commit balance notification", zap.Error(err))
+
+		return
+	}
+
+	r.belowLimit.Store(true)
+}
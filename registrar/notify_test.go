@@ -0,0 +1,190 @@
+package registrar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTestOutbox creates an outbox that writes
+// the notifications to the batch, with sequential IDs
+func newTestOutbox() *mockOutbox {
+	var id uint64
+
+	return &mockOutbox{
+		enqueueFn: func(wb storage.Batch, notification *ledger.Notification) error {
+			id++
+
+			notification.ID = id
+
+			return wb.SetNotification(notification)
+		},
+	}
+}
+
+func TestRegistrar_Notify(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name       string
+		registerer Registerer
+		events     []ledger.Event
+		txHashes   []string
+	}{
+		{
+			"registration confirmed",
+			&mockRegisterer{
+				registerGrc20TokenFn: func(_ string) (*addpkg.Receipt, error) {
+					return &addpkg.Receipt{TxHash: "hash"}, nil
+				},
+			},
+			[]ledger.Event{ledger.EventSubmitted, ledger.EventConfirmed},
+			[]string{"", "hash"},
+		},
+		{
+			"registration failed",
+			&mockRegisterer{
+				registerGrc20TokenFn: func(_ string) (*addpkg.Receipt, error) {
+					return nil, errors.New("broadcast failed")
+				},
+			},
+			[]ledger.Event{ledger.EventSubmitted, ledger.EventFailed},
+			[]string{"", ""},
+		},
+		{
+			"signed registration confirmed",
+			&mockTxRegisterer{
+				prepareFn: func(_ ledger.Kind, tokens ...*ledger.Token) (*addpkg.SignedTx, error) {
+					return newSignedTx(tokens[0].PkgPath), nil
+				},
+				broadcastFn: func(signed *addpkg.SignedTx) (*addpkg.Receipt, error) {
+					return &addpkg.Receipt{TxHash: signed.Hash, Fee: signed.Fee}, nil
+				},
+			},
+			[]ledger.Event{ledger.EventSubmitted, ledger.EventConfirmed},
+			[]string{
+				newSignedTx("gno.land/r/demo/foo").Hash,
+				newSignedTx("gno.land/r/demo/foo").Hash,
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestStorage(t)
+
+			r := New(s, testCase.registerer, &mockEvents{}, WithOutbox(newTestOutbox()))
+
+			r.Register(&ledger.Token{
+				PkgPath:      "gno.land/r/demo/foo",
+				DeployTxHash: "deploy",
+			})
+
+			// Make sure the lifecycle events were committed,
+			// with the token tx hashes
+			notifications, err := s.GetNotifications()
+			require.NoError(t, err)
+
+			require.Len(t, notifications, len(testCase.events))
+
+			for i, notification := range notifications {
+				assert.Equal(t, testCase.events[i], notification.Event)
+
+				require.NotNil(t, notification.Token)
+				assert.Equal(t, "deploy", notification.Token.DeployTxHash)
+				assert.Equal(t, testCase.txHashes[i], notification.Token.RegisterTxHash)
+			}
+		})
+	}
+}
+
+func TestRegistrar_Notify_Unsaved(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		outbox = &mockOutbox{
+			enqueueFn: func(_ storage.Batch, _ *ledger.Notification) error {
+				return errors.New("outbox failed")
+			},
+		}
+	)
+
+	r := New(s, &mockRegisterer{}, &mockEvents{}, WithOutbox(outbox))
+
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+
+	// Make sure the failed notification did not fail the save
+	token, err := s.GetToken("gno.land/r/demo/foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, token.Status)
+}
+
+func TestRegistrar_CheckBalance(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		amount     = int64(500)
+		registerer = &mockBalanceRegisterer{
+			signerBalanceFn: func() (*ledger.Balance, error) {
+				return &ledger.Balance{
+					Signer: "g1signer",
+					Denom:  "ugnot",
+					Amount: amount,
+				}, nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithOutbox(newTestOutbox()), WithLowBalance(1_000))
+
+	lowBalances := func() []*ledger.Notification {
+		notifications, err := s.GetNotifications()
+		require.NoError(t, err)
+
+		return notifications
+	}
+
+	// Make sure the low balance is notified once
+	r.processQueue()
+	r.processQueue()
+
+	notifications := lowBalances()
+	require.Len(t, notifications, 1)
+
+	assert.Equal(t, ledger.EventLowBalance, notifications[0].Event)
+	assert.Equal(t, &ledger.Balance{
+		Signer:    "g1signer",
+		Denom:     "ugnot",
+		Amount:    500,
+		Threshold: 1_000,
+	}, notifications[0].Balance)
+
+	// Make sure it is notified again only after it is topped up
+	amount = 2_000
+
+	r.processQueue()
+
+	amount = 100
+
+	r.processQueue()
+
+	notifications = lowBalances()
+	require.Len(t, notifications, 2)
+
+	assert.Equal(t, int64(100), notifications[1].Balance.Amount)
+}
@@ -0,0 +1,119 @@
+package registrar
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/budget"
+	"github.com/gnolang/tx-indexer/detector"
+)
+
+type Option func(r *Registrar)
+
+// WithLogger sets the logger to be used
+// with the registrar
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Registrar) {
+		r.logger = logger
+	}
+}
+
+// WithBudget sets the fee budget tracker
+// for the registrar
+func WithBudget(tracker *budget.Tracker) Option {
+	return func(r *Registrar) {
+		r.budget = tracker
+	}
+}
+
+// WithQueueInterval sets the interval at which
+// the registrar retries the queued registrations
+func WithQueueInterval(interval time.Duration) Option {
+	return func(r *Registrar) {
+		r.queueInterval = interval
+	}
+}
+
+// WithDrainTimeout sets the time the in-flight registration
+// is drained for on shutdown, without a limit if not positive
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(r *Registrar) {
+		r.drainTimeout = timeout
+	}
+}
+
+// WithPolicy sets the registration policy
+// for the registrar
+func WithPolicy(p Policy) Option {
+	return func(r *Registrar) {
+		r.policy = p
+	}
+}
+
+// WithImpersonationChecker sets the impersonation checker
+// for the registrar
+func WithImpersonationChecker(checker *detector.ImpersonationChecker) Option {
+	return func(r *Registrar) {
+		r.impersonation = checker
+	}
+}
+
+// WithProber sets the token behavioral prober
+// for the registrar
+func WithProber(p Prober) Option {
+	return func(r *Registrar) {
+		r.prober = p
+	}
+}
+
+// WithBatchWindow sets the window in which the detected tokens
+// are coalesced into a single registration tx. Batching requires
+// a registerer that implements BatchRegisterer
+func WithBatchWindow(window time.Duration) Option {
+	return func(r *Registrar) {
+		r.batchWindow = window
+	}
+}
+
+// WithBatchSize sets the maximum number of tokens
+// registered in a single batch tx
+func WithBatchSize(size int) Option {
+	return func(r *Registrar) {
+		r.batchSize = size
+	}
+}
+
+// WithUpgrades sets the registry realms, and the registry
+// versions their deployment activates
+func WithUpgrades(upgrades map[string]string) Option {
+	return func(r *Registrar) {
+		r.upgrades = upgrades
+	}
+}
+
+// WithFence sets the single-writer lease, validated before
+// every registration tx broadcast, so a stale leader never
+// registers the tokens taken over by the standby
+func WithFence(f Fence) Option {
+	return func(r *Registrar) {
+		r.fence = f
+	}
+}
+
+// WithOutbox sets the lifecycle webhook outbox, notified
+// of the registration outcomes in the batch saving them
+func WithOutbox(outbox Outbox) Option {
+	return func(r *Registrar) {
+		r.outbox = outbox
+	}
+}
+
+// WithLowBalance sets the signer balance threshold, below which
+// the low balance is notified. Checking the balance requires
+// a registerer that implements BalanceChecker
+func WithLowBalance(threshold int64) Option {
+	return func(r *Registrar) {
+		r.lowBalance = threshold
+	}
+}
@@ -0,0 +1,194 @@
+package registrar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+var errPendingUnsaved = errors.New("unable to persist the signed registration tx")
+
+// txRegisterer returns the registration tx broadcaster
+// that signs the registration txs ahead of their broadcast, if any
+func (r *Registrar) txRegisterer() (TxRegisterer, bool) {
+	registerer, ok := r.registerer.(TxRegisterer)
+
+	return registerer, ok
+}
+
+// unregistered checks on chain that the token is not registered yet,
+// before its registration tx is signed. The tokens already registered,
+// ex. imported from genesis, or queued again by a registry upgrade, are
+// saved as registered without a tx, and the tokens that are unable to be
+// checked are failed. Only the grc20 tokens registered by their package
+// path are able to be checked
+func (r *Registrar) unregistered(token *ledger.Token) bool {
+	checker, ok := r.registerer.(RegistrationChecker)
+	if !ok || token.Kind == ledger.KindGRC721 || token.Key != "" {
+		return true
+	}
+
+	registered, err := checker.IsRegistered(token.PkgPath)
+	if err == nil && !registered {
+		return true
+	}
+
+	if registered {
+		token.Version = r.version
+		err = fmt.Errorf("%w: %s", addpkg.ErrTokenAlreadyRegistered, token.PkgPath)
+	}
+
+	r.setOutcome(token, err)
+	r.save(token, nil)
+
+	return false
+}
+
+// sendPrepared signs the registration tx of the tokens, persists its hash
+// as the pending tx of every token, and only then broadcasts it. If the
+// broadcast outcome is unknown, the tx is looked up by its hash
+func (r *Registrar) sendPrepared(
+	registerer TxRegisterer,
+	kind ledger.Kind,
+	tokens ...*ledger.Token,
+) (*addpkg.Receipt, error) {
+	signed, err := registerer.Prepare(kind, tokens...)
+	if err != nil {
+		return nil, err
+	}
+
+	share := signed.Fee.Amount / int64(len(tokens))
+
+	for i, token := range tokens {
+		token.RegisterTxHash = signed.Hash
+		token.PendingTx = &ledger.PendingTx{
+			Hash:   signed.Hash,
+			Raw:    signed.Raw,
+			Denom:  signed.Fee.Denom,
+			Amount: signed.Fee.Amount,
+			Share:  share,
+		}
+
+		// The remainder is attributed to the first token
+		if i == 0 {
+			token.PendingTx.Share += signed.Fee.Amount % int64(len(tokens))
+		}
+
+		if !r.markRegistering(token) {
+			return nil, errPendingUnsaved
+		}
+	}
+
+	receipt, err := registerer.Broadcast(signed)
+	if !errors.Is(err, addpkg.ErrBroadcastUncertain) {
+		return receipt, err
+	}
+
+	r.logger.Warn(
+		"registration tx outcome unknown, looking up its hash",
+		zap.String("txHash", signed.Hash),
+		zap.Error(err),
+	)
+
+	return r.resolvePending(registerer, tokens[0].PendingTx)
+}
+
+// resolvePending resolves the outcome of the pending tx by its precomputed
+// hash, in the indexed txs, and on the chain. The tx is broadcast again,
+// with the same sequence, only if it was not committed, so it is never paid twice
+func (r *Registrar) resolvePending(registerer TxRegisterer, pending *ledger.PendingTx) (*addpkg.Receipt, error) {
+	if result, err := r.storage.GetTxByHash(pending.Hash); err == nil {
+		return indexedReceipt(pending, result)
+	}
+
+	receipt, err := registerer.LookupTx(pending.Hash)
+	if !errors.Is(err, addpkg.ErrTxNotFound) {
+		return receipt, err
+	}
+
+	// The tx is broadcast again only by the running lease holder,
+	// and resolved once it is allowed to be broadcast otherwise
+	if err := r.halted(); err != nil {
+		return nil, fmt.Errorf("%w, %w", addpkg.ErrBroadcastUncertain, err)
+	}
+
+	signed := &addpkg.SignedTx{
+		Hash: pending.Hash,
+		Raw:  pending.Raw,
+		Fee:  std.NewCoin(pending.Denom, pending.Amount),
+	}
+
+	r.logger.Info("registration tx not committed, broadcasting it again", zap.String("txHash", pending.Hash))
+
+	receipt, err = registerer.Broadcast(signed)
+	if err == nil || receipt != nil || errors.Is(err, addpkg.ErrBroadcastUncertain) {
+		return receipt, err
+	}
+
+	// The broadcast is rejected if the tx was committed
+	// in the meantime, since its sequence is already used
+	committed, lookupErr := registerer.LookupTx(pending.Hash)
+	if errors.Is(lookupErr, addpkg.ErrTxNotFound) {
+		return nil, err
+	}
+
+	return committed, lookupErr
+}
+
+// recoverPending resolves the outcome of the interrupted registration
+// by the precomputed hash of its pending tx, and saves it to the ledger
+func (r *Registrar) recoverPending(registerer TxRegisterer, token *ledger.Token) {
+	pending := token.PendingTx
+
+	receipt, err := r.resolvePending(registerer, pending)
+	if errors.Is(err, addpkg.ErrBroadcastUncertain) {
+		// The token is resolved again on the next run
+		r.logger.Warn("registration outcome still unknown", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+		return
+	}
+
+	r.setOutcome(token, err)
+
+	var spend *ledger.Spend
+
+	// The fee is paid for every committed tx
+	if receipt != nil {
+		token.RegisterTxHash = receipt.TxHash
+
+		spend = &ledger.Spend{
+			Time:     time.Now(),
+			PkgPath:  token.PkgPath,
+			Deployer: token.Deployer,
+			TxHash:   receipt.TxHash,
+			Denom:    pending.Denom,
+			Amount:   pending.Share,
+		}
+
+		r.budget.Record(spend)
+	}
+
+	r.save(token, spend)
+}
+
+// indexedReceipt creates the receipt of the pending tx, from its indexed result
+func indexedReceipt(pending *ledger.PendingTx, result *types.TxResult) (*addpkg.Receipt, error) {
+	receipt := &addpkg.Receipt{
+		TxHash: pending.Hash,
+		Fee:    std.NewCoin(pending.Denom, pending.Amount),
+		Height: result.Height,
+	}
+
+	if result.Response.IsErr() {
+		return receipt, fmt.Errorf("transaction failed during execution, %w", result.Response.Error)
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,265 @@
+package registrar
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// newSignedTx creates a signed tx, with a hash derived from the package paths
+func newSignedTx(pkgPaths ...string) *addpkg.SignedTx {
+	return &addpkg.SignedTx{
+		Hash: fmt.Sprintf("hash %v", pkgPaths),
+		Raw:  []byte(fmt.Sprintf("tx %v", pkgPaths)),
+		Fee:  std.NewCoin("ugnot", 1_000_000),
+	}
+}
+
+// uncertainErr is the error of a timed out broadcast
+var uncertainErr = fmt.Errorf("%w, timed out", addpkg.ErrBroadcastUncertain)
+
+func TestRegistrar_Register_UncertainBroadcast(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name       string
+		committed  bool
+		broadcasts int
+	}{
+		{
+			"tx committed",
+			true,
+			1,
+		},
+		{
+			"tx not committed",
+			false,
+			2,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				s = newTestStorage(t)
+
+				pkgPath    = "gno.land/r/demo/foo"
+				broadcasts = make([]*addpkg.SignedTx, 0)
+
+				registerer = &mockTxRegisterer{
+					prepareFn: func(_ ledger.Kind, tokens ...*ledger.Token) (*addpkg.SignedTx, error) {
+						return newSignedTx(tokens[0].PkgPath), nil
+					},
+					broadcastFn: func(signed *addpkg.SignedTx) (*addpkg.Receipt, error) {
+						broadcasts = append(broadcasts, signed)
+
+						// The first broadcast times out
+						if len(broadcasts) == 1 {
+							return nil, uncertainErr
+						}
+
+						return &addpkg.Receipt{TxHash: signed.Hash, Fee: signed.Fee}, nil
+					},
+					lookupTxFn: func(txHash string) (*addpkg.Receipt, error) {
+						if !testCase.committed {
+							return nil, addpkg.ErrTxNotFound
+						}
+
+						return &addpkg.Receipt{TxHash: txHash, Fee: std.NewCoin("ugnot", 1_000_000)}, nil
+					},
+				}
+			)
+
+			r := New(s, registerer, &mockEvents{})
+
+			r.Register(&ledger.Token{PkgPath: pkgPath})
+
+			// Make sure the tx was only broadcast again if it was not committed,
+			// as the same signed tx
+			require.Len(t, broadcasts, testCase.broadcasts)
+
+			for _, signed := range broadcasts {
+				assert.Equal(t, newSignedTx(pkgPath), signed)
+			}
+
+			token, err := s.GetToken(pkgPath)
+			require.NoError(t, err)
+
+			assert.Equal(t, ledger.StatusRegistered, token.Status)
+			assert.Equal(t, newSignedTx(pkgPath).Hash, token.RegisterTxHash)
+			assert.Nil(t, token.PendingTx)
+
+			// Make sure the fee was recorded once
+			spends, err := s.GetSpends()
+			require.NoError(t, err)
+
+			assert.Len(t, spends, 1)
+		})
+	}
+}
+
+func TestRegistrar_RecoverRegistering_PendingTx(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		pkgPath   = "gno.land/r/demo/foo"
+		committed = false
+
+		broadcasts = 0
+
+		registerer = &mockTxRegisterer{
+			prepareFn: func(_ ledger.Kind, tokens ...*ledger.Token) (*addpkg.SignedTx, error) {
+				return newSignedTx(tokens[0].PkgPath), nil
+			},
+			broadcastFn: func(_ *addpkg.SignedTx) (*addpkg.Receipt, error) {
+				broadcasts++
+
+				return nil, uncertainErr
+			},
+			lookupTxFn: func(txHash string) (*addpkg.Receipt, error) {
+				if !committed {
+					return nil, addpkg.ErrTxNotFound
+				}
+
+				return &addpkg.Receipt{TxHash: txHash, Height: 10}, nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.Register(&ledger.Token{PkgPath: pkgPath})
+
+	// Make sure the token is left registering,
+	// with its signed tx persisted
+	token, err := s.GetToken(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistering, token.Status)
+	require.NotNil(t, token.PendingTx)
+	assert.Equal(t, newSignedTx(pkgPath).Hash, token.PendingTx.Hash)
+	assert.Equal(t, 2, broadcasts)
+
+	// The tx was committed after all
+	committed = true
+
+	r.recoverRegistering()
+
+	// Make sure the token was resolved by its hash, without a broadcast
+	token, err = s.GetToken(pkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, token.Status)
+	assert.Nil(t, token.PendingTx)
+	assert.Equal(t, 2, broadcasts)
+
+	spends, err := s.GetSpends()
+	require.NoError(t, err)
+
+	require.Len(t, spends, 1)
+	assert.Equal(t, int64(1_000_000), spends[0].Amount)
+}
+
+func TestRegistrar_Register_AlreadyRegistered(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		tokens []*ledger.Token
+		opts   []Option
+	}{
+		{
+			"single token",
+			[]*ledger.Token{
+				{PkgPath: "gno.land/r/demo/registered"},
+				{PkgPath: "gno.land/r/demo/foo"},
+			},
+			nil,
+		},
+		{
+			"batch",
+			[]*ledger.Token{
+				{PkgPath: "gno.land/r/demo/registered"},
+				{PkgPath: "gno.land/r/demo/foo"},
+			},
+			[]Option{WithBatchWindow(time.Hour), WithBatchSize(2)},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				s = newTestStorage(t)
+
+				prepared   = make([]string, 0)
+				registerer = &mockCheckedTxRegisterer{
+					mockTxRegisterer: mockTxRegisterer{
+						prepareFn: func(_ ledger.Kind, tokens ...*ledger.Token) (*addpkg.SignedTx, error) {
+							pkgPaths := make([]string, 0, len(tokens))
+
+							for _, token := range tokens {
+								pkgPaths = append(pkgPaths, token.PkgPath)
+							}
+
+							prepared = append(prepared, pkgPaths...)
+
+							return newSignedTx(pkgPaths...), nil
+						},
+						broadcastFn: func(signed *addpkg.SignedTx) (*addpkg.Receipt, error) {
+							return &addpkg.Receipt{TxHash: signed.Hash, Fee: signed.Fee}, nil
+						},
+					},
+					isRegisteredFn: func(pkgPath string) (bool, error) {
+						return pkgPath == "gno.land/r/demo/registered", nil
+					},
+				}
+			)
+
+			r := New(s, registerer, &mockEvents{}, testCase.opts...)
+
+			for _, token := range testCase.tokens {
+				r.Register(token)
+			}
+
+			// Make sure only the unregistered token was signed
+			assert.Equal(t, []string{"gno.land/r/demo/foo"}, prepared)
+
+			registered, err := s.GetToken("gno.land/r/demo/registered")
+			require.NoError(t, err)
+
+			assert.Equal(t, ledger.StatusRegistered, registered.Status)
+			assert.Empty(t, registered.RegisterTxHash)
+			assert.Nil(t, registered.PendingTx)
+
+			foo, err := s.GetToken("gno.land/r/demo/foo")
+			require.NoError(t, err)
+
+			assert.Equal(t, ledger.StatusRegistered, foo.Status)
+			assert.Equal(t, newSignedTx("gno.land/r/demo/foo").Hash, foo.RegisterTxHash)
+
+			// Make sure no fee was paid for the registered token
+			spends, err := s.GetSpends()
+			require.NoError(t, err)
+
+			require.Len(t, spends, 1)
+			assert.Equal(t, "gno.land/r/demo/foo", spends[0].PkgPath)
+		})
+	}
+}
@@ -0,0 +1,519 @@
+package registrar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/budget"
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+	"github.com/gnolang/tx-indexer/storage"
+	"github.com/gnolang/tx-indexer/types"
+)
+
+const (
+	DefaultQueueInterval = 1 * time.Minute
+
+	// DefaultDrainTimeout is the default time the
+	// in-flight registration is drained for, on shutdown
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+var errGrc721Unsupported = errors.New("grc721 registrations are not supported by the registerer")
+
+// Registrar is the token registration service. It registers the
+// detected tokens allowed by the policy within the fee budget,
+// queueing the rest
+type Registrar struct {
+	storage    storage.Storage
+	registerer Registerer
+	events     Events
+	policy     Policy
+	prober     Prober
+	fence      Fence
+	outbox     Outbox
+
+	impersonation *detector.ImpersonationChecker
+
+	budget *budget.Tracker
+	logger *zap.Logger
+
+	lowBalance int64       // the signer balance threshold, if any
+	belowLimit atomic.Bool // flag indicating if the low balance was notified
+
+	queueInterval time.Duration
+	drainTimeout  time.Duration
+
+	batchWindow time.Duration
+	batchSize   int
+	pending     batch
+
+	version  string            // the active registry version
+	upgrades map[string]string // the registry realms, and the versions they activate
+
+	intents chan struct{} // the committed registration intents signal
+	resumed chan struct{} // the resumed registrations signal
+
+	paused   atomic.Bool                     // flag indicating if the registrations are paused
+	serveCtx atomic.Pointer[context.Context] // the service context, cancelled on shutdown
+
+	// mux serializes the registrations,
+	// since they share the signer account
+	mux sync.Mutex
+}
+
+// New creates a new registrar instance
+func New(
+	storage storage.Storage,
+	registerer Registerer,
+	events Events,
+	opts ...Option,
+) *Registrar {
+	r := &Registrar{
+		storage:       storage,
+		registerer:    registerer,
+		events:        events,
+		impersonation: detector.NewImpersonationChecker(nil),
+		budget:        budget.NewTracker(budget.Config{}, nil),
+		logger:        zap.NewNop(),
+		queueInterval: DefaultQueueInterval,
+		drainTimeout:  DefaultDrainTimeout,
+		batchSize:     DefaultBatchSize,
+		intents:       make(chan struct{}, 1),
+		resumed:       make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.loadVersion()
+
+	return r
+}
+
+// Register registers the detected token, if the registration policy
+// and the fee budget allow it. Otherwise, the token registration
+// is rejected, held for review or queued
+func (r *Registrar) Register(token *ledger.Token) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.handle(token)
+}
+
+// handle runs the detected token through the registration pipeline
+func (r *Registrar) handle(token *ledger.Token) {
+	token.Version = r.version
+
+	// The token might have been registered by the previous lease holder
+	if r.linked(token) {
+		return
+	}
+
+	if !r.evaluate(token) {
+		return
+	}
+
+	fee := r.registerer.EstimateFee()
+
+	if err := r.budget.Check(token.Deployer, fee.Amount, time.Now()); err != nil {
+		r.queue(token, err)
+
+		return
+	}
+
+	// Coalesce the registrations within the batch window, if configured.
+	// Only the grc20 tokens are registered in batches
+	if _, ok := r.batchRegisterer(); ok && token.Kind != ledger.KindGRC721 {
+		r.addToBatch(token)
+
+		return
+	}
+
+	r.register(token)
+}
+
+// Serve starts the registrar queue processing, until the context
+// is cancelled. Once cancelled, no new registrations are started, and
+// the service returns after the in-flight registration is drained
+func (r *Registrar) Serve(ctx context.Context) error {
+	ticker := time.NewTicker(r.queueInterval)
+	defer ticker.Stop()
+
+	// The service is started again by the lease elector,
+	// after the lease is lost and acquired again
+	r.serveCtx.Store(&ctx)
+
+	// Resolve the registrations interrupted by a crash, and
+	// consume the intents committed before the restart, if any
+	r.recoverRegistering()
+	r.processIntents()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.drain(); err != nil {
+				return err
+			}
+
+			r.logger.Info("Registrar service shut down")
+
+			return nil
+		case <-ticker.C:
+			r.processQueue()
+		case <-r.intents:
+			r.processIntents()
+		case <-r.resumed:
+			r.processIntents()
+			r.processQueue()
+		}
+	}
+}
+
+// processQueue registers the queued tokens
+// that fit into the fee budget
+func (r *Registrar) processQueue() {
+	if r.stopped() {
+		return
+	}
+
+	r.recoverRegistering()
+	r.checkBalance()
+
+	tokens, err := r.storage.GetTokens()
+	if err != nil {
+		r.logger.Error("unable to fetch tokens", zap.Error(err))
+
+		return
+	}
+
+	queued := make([]*ledger.Token, 0)
+
+	r.mux.Lock()
+
+	for _, token := range tokens {
+		switch {
+		case token.Status == ledger.StatusQueued:
+			queued = append(queued, token)
+		case token.Status == ledger.StatusBatched && !r.pending.contains(token.PkgPath):
+			// The batch was never registered, ex. because of a restart
+			queued = append(queued, token)
+		}
+	}
+
+	r.mux.Unlock()
+
+	// Register the tokens in the order they were detected
+	sort.SliceStable(queued, func(i, j int) bool {
+		return queued[i].Height < queued[j].Height
+	})
+
+	for _, token := range queued {
+		// The rest of the queue is registered once resumed, or on the next start
+		if r.stopped() {
+			return
+		}
+
+		r.mux.Lock()
+
+		if r.linked(token) {
+			r.mux.Unlock()
+
+			continue
+		}
+
+		fee := r.registerer.EstimateFee()
+
+		// The token stays queued until
+		// its registration fits into the budget
+		if err := r.budget.Check(token.Deployer, fee.Amount, time.Now()); err == nil {
+			r.register(token)
+		}
+
+		r.mux.Unlock()
+	}
+}
+
+// evaluate evaluates the registration policy and the impersonation check
+// for the token, and saves the token if it is not allowed to be registered
+func (r *Registrar) evaluate(token *ledger.Token) bool {
+	result := Evaluate(r.policy, r.impersonation, token, r.catalog)
+
+	token.PolicyReason = result.Reason
+
+	switch result.Decision {
+	case policy.DecisionAllow:
+		return true
+	case policy.DecisionReview:
+		token.Status = ledger.StatusNeedsReview
+	default:
+		token.Status = ledger.StatusRejected
+	}
+
+	r.logger.Info(
+		"grc20 token registration not allowed by policy",
+		zap.String("pkgPath", token.PkgPath),
+		zap.String("status", string(token.Status)),
+		zap.String("reason", result.Reason),
+	)
+
+	r.save(token, nil)
+
+	return false
+}
+
+// catalog returns the registered tokens,
+// the detected tokens are compared against
+func (r *Registrar) catalog() ([]*ledger.Token, error) {
+	tokens, err := r.storage.GetTokens()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch token catalog, %w", err)
+	}
+
+	catalog := make([]*ledger.Token, 0, len(tokens))
+
+	for _, t := range tokens {
+		if t.Status == ledger.StatusRegistered {
+			catalog = append(catalog, t)
+		}
+	}
+
+	return catalog, nil
+}
+
+// queue marks the token registration as queued,
+// and emits the budget alert
+func (r *Registrar) queue(token *ledger.Token, err error) {
+	token.Status = ledger.StatusQueued
+	token.Error = err.Error()
+
+	r.logger.Warn(
+		"grc20 token registration queued",
+		zap.String("pkgPath", token.PkgPath),
+		zap.Error(err),
+	)
+
+	var exceededErr *budget.ExceededError
+	if errors.As(err, &exceededErr) {
+		r.events.SignalEvent(&types.BudgetExceeded{
+			Token:  token,
+			Budget: exceededErr.Budget,
+			Limit:  exceededErr.Limit,
+			Spent:  exceededErr.Spent,
+			Amount: exceededErr.Amount,
+		})
+	}
+
+	r.save(token, nil)
+}
+
+// register registers the token, and saves the
+// outcome and the paid fee to the ledger
+func (r *Registrar) register(token *ledger.Token) {
+	if !r.probe(token) {
+		return
+	}
+
+	r.submit(token)
+}
+
+// probe validates the token behavior before paying for the
+// registration, and saves the token if it failed the probes
+func (r *Registrar) probe(token *ledger.Token) bool {
+	// The behavioral probes are specific to the grc20 tokens
+	if r.prober == nil || token.Kind == ledger.KindGRC721 {
+		return true
+	}
+
+	if err := r.prober.Probe(token); err != nil {
+		token.Status = ledger.StatusProbeFailed
+		token.Error = err.Error()
+
+		r.logger.Error(
+			"grc20 token failed behavioral probes",
+			zap.String("pkgPath", token.PkgPath),
+			zap.Error(err),
+		)
+
+		r.save(token, nil)
+
+		return false
+	}
+
+	return true
+}
+
+// submit broadcasts the registration tx of the probed token,
+// and saves the outcome and the paid fee to the ledger
+func (r *Registrar) submit(token *ledger.Token) {
+	var spend *ledger.Spend
+
+	token.Version = r.version
+
+	// The token is only registered by the running lease holder,
+	// and stays queued until it is allowed to be registered
+	if err := r.halted(); err != nil {
+		r.queue(token, err)
+
+		return
+	}
+
+	// The signed registration txs are not checked on chain by the
+	// registerer, so the registered tokens are never signed again
+	if _, ok := r.txRegisterer(); ok && !r.unregistered(token) {
+		return
+	}
+
+	// The token is never broadcast without being marked as registering
+	if !r.markRegistering(token) {
+		return
+	}
+
+	receipt, err := r.broadcast(token)
+
+	// The token is left registering, and its outcome
+	// is resolved by the precomputed hash on the next queue run
+	if errors.Is(err, addpkg.ErrBroadcastUncertain) {
+		r.logger.Warn("registration outcome unknown", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+		return
+	}
+
+	r.setOutcome(token, err)
+
+	// The fee is paid for every committed tx
+	if receipt != nil {
+		token.RegisterTxHash = receipt.TxHash
+
+		spend = &ledger.Spend{
+			Time:     time.Now(),
+			PkgPath:  token.PkgPath,
+			Deployer: token.Deployer,
+			TxHash:   receipt.TxHash,
+			Denom:    receipt.Fee.Denom,
+			Amount:   receipt.Fee.Amount,
+		}
+
+		r.budget.Record(spend)
+	}
+
+	r.save(token, spend)
+}
+
+// broadcast broadcasts the registration tx of the token,
+// with the registerer of the token kind
+func (r *Registrar) broadcast(token *ledger.Token) (*addpkg.Receipt, error) {
+	if registerer, ok := r.txRegisterer(); ok {
+		return r.sendPrepared(registerer, token.Kind, token)
+	}
+
+	if token.Kind != ledger.KindGRC721 {
+		return r.registerer.RegisterGrc20Token(token.PkgPath)
+	}
+
+	registerer, ok := r.registerer.(Grc721Registerer)
+	if !ok {
+		return nil, errGrc721Unsupported
+	}
+
+	return registerer.RegisterGrc721Collection(token.PkgPath)
+}
+
+// setOutcome sets the token status from the registration error, if any
+func (r *Registrar) setOutcome(token *ledger.Token, err error) {
+	var (
+		simErr      *addpkg.SimulationError
+		templateErr *addpkg.TemplateError
+	)
+
+	// The outcome of the signed tx is known
+	token.PendingTx = nil
+
+	switch {
+	case err == nil, errors.Is(err, addpkg.ErrTokenAlreadyRegistered):
+		token.Status = ledger.StatusRegistered
+		token.Error = ""
+
+		r.logger.Info("registered grc20 token", zap.String("pkgPath", token.PkgPath))
+	case errors.As(err, &simErr):
+		token.Status = ledger.StatusSimulationFailed
+		token.Error = simErr.VMError
+
+		r.logger.Error(
+			"grc20 token registration failed simulation",
+			zap.String("pkgPath", token.PkgPath),
+			zap.String("error", simErr.VMError),
+		)
+	case errors.As(err, &templateErr):
+		token.Status = ledger.StatusTemplateError
+		token.Error = templateErr.Error()
+
+		r.logger.Error(
+			"grc20 token register package failed to type-check",
+			zap.String("pkgPath", token.PkgPath),
+			zap.Error(templateErr),
+		)
+	default:
+		token.Status = ledger.StatusFailed
+		token.Error = err.Error()
+
+		r.logger.Error("unable to register grc20 token", zap.Error(err))
+	}
+}
+
+// save persists the token ledger record, alongside the spend
+// record, if any. The registration intent of the token is consumed,
+// and its lifecycle event is notified, in the same batch. It returns a flag indicating if it was saved
+func (r *Registrar) save(token *ledger.Token, spend *ledger.Spend) bool {
+	token.UpdatedAt = time.Now()
+
+	recordVersion(token)
+
+	wb := r.storage.WriteBatch()
+
+	if err := wb.SetToken(token); err != nil {
+		r.logger.Error("unable to save token", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+		_ = wb.Rollback()
+
+		return false
+	}
+
+	if spend != nil {
+		if err := wb.SetSpend(spend); err != nil {
+			r.logger.Error("unable to save spend", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+			_ = wb.Rollback()
+
+			return false
+		}
+	}
+
+	if err := wb.DeleteIntent(token.PkgPath); err != nil {
+		r.logger.Error("unable to consume intent", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+		_ = wb.Rollback()
+
+		return false
+	}
+
+	r.notify(wb, token)
+
+	if err := wb.Commit(); err != nil {
+		r.logger.Error("unable to commit token", zap.String("pkgPath", token.PkgPath), zap.Error(err))
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,334 @@
+package registrar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/budget"
+	"github.com/gnolang/tx-indexer/detector"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+	"github.com/gnolang/tx-indexer/storage"
+	"github.com/gnolang/tx-indexer/types"
+)
+
+// newTestStorage creates a new temporary storage instance
+func newTestStorage(t *testing.T) *storage.Pebble {
+	t.Helper()
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	return s
+}
+
+func TestRegistrar_Register(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fee = std.NewCoin("ugnot", 1_000_000)
+
+		s = newTestStorage(t)
+
+		registerer = &mockRegisterer{
+			estimateFeeFn: func() std.Coin {
+				return fee
+			},
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				return &addpkg.Receipt{
+					TxHash: "hash " + pkgPath,
+					Fee:    fee,
+				}, nil
+			},
+		}
+
+		alerts = make([]*types.BudgetExceeded, 0)
+		em     = &mockEvents{
+			signalEventFn: func(event events.Event) {
+				alerts = append(alerts, event.(*types.BudgetExceeded))
+			},
+		}
+
+		tokens = []*ledger.Token{
+			{PkgPath: "gno.land/r/demo/foo", Deployer: "g1alice"},
+			{PkgPath: "gno.land/r/demo/bar", Deployer: "g1alice"},
+		}
+	)
+
+	r := New(
+		s,
+		registerer,
+		em,
+		WithBudget(budget.NewTracker(budget.Config{Hourly: fee.Amount}, nil)),
+	)
+
+	for _, token := range tokens {
+		r.Register(token)
+	}
+
+	// Make sure the first token was registered
+	foo, err := s.GetToken(tokens[0].PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "hash gno.land/r/demo/foo", foo.RegisterTxHash)
+
+	// Make sure the second token was queued, with an alert
+	bar, err := s.GetToken(tokens[1].PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusQueued, bar.Status)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "hourly", alerts[0].Budget)
+	assert.Equal(t, tokens[1].PkgPath, alerts[0].Token.PkgPath)
+
+	// Make sure the spend was persisted
+	spends, err := s.GetSpends()
+	require.NoError(t, err)
+
+	require.Len(t, spends, 1)
+	assert.Equal(t, fee.Amount, spends[0].Amount)
+	assert.Equal(t, tokens[0].PkgPath, spends[0].PkgPath)
+}
+
+func TestRegistrar_Register_Kind(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		grc20  = make([]string, 0)
+		grc721 = make([]string, 0)
+
+		registerer = &mockGrc721Registerer{
+			mockRegisterer: mockRegisterer{
+				registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+					grc20 = append(grc20, pkgPath)
+
+					return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+				},
+			},
+			registerGrc721CollectionFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				grc721 = append(grc721, pkgPath)
+
+				return &addpkg.Receipt{TxHash: "hash " + pkgPath}, nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/foo", Kind: ledger.KindGRC20})
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/nft", Kind: ledger.KindGRC721})
+
+	// Make sure the registrations were routed by the token kind
+	assert.Equal(t, []string{"gno.land/r/demo/foo"}, grc20)
+	assert.Equal(t, []string{"gno.land/r/demo/nft"}, grc721)
+
+	nft, err := s.GetToken("gno.land/r/demo/nft")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, nft.Status)
+	assert.Equal(t, ledger.KindGRC721, nft.Kind)
+
+	// Make sure the collections fail without a grc721 registerer
+	r = New(s, &registerer.mockRegisterer, &mockEvents{})
+
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/gallery", Kind: ledger.KindGRC721})
+
+	gallery, err := s.GetToken("gno.land/r/demo/gallery")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusFailed, gallery.Status)
+	assert.Equal(t, errGrc721Unsupported.Error(), gallery.Error)
+}
+
+func TestRegistrar_Register_Policy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return nil, nil
+			},
+		}
+
+		decisions = map[string]policy.Decision{
+			"gno.land/r/demo/allowed":  policy.DecisionAllow,
+			"gno.land/r/demo/rejected": policy.DecisionReject,
+			"gno.land/r/demo/review":   policy.DecisionReview,
+		}
+
+		p = &mockPolicy{
+			evaluateFn: func(token *ledger.Token) policy.Result {
+				return policy.Result{
+					Decision: decisions[token.PkgPath],
+					Reason:   "reason " + token.PkgPath,
+				}
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithPolicy(p))
+
+	for pkgPath := range decisions {
+		r.Register(&ledger.Token{PkgPath: pkgPath})
+	}
+
+	// Make sure only the allowed token was registered
+	assert.Equal(t, []string{"gno.land/r/demo/allowed"}, registered)
+
+	expectedStatuses := map[string]ledger.Status{
+		"gno.land/r/demo/allowed":  ledger.StatusRegistered,
+		"gno.land/r/demo/rejected": ledger.StatusRejected,
+		"gno.land/r/demo/review":   ledger.StatusNeedsReview,
+	}
+
+	for pkgPath, status := range expectedStatuses {
+		token, err := s.GetToken(pkgPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, status, token.Status)
+		assert.Equal(t, "reason "+pkgPath, token.PolicyReason)
+	}
+}
+
+func TestRegistrar_Register_Impersonation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return nil, nil
+			},
+		}
+
+		original = &ledger.Token{PkgPath: "gno.land/r/gnoswap/v1/gns", Name: "Gnoswap", Symbol: "GNS"}
+		fake     = &ledger.Token{PkgPath: "gno.land/r/scam/gns", Name: "Gnoswap", Symbol: "GNS"}
+		fakeGnot = &ledger.Token{PkgPath: "gno.land/r/scam/gnot", Name: "Gno", Symbol: "GN0T"}
+	)
+
+	r := New(
+		s,
+		registerer,
+		&mockEvents{},
+		WithImpersonationChecker(detector.NewImpersonationChecker([]string{"GNOT"})),
+	)
+
+	r.Register(original)
+	r.Register(fake)
+	r.Register(fakeGnot)
+
+	// Make sure only the original token was registered
+	assert.Equal(t, []string{original.PkgPath}, registered)
+
+	for _, pkgPath := range []string{fake.PkgPath, fakeGnot.PkgPath} {
+		token, err := s.GetToken(pkgPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, ledger.StatusNeedsReview, token.Status)
+		assert.Contains(t, token.PolicyReason, "suspected impersonation")
+		assert.NotEmpty(t, token.Impersonates)
+	}
+}
+
+func TestRegistrar_Register_ProbeFailed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return nil, nil
+			},
+		}
+
+		prober = &mockProber{
+			probeFn: func(token *ledger.Token) error {
+				if token.PkgPath == "gno.land/r/demo/broken" {
+					return errors.New("probe TotalSupply() failed")
+				}
+
+				return nil
+			},
+		}
+	)
+
+	r := New(s, registerer, &mockEvents{}, WithProber(prober))
+
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/foo", Symbol: "FOO"})
+	r.Register(&ledger.Token{PkgPath: "gno.land/r/demo/broken", Symbol: "BRK"})
+
+	// Make sure only the valid token was registered
+	assert.Equal(t, []string{"gno.land/r/demo/foo"}, registered)
+
+	token, err := s.GetToken("gno.land/r/demo/broken")
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusProbeFailed, token.Status)
+	assert.Equal(t, "probe TotalSupply() failed", token.Error)
+}
+
+func TestRegistrar_ProcessQueue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockRegisterer{
+			registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+				registered = append(registered, pkgPath)
+
+				return nil, addpkg.ErrTokenAlreadyRegistered
+			},
+		}
+	)
+
+	// Save the queued tokens
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetToken(&ledger.Token{PkgPath: "gno.land/r/demo/a", Height: 20, Status: ledger.StatusQueued}))
+	require.NoError(t, wb.SetToken(&ledger.Token{PkgPath: "gno.land/r/demo/b", Height: 10, Status: ledger.StatusQueued}))
+	require.NoError(t, wb.SetToken(&ledger.Token{PkgPath: "gno.land/r/demo/c", Height: 5, Status: ledger.StatusFailed}))
+	require.NoError(t, wb.Commit())
+
+	r := New(s, registerer, &mockEvents{})
+
+	r.processQueue()
+
+	// Make sure only the queued tokens were registered, in order
+	assert.Equal(t, []string{"gno.land/r/demo/b", "gno.land/r/demo/a"}, registered)
+
+	tokens, err := s.GetTokens()
+	require.NoError(t, err)
+
+	for _, token := range tokens[:2] {
+		assert.Equal(t, ledger.StatusRegistered, token.Status)
+	}
+}
@@ -0,0 +1,102 @@
+package registrar
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Registerer defines the interface for the registration tx broadcaster
+type Registerer interface {
+	// EstimateFee returns the fee paid for a single registration tx
+	EstimateFee() std.Coin
+
+	// RegisterGrc20Token registers the token on the target realm
+	RegisterGrc20Token(pkgPath string) (*addpkg.Receipt, error)
+}
+
+// BatchRegisterer defines the interface for the registration
+// tx broadcaster that can register multiple tokens in a single tx
+type BatchRegisterer interface {
+	// RegisterGrc20Tokens registers the tokens on the target realm, in a single tx
+	RegisterGrc20Tokens(pkgPaths []string) (*addpkg.Receipt, error)
+}
+
+// Grc721Registerer defines the interface for the registration
+// tx broadcaster that can register grc721 collections
+type Grc721Registerer interface {
+	// RegisterGrc721Collection registers the collection on the grc721 target realms
+	RegisterGrc721Collection(pkgPath string) (*addpkg.Receipt, error)
+}
+
+// RegistrationChecker defines the interface for the registration
+// tx broadcaster that can verify the token registration on chain
+type RegistrationChecker interface {
+	// IsRegistered checks if the token is registered on the target realm
+	IsRegistered(pkgPath string) (bool, error)
+}
+
+// TxRegisterer defines the interface for the registration tx broadcaster
+// that signs the registration txs ahead of their broadcast, so their hash
+// is persisted before they are sent, and looked up on an uncertain outcome
+type TxRegisterer interface {
+	// Prepare signs the registration tx of the tokens, without broadcasting it
+	Prepare(kind ledger.Kind, tokens ...*ledger.Token) (*addpkg.SignedTx, error)
+
+	// Broadcast broadcasts the signed registration tx
+	Broadcast(signed *addpkg.SignedTx) (*addpkg.Receipt, error)
+
+	// LookupTx looks up the committed tx by its hash, on the chain
+	LookupTx(txHash string) (*addpkg.Receipt, error)
+}
+
+// VersionedRegisterer defines the interface for the registration
+// tx broadcaster that registers the tokens to a registry version
+type VersionedRegisterer interface {
+	// SetVersion sets the registry version the tokens are registered to
+	SetVersion(version string) error
+}
+
+// Events is the events API
+type Events interface {
+	// SignalEvent signals a new event to the event manager
+	SignalEvent(events.Event)
+}
+
+// Policy defines the interface for the registration policy
+type Policy interface {
+	// Evaluate evaluates the registration policy for the given token
+	Evaluate(token *ledger.Token) policy.Result
+}
+
+// Prober defines the interface for the token behavioral validation
+type Prober interface {
+	// Probe validates the behavior of the deployed token
+	Probe(token *ledger.Token) error
+}
+
+// Fence defines the interface for the single-writer lease,
+// held by the registrar instance that is allowed to register
+type Fence interface {
+	// Validate checks that the lease is still held,
+	// with the fencing token it was acquired with
+	Validate() error
+}
+
+// Outbox defines the interface for the lifecycle webhook outbox
+type Outbox interface {
+	// Enqueue writes the lifecycle notification to the outbox,
+	// in the batch, so it is only delivered once the batch is committed
+	Enqueue(wb storage.Batch, notification *ledger.Notification) error
+}
+
+// BalanceChecker defines the interface for the registration
+// tx broadcaster that can report the signer account balance
+type BalanceChecker interface {
+	// SignerBalance returns the balance of the signer account, in the fee denom
+	SignerBalance() (*ledger.Balance, error)
+}
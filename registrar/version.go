@@ -0,0 +1,160 @@
+package registrar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// loadVersion loads the active registry version from the storage,
+// and activates it on the registerer
+func (r *Registrar) loadVersion() {
+	version, err := r.storage.GetRegistryVersion()
+	if err != nil {
+		if !errors.Is(err, storageErrors.ErrNotFound) {
+			r.logger.Error("unable to load registry version", zap.Error(err))
+		}
+
+		return
+	}
+
+	if registerer, ok := r.registerer.(VersionedRegisterer); ok {
+		if err := registerer.SetVersion(version); err != nil {
+			r.logger.Error("unable to activate registry version", zap.String("version", version), zap.Error(err))
+
+			return
+		}
+	}
+
+	r.version = version
+}
+
+// Version returns the active registry version, if any
+func (r *Registrar) Version() string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.version
+}
+
+// Deployed handles the deployment of a package on chain. If the package
+// is the registry realm of a new registry version, the version is activated
+func (r *Registrar) Deployed(pkgPath string) {
+	version, ok := r.upgrades[pkgPath]
+	if !ok {
+		return
+	}
+
+	r.logger.Info(
+		"registry realm deployed",
+		zap.String("pkgPath", pkgPath),
+		zap.String("version", version),
+	)
+
+	if err := r.Upgrade(version); err != nil {
+		r.logger.Error("unable to upgrade registry version", zap.String("version", version), zap.Error(err))
+	}
+}
+
+// Upgrade activates the given registry version, and queues the registered
+// tokens for the registration to the new version. The first activated
+// version is adopted as is, without re-registering the tokens
+func (r *Registrar) Upgrade(version string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if version == r.version {
+		return nil
+	}
+
+	if registerer, ok := r.registerer.(VersionedRegisterer); ok {
+		if err := registerer.SetVersion(version); err != nil {
+			return err
+		}
+	}
+
+	tokens, err := r.storage.GetTokens()
+	if err != nil {
+		return fmt.Errorf("unable to fetch tokens, %w", err)
+	}
+
+	var (
+		previous = r.version
+		requeued = 0
+		now      = time.Now()
+		wb       = r.storage.WriteBatch()
+	)
+
+	for _, token := range tokens {
+		switch {
+		case previous == "" && token.Status == ledger.StatusRegistered:
+			// The registrations carry over to the first version
+		case token.Status == ledger.StatusRegistered:
+			token.Status = ledger.StatusQueued
+			token.RegisterTxHash = ""
+			token.Error = ""
+
+			requeued++
+		case token.Status == ledger.StatusQueued, token.Status == ledger.StatusBatched:
+			// The pending registrations are sent to the new version
+		default:
+			continue
+		}
+
+		token.Version = version
+		token.UpdatedAt = now
+
+		recordVersion(token)
+
+		if err := wb.SetToken(token); err != nil {
+			_ = wb.Rollback()
+
+			return fmt.Errorf("unable to save token %s, %w", token.PkgPath, err)
+		}
+	}
+
+	if err := wb.SetRegistryVersion(version); err != nil {
+		_ = wb.Rollback()
+
+		return fmt.Errorf("unable to save registry version, %w", err)
+	}
+
+	if err := wb.Commit(); err != nil {
+		return fmt.Errorf("unable to commit registry upgrade, %w", err)
+	}
+
+	r.version = version
+
+	r.logger.Info(
+		"registry version upgraded",
+		zap.String("from", previous),
+		zap.String("to", version),
+		zap.Int("requeued", requeued),
+	)
+
+	return nil
+}
+
+// recordVersion records the token registration
+// status for its registry version, if any
+func recordVersion(token *ledger.Token) {
+	if token.Version == "" {
+		return
+	}
+
+	if token.Registrations == nil {
+		token.Registrations = make(map[string]*ledger.Registration)
+	}
+
+	token.Registrations[token.Version] = &ledger.Registration{
+		UpdatedAt:      token.UpdatedAt,
+		Status:         token.Status,
+		RegisterTxHash: token.RegisterTxHash,
+		Error:          token.Error,
+	}
+}
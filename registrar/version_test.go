@@ -0,0 +1,176 @@
+package registrar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/addpkg"
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestRegistrar_Upgrade(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		versions   = make([]string, 0)
+		registerer = &mockVersionedRegisterer{
+			mockRegisterer: mockRegisterer{
+				registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+					return &addpkg.Receipt{
+						TxHash: "hash " + versions[len(versions)-1],
+					}, nil
+				},
+			},
+			setVersionFn: func(version string) error {
+				versions = append(versions, version)
+
+				return nil
+			},
+		}
+
+		registry = "gno.land/r/gnoswap/v2/pool"
+		token    = &ledger.Token{PkgPath: "gno.land/r/demo/foo"}
+	)
+
+	r := New(
+		s,
+		registerer,
+		&mockEvents{},
+		WithUpgrades(map[string]string{registry: "v2"}),
+	)
+
+	// Make sure the first version is adopted, without re-registration
+	require.NoError(t, r.Upgrade("v1"))
+
+	r.Register(token)
+
+	foo, err := s.GetToken(token.PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "v1", foo.Version)
+	assert.Equal(t, "hash v1", foo.RegisterTxHash)
+
+	// Make sure unrelated deployments are ignored
+	r.Deployed("gno.land/r/demo/bar")
+
+	assert.Equal(t, "v1", r.Version())
+
+	// Make sure the registry deployment queues the registered tokens
+	r.Deployed(registry)
+
+	assert.Equal(t, "v2", r.Version())
+	assert.Equal(t, []string{"v1", "v2"}, versions)
+
+	foo, err = s.GetToken(token.PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusQueued, foo.Status)
+	assert.Equal(t, "v2", foo.Version)
+	assert.Empty(t, foo.RegisterTxHash)
+
+	// Make sure the token is registered to the new version
+	r.processQueue()
+
+	foo, err = s.GetToken(token.PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "hash v2", foo.RegisterTxHash)
+
+	// Make sure the status is kept per version
+	require.Len(t, foo.Registrations, 2)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Registrations["v1"].Status)
+	assert.Equal(t, "hash v1", foo.Registrations["v1"].RegisterTxHash)
+	assert.Equal(t, ledger.StatusRegistered, foo.Registrations["v2"].Status)
+	assert.Equal(t, "hash v2", foo.Registrations["v2"].RegisterTxHash)
+
+	// Make sure the active version is restored on restart
+	restarted := New(s, registerer, &mockEvents{})
+
+	assert.Equal(t, "v2", restarted.Version())
+}
+
+func TestRegistrar_Upgrade_Linked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		s = newTestStorage(t)
+
+		registered = make([]string, 0)
+		registerer = &mockVersionedRegisterer{
+			mockRegisterer: mockRegisterer{
+				registerGrc20TokenFn: func(pkgPath string) (*addpkg.Receipt, error) {
+					registered = append(registered, pkgPath)
+
+					return &addpkg.Receipt{TxHash: "hash v2"}, nil
+				},
+			},
+			setVersionFn: func(string) error {
+				return nil
+			},
+		}
+
+		token = &ledger.Token{PkgPath: "gno.land/r/demo/foo"}
+	)
+
+	r := New(s, registerer, &mockEvents{})
+
+	require.NoError(t, r.Upgrade("v1"))
+
+	r.Register(token)
+
+	// The token registration to v1 was linked by its memo
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetRegistrationTx(&ledger.RegistrationTx{
+		TxHash:  "hash v1",
+		TokenID: token.PkgPath,
+		Version: "v1",
+		Height:  7,
+	}))
+	require.NoError(t, wb.Commit())
+
+	require.NoError(t, r.Upgrade("v2"))
+
+	// Make sure the v1 link doesn't mark the requeued token registered
+	r.processQueue()
+
+	assert.Equal(t, []string{token.PkgPath, token.PkgPath}, registered)
+
+	foo, err := s.GetToken(token.PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "v2", foo.Version)
+	assert.Equal(t, "hash v2", foo.RegisterTxHash)
+
+	// Make sure a link of the active version marks the token registered, without a registration
+	require.NoError(t, r.Upgrade("v3"))
+
+	wb = s.WriteBatch()
+
+	require.NoError(t, wb.SetRegistrationTx(&ledger.RegistrationTx{
+		TxHash:  "hash v3",
+		TokenID: token.PkgPath,
+		Version: "v3",
+		Height:  9,
+	}))
+	require.NoError(t, wb.Commit())
+
+	r.processQueue()
+
+	assert.Len(t, registered, 2)
+
+	foo, err = s.GetToken(token.PkgPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, ledger.StatusRegistered, foo.Status)
+	assert.Equal(t, "v3", foo.Version)
+	assert.Equal(t, "hash v3", foo.RegisterTxHash)
+}
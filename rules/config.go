@@ -0,0 +1,206 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/policy"
+)
+
+var (
+	errInvalidAction = errors.New("invalid rule action")
+	errInvalidRule   = errors.New("invalid rule")
+)
+
+// ActionType is the type of the action taken on a rule match
+type ActionType string
+
+const (
+	// ActionRegister registers the matched package as a token,
+	// with the registration targets (templates) of its kind
+	ActionRegister ActionType = "register"
+
+	// ActionWebhook notifies the webhook endpoint of the match
+	ActionWebhook ActionType = "webhook"
+
+	// ActionTag tags the matched package in the token ledger
+	ActionTag ActionType = "tag"
+)
+
+// Config is the detection rules configuration
+type Config struct {
+	// Actions are the named actions the rules map to
+	Actions []Action `json:"actions"`
+
+	// Rules are the detection rules, evaluated in order.
+	// Every matching rule triggers its action
+	Rules []Rule `json:"rules"`
+}
+
+// Action is a named action taken on a rule match
+type Action struct {
+	// Name is the unique name of the action
+	Name string `json:"name"`
+
+	// Type is the action type, "register", "webhook" or "tag"
+	Type ActionType `json:"type"`
+
+	// Kind is the token kind registered by the "register" action.
+	// The detected kind is used if empty
+	Kind ledger.Kind `json:"kind,omitempty"`
+
+	// Targets are the names of the registration targets (templates)
+	// the "register" action registers the token to. Every target
+	// of the token kind is used if empty
+	Targets []string `json:"targets,omitempty"`
+
+	// URL is the notified endpoint of the "webhook" action,
+	// one of the configured lifecycle webhook endpoints
+	URL string `json:"url,omitempty"`
+
+	// Tags are the tags set by the "tag" action
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Rule maps the match criteria to a named action
+type Rule struct {
+	// Name is the name of the rule
+	Name string `json:"name"`
+
+	// Match are the match criteria. Every set criterion is required to match
+	Match Criteria `json:"match"`
+
+	// Action is the name of the action taken on a match
+	Action string `json:"action"`
+}
+
+// Criteria are the rule match criteria
+type Criteria struct {
+	// Funcs are the exported functions the package is required to have
+	Funcs []string `json:"funcs,omitempty"`
+
+	// Imports are the packages the package is required to import
+	Imports []string `json:"imports,omitempty"`
+
+	// Source is the regular expression the package
+	// source is required to match, in any file
+	Source string `json:"source,omitempty"`
+
+	// Event is the event type the tx is required to emit
+	Event string `json:"event,omitempty"`
+
+	// PkgPath is the package path pattern, a glob (* matches within
+	// a path segment, ** matches across segments), or a regular
+	// expression if it starts with "re:"
+	PkgPath string `json:"pkgPath,omitempty"`
+
+	// Deployer is the required deployer address
+	Deployer string `json:"deployer,omitempty"`
+}
+
+// compiledRule is a validated rule, with its compiled patterns
+type compiledRule struct {
+	Rule
+
+	action  *Action
+	source  *regexp.Regexp
+	pkgPath *regexp.Regexp
+}
+
+// LoadConfig loads the detection rules configuration
+// from the given JSON file
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rules file, %w", err)
+	}
+
+	var cfg Config
+
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("unable to parse rules file, %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// compile validates the configuration, and compiles its rules. The action
+// targets and URLs are validated against the known target names and
+// webhook endpoints, if any
+func compile(cfg *Config, targets, endpoints []string) ([]*compiledRule, error) {
+	actions := make(map[string]*Action, len(cfg.Actions))
+
+	for i := range cfg.Actions {
+		action := &cfg.Actions[i]
+
+		if _, exists := actions[action.Name]; exists || action.Name == "" {
+			return nil, fmt.Errorf("%w, duplicate or missing name %q", errInvalidAction, action.Name)
+		}
+
+		switch action.Type {
+		case ActionRegister:
+			if action.Kind != "" && action.Kind != ledger.KindGRC20 && action.Kind != ledger.KindGRC721 {
+				return nil, fmt.Errorf("%w %s, unknown kind %q", errInvalidAction, action.Name, action.Kind)
+			}
+
+			for _, target := range action.Targets {
+				if targets != nil && !slices.Contains(targets, target) {
+					return nil, fmt.Errorf("%w %s, unknown target %q", errInvalidAction, action.Name, target)
+				}
+			}
+		case ActionWebhook:
+			if action.URL == "" {
+				return nil, fmt.Errorf("%w %s, missing webhook URL", errInvalidAction, action.Name)
+			}
+
+			if endpoints != nil && !slices.Contains(endpoints, action.URL) {
+				return nil, fmt.Errorf("%w %s, unknown webhook endpoint %q", errInvalidAction, action.Name, action.URL)
+			}
+		case ActionTag:
+			if len(action.Tags) == 0 {
+				return nil, fmt.Errorf("%w %s, missing tags", errInvalidAction, action.Name)
+			}
+		default:
+			return nil, fmt.Errorf("%w %s, unknown type %q", errInvalidAction, action.Name, action.Type)
+		}
+
+		actions[action.Name] = action
+	}
+
+	compiled := make([]*compiledRule, 0, len(cfg.Rules))
+
+	for i, rule := range cfg.Rules {
+		action, ok := actions[rule.Action]
+		if !ok {
+			return nil, fmt.Errorf("%w #%d, unknown action %q", errInvalidRule, i, rule.Action)
+		}
+
+		c := &compiledRule{
+			Rule:   rule,
+			action: action,
+		}
+
+		var err error
+
+		if rule.Match.Source != "" {
+			if c.source, err = regexp.Compile(rule.Match.Source); err != nil {
+				return nil, fmt.Errorf("%w #%d, invalid source pattern, %w", errInvalidRule, i, err)
+			}
+		}
+
+		if rule.Match.PkgPath != "" {
+			if c.pkgPath, err = policy.CompilePattern(rule.Match.PkgPath); err != nil {
+				return nil, fmt.Errorf("%w #%d, invalid pkgPath pattern, %w", errInvalidRule, i, err)
+			}
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return compiled, nil
+}
@@ -0,0 +1,249 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/detector"
+)
+
+// DefaultReloadInterval is the default interval
+// the rules file is checked for changes
+const DefaultReloadInterval = 5 * time.Second
+
+// Subject is a tx message evaluated against the rules
+type Subject struct {
+	PkgPath  string // the deployed package, or the called realm
+	Deployer string // the message creator
+
+	// Call marks the realm calls, as opposed to the package deployments
+	Call bool
+
+	// Files are the package source files, for the deployed packages
+	Files []detector.File
+
+	// Events are the types of the events emitted by the tx
+	Events []string
+}
+
+// Match is a matched rule, and the action it maps to
+type Match struct {
+	Rule   string
+	Action *Action
+}
+
+// Engine evaluates the tx messages against the detection rules.
+// The rules file is reloaded when it changes
+type Engine struct {
+	logger *zap.Logger
+
+	rules atomic.Pointer[[]*compiledRule]
+
+	targets   []string // the known registration target names, if any
+	endpoints []string // the known webhook endpoint URLs, if any
+
+	path           string
+	modTime        time.Time // the modification time of the loaded rules file
+	reloadInterval time.Duration
+}
+
+// New creates a new rules engine, with the rules loaded from the given file
+func New(path string, opts ...Option) (*Engine, error) {
+	e := &Engine{
+		logger:         zap.NewNop(),
+		path:           path,
+		reloadInterval: DefaultReloadInterval,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rules file, %w", err)
+	}
+
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+
+	e.modTime = info.ModTime()
+
+	return e, nil
+}
+
+// load loads and compiles the rules file, replacing the active rules
+func (e *Engine) load() error {
+	cfg, err := LoadConfig(e.path)
+	if err != nil {
+		return err
+	}
+
+	compiled, err := compile(cfg, e.targets, e.endpoints)
+	if err != nil {
+		return err
+	}
+
+	e.rules.Store(&compiled)
+
+	return nil
+}
+
+// Watch reloads the rules file whenever it changes, until the context
+// is cancelled. Invalid rules files are logged, and the active rules are kept
+func (e *Engine) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(e.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("rules watcher shut down")
+
+			return nil
+		case <-ticker.C:
+			e.reload()
+		}
+	}
+}
+
+// reload reloads the rules file, if it was modified since the last load
+func (e *Engine) reload() {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		e.logger.Error("unable to check rules file", zap.Error(err))
+
+		return
+	}
+
+	if info.ModTime().Equal(e.modTime) {
+		return
+	}
+
+	// The file is not reloaded until it changes again
+	e.modTime = info.ModTime()
+
+	if err := e.load(); err != nil {
+		e.logger.Error("unable to reload rules, keeping the active rules", zap.Error(err))
+
+		return
+	}
+
+	e.logger.Info("reloaded rules", zap.Int("rules", len(*e.rules.Load())))
+}
+
+// Evaluate evaluates the message against the rules,
+// returning the matched rules in order
+func (e *Engine) Evaluate(subject *Subject) []*Match {
+	var (
+		matches = make([]*Match, 0)
+		lazy    = &lazySubject{Subject: subject}
+	)
+
+	for _, rule := range *e.rules.Load() {
+		if !rule.matches(lazy) {
+			continue
+		}
+
+		matches = append(matches, &Match{
+			Rule:   rule.Name,
+			Action: rule.action,
+		})
+	}
+
+	return matches
+}
+
+// lazySubject is the evaluated subject, with the
+// package functions and imports parsed on first use
+type lazySubject struct {
+	*Subject
+
+	funcs   []string
+	imports []string
+}
+
+func (s *lazySubject) getFuncs() []string {
+	if s.funcs == nil {
+		s.funcs = detector.ExportedFuncs(s.Files)
+	}
+
+	return s.funcs
+}
+
+func (s *lazySubject) getImports() []string {
+	if s.imports == nil {
+		s.imports = detector.Imports(s.Files)
+	}
+
+	return s.imports
+}
+
+// matches checks if every set criterion of the rule matches the subject
+func (r *compiledRule) matches(subject *lazySubject) bool {
+	if r.pkgPath != nil && !r.pkgPath.MatchString(subject.PkgPath) {
+		return false
+	}
+
+	if r.Match.Deployer != "" && r.Match.Deployer != subject.Deployer {
+		return false
+	}
+
+	if r.Match.Event != "" && !contains(subject.Events, r.Match.Event) {
+		return false
+	}
+
+	if len(r.Match.Funcs) > 0 && !containsAll(subject.getFuncs(), r.Match.Funcs) {
+		return false
+	}
+
+	if len(r.Match.Imports) > 0 && !containsAll(subject.getImports(), r.Match.Imports) {
+		return false
+	}
+
+	if r.source != nil {
+		found := false
+
+		for _, file := range subject.Files {
+			if r.source.MatchString(file.Body) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsAll checks if all the values are in the list
+func containsAll(list, values []string) bool {
+	for _, value := range values {
+		if !contains(list, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contains checks if the value is in the list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,276 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/detector"
+)
+
+const testRules = `{
+	"actions": [
+		{"name": "register", "type": "register"},
+		{"name": "notify", "type": "webhook", "url": "http://localhost/hook"},
+		{"name": "tag-nft", "type": "tag", "tags": ["nft"]}
+	],
+	"rules": [
+		{
+			"name": "grc20",
+			"match": {
+				"funcs": ["Transfer", "BalanceOf"],
+				"imports": ["gno.land/p/demo/grc/grc20"],
+				"source": "grc20\\.NewBanker\\("
+			},
+			"action": "register"
+		},
+		{
+			"name": "factory",
+			"match": {"event": "TokenCreated", "pkgPath": "gno.land/r/factory/**"},
+			"action": "notify"
+		},
+		{
+			"name": "alice nfts",
+			"match": {"imports": ["gno.land/p/demo/grc/grc721"], "deployer": "g1alice"},
+			"action": "tag-nft"
+		}
+	]
+}`
+
+const tokenSource = `package foo
+
+import "gno.land/p/demo/grc/grc20"
+
+var banker = grc20.NewBanker("Foo", "FOO", 4)
+
+func Transfer(to string, amount uint64) {}
+
+func BalanceOf(owner string) uint64 { return 0 }
+`
+
+// writeRules writes the rules file to a temporary directory
+func writeRules(t *testing.T, rules string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(rules), 0o600))
+
+	return path
+}
+
+// matchedRules returns the names of the rules matching the subject
+func matchedRules(e *Engine, subject *Subject) []string {
+	names := make([]string, 0)
+
+	for _, match := range e.Evaluate(subject) {
+		names = append(names, match.Rule)
+	}
+
+	return names
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	e, err := New(writeRules(t, testRules))
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name     string
+		subject  *Subject
+		expected []string
+	}{
+		{
+			"grc20 token",
+			&Subject{
+				PkgPath: "gno.land/r/demo/foo",
+				Files:   []detector.File{{Name: "foo.gno", Body: tokenSource}},
+			},
+			[]string{"grc20"},
+		},
+		{
+			"missing function",
+			&Subject{
+				PkgPath: "gno.land/r/demo/foo",
+				Files: []detector.File{{
+					Name: "foo.gno",
+					Body: "package foo\n\nimport \"gno.land/p/demo/grc/grc20\"\n\nvar banker = grc20.NewBanker(\"Foo\", \"FOO\", 4)\n",
+				}},
+			},
+			[]string{},
+		},
+		{
+			"factory event",
+			&Subject{
+				PkgPath: "gno.land/r/factory/v1/tokens",
+				Events:  []string{"Transfer", "TokenCreated"},
+			},
+			[]string{"factory"},
+		},
+		{
+			"event of another realm",
+			&Subject{
+				PkgPath: "gno.land/r/demo/tokens",
+				Events:  []string{"TokenCreated"},
+			},
+			[]string{},
+		},
+		{
+			"multiple rules",
+			&Subject{
+				PkgPath:  "gno.land/r/factory/nft",
+				Deployer: "g1alice",
+				Files: []detector.File{{
+					Name: "nft.gno",
+					Body: "package nft\n\nimport \"gno.land/p/demo/grc/grc721\"\n",
+				}},
+				Events: []string{"TokenCreated"},
+			},
+			[]string{"factory", "alice nfts"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, matchedRules(e, testCase.subject))
+		})
+	}
+}
+
+func TestEngine_Reload(t *testing.T) {
+	t.Parallel()
+
+	path := writeRules(t, testRules)
+
+	e, err := New(path)
+	require.NoError(t, err)
+
+	subject := &Subject{
+		PkgPath: "gno.land/r/factory/tokens",
+		Events:  []string{"TokenCreated"},
+	}
+
+	require.Equal(t, []string{"factory"}, matchedRules(e, subject))
+
+	// Make sure a changed rules file is reloaded
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"actions": [{"name": "tag", "type": "tag", "tags": ["factory"]}],
+		"rules": [{"name": "tokens", "match": {"pkgPath": "gno.land/r/factory/tokens"}, "action": "tag"}]
+	}`), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+
+	e.reload()
+
+	assert.Equal(t, []string{"tokens"}, matchedRules(e, subject))
+
+	// Make sure an invalid rules file keeps the active rules
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules": [{"name": "broken", "action": "missing"}]}`), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+
+	e.reload()
+
+	assert.Equal(t, []string{"tokens"}, matchedRules(e, subject))
+}
+
+func TestNew_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		rules string
+	}{
+		{
+			"unknown action",
+			`{"rules": [{"name": "grc20", "action": "register"}]}`,
+		},
+		{
+			"unknown action type",
+			`{"actions": [{"name": "deploy", "type": "deploy"}]}`,
+		},
+		{
+			"webhook without URL",
+			`{"actions": [{"name": "notify", "type": "webhook"}]}`,
+		},
+		{
+			"unknown webhook endpoint",
+			`{"actions": [{"name": "notify", "type": "webhook", "url": "http://localhost/unknown"}]}`,
+		},
+		{
+			"tag without tags",
+			`{"actions": [{"name": "tag", "type": "tag"}]}`,
+		},
+		{
+			"unknown register kind",
+			`{"actions": [{"name": "register", "type": "register", "kind": "grc1155"}]}`,
+		},
+		{
+			"unknown register target",
+			`{"actions": [{"name": "register", "type": "register", "targets": ["unknown"]}]}`,
+		},
+		{
+			"duplicate action",
+			`{"actions": [{"name": "tag", "type": "tag", "tags": ["a"]}, {"name": "tag", "type": "tag", "tags": ["b"]}]}`,
+		},
+		{
+			"invalid source pattern",
+			`{"actions": [{"name": "tag", "type": "tag", "tags": ["a"]}], "rules": [{"name": "r", "match": {"source": "("}, "action": "tag"}]}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(
+				writeRules(t, testCase.rules),
+				WithTargets([]string{"gnoswap"}),
+				WithEndpoints([]string{"http://localhost/hook"}),
+			)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNew_Targets(t *testing.T) {
+	t.Parallel()
+
+	const targetRules = `{"actions": [{"name": "register", "type": "register", "targets": ["gnoswap"]}]}`
+
+	// Make sure the known targets are accepted
+	_, err := New(writeRules(t, targetRules), WithTargets([]string{"gnoswap", "registry"}))
+	assert.NoError(t, err)
+
+	// Make sure the targets are not validated without the known targets
+	_, err = New(writeRules(t, targetRules))
+	assert.NoError(t, err)
+}
+
+func TestNew_Endpoints(t *testing.T) {
+	t.Parallel()
+
+	path := writeRules(t, testRules)
+
+	// Make sure the known endpoints are accepted
+	_, err := New(path, WithEndpoints([]string{"http://localhost/hook"}))
+	assert.NoError(t, err)
+
+	// Make sure the webhook actions are rejected without any endpoint
+	_, err = New(path, WithEndpoints([]string{}))
+	assert.Error(t, err)
+
+	// Make sure the endpoints are not validated without the known endpoints
+	_, err = New(path)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,42 @@
+package rules
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(e *Engine)
+
+// WithLogger sets the logger to be used
+// with the rules engine
+func WithLogger(logger *zap.Logger) Option {
+	return func(e *Engine) {
+		e.logger = logger
+	}
+}
+
+// WithReloadInterval sets the interval at which
+// the rules file is checked for changes
+func WithReloadInterval(interval time.Duration) Option {
+	return func(e *Engine) {
+		e.reloadInterval = interval
+	}
+}
+
+// WithTargets sets the known registration target names,
+// the "register" actions are validated against
+func WithTargets(names []string) Option {
+	return func(e *Engine) {
+		e.targets = names
+	}
+}
+
+// WithEndpoints sets the known webhook endpoint URLs, the "webhook"
+// actions are validated against. An empty list rejects every
+// "webhook" action, ex. when no webhook endpoint is configured
+func WithEndpoints(urls []string) Option {
+	return func(e *Engine) {
+		e.endpoints = urls
+	}
+}
@@ -0,0 +1,65 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const token = "admin-token"
+
+	testTable := []struct {
+		name           string
+		header         string
+		expectedStatus int
+	}{
+		{
+			"valid token",
+			"Bearer " + token,
+			http.StatusOK,
+		},
+		{
+			"missing header",
+			"",
+			http.StatusUnauthorized,
+		},
+		{
+			"invalid token",
+			"Bearer invalid",
+			http.StatusUnauthorized,
+		},
+		{
+			"invalid scheme",
+			"Basic " + token,
+			http.StatusUnauthorized,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := AuthMiddleware(token)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if testCase.header != "" {
+				req.Header.Set("Authorization", testCase.header)
+			}
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, testCase.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -85,6 +85,11 @@ func (f *Manager) NewTransactionSubscription(conn conns.WSConnection) string {
 	return f.newSubscription(filterSubscription.NewTransactionSubscription(conn))
 }
 
+// NewBudgetSubscription creates a new budget-exceeded alert subscription (over WS)
+func (f *Manager) NewBudgetSubscription(conn conns.WSConnection) string {
+	return f.newSubscription(filterSubscription.NewBudgetSubscription(conn))
+}
+
 // newSubscription adds new subscription to the subscription map
 func (f *Manager) newSubscription(subscription subscription) string {
 	return f.subscriptions.addSubscription(subscription)
@@ -98,7 +103,10 @@ func (f *Manager) UninstallSubscription(id string) bool {
 
 // subscribeToEvents subscribes to new events
 func (f *Manager) subscribeToEvents() {
-	subscription := f.events.Subscribe([]events.Type{commonTypes.NewBlockEvent})
+	subscription := f.events.Subscribe([]events.Type{
+		commonTypes.NewBlockEvent,
+		commonTypes.BudgetExceededEvent,
+	})
 	defer f.events.CancelSubscription(subscription.ID)
 
 	for {
@@ -132,6 +140,11 @@ func (f *Manager) subscribeToEvents() {
 					}
 				}
 			}
+
+			if event.GetType() == commonTypes.BudgetExceededEvent {
+				// Send the alert to all `budgetExceeded` subscriptions
+				f.subscriptions.sendEvent(filterSubscription.BudgetExceededEvent, event.GetData())
+			}
 		}
 	}
 }
@@ -0,0 +1,40 @@
+package subscription
+
+import (
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/serve/conns"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/types"
+)
+
+const (
+	BudgetExceededEvent = "budgetExceeded"
+)
+
+// BudgetSubscription is the budget-exceeded alert type
+// subscription
+type BudgetSubscription struct {
+	*baseSubscription
+}
+
+func NewBudgetSubscription(conn conns.WSConnection) *BudgetSubscription {
+	return &BudgetSubscription{
+		baseSubscription: newBaseSubscription(conn),
+	}
+}
+
+func (b *BudgetSubscription) GetType() events.Type {
+	return BudgetExceededEvent
+}
+
+func (b *BudgetSubscription) WriteResponse(id string, data any) error {
+	alert, ok := data.(*types.BudgetExceeded)
+	if !ok {
+		return fmt.Errorf("unable to cast budget alert, %s", data)
+	}
+
+	// The alert holds no TM2 types, so it is served as plain JSON
+	return b.conn.WriteData(spec.NewJSONSubscribeResponse(id, alert))
+}
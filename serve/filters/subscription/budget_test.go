@@ -0,0 +1,49 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/types"
+)
+
+func TestBudgetSubscription_WriteResponse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		capturedWrite any
+
+		alert = &types.BudgetExceeded{
+			Token:  &ledger.Token{PkgPath: "gno.land/r/demo/foo"},
+			Budget: "hourly",
+			Limit:  1_000_000,
+			Spent:  1_000_000,
+			Amount: 1_000,
+		}
+	)
+
+	mockConn := &mock.Conn{
+		WriteDataFn: func(data any) error {
+			capturedWrite = data
+
+			return nil
+		},
+	}
+
+	// Create the budget subscription
+	budgetSubscription := NewBudgetSubscription(mockConn)
+
+	// Write the response
+	require.NoError(t, budgetSubscription.WriteResponse("id", alert))
+
+	// Make sure the alert was written as is
+	assert.Equal(t, spec.NewJSONSubscribeResponse("id", alert), capturedWrite)
+
+	// Make sure other data is rejected
+	assert.Error(t, budgetSubscription.WriteResponse("id", "block"))
+}
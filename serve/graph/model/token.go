@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+type Token struct {
+	token *ledger.Token
+}
+
+func NewToken(token *ledger.Token) *Token {
+	return &Token{
+		token: token,
+	}
+}
+
+func (t *Token) PkgPath() string {
+	return t.token.PkgPath
+}
+
+func (t *Token) Kind() string {
+	if t.token.Kind == "" {
+		return string(ledger.KindGRC20)
+	}
+
+	return string(t.token.Kind)
+}
+
+func (t *Token) Name() string {
+	return t.token.Name
+}
+
+func (t *Token) Symbol() string {
+	return t.token.Symbol
+}
+
+func (t *Token) Decimals() int {
+	return t.token.Decimals
+}
+
+func (t *Token) Deployer() string {
+	return t.token.Deployer
+}
+
+func (t *Token) Status() string {
+	return string(t.token.Status)
+}
+
+func (t *Token) Height() int {
+	return int(t.token.Height)
+}
+
+func (t *Token) DeployTxHash() string {
+	return t.token.DeployTxHash
+}
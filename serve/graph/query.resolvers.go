@@ -6,9 +6,11 @@ package graph
 
 import (
 	"context"
+	"errors"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/gnolang/tx-indexer/serve/graph/model"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
@@ -122,6 +124,20 @@ func (r *queryResolver) LatestBlockHeight(ctx context.Context) (int, error) {
 	return int(h), err
 }
 
+// Token is the resolver for the token field.
+func (r *queryResolver) Token(ctx context.Context, pkgPath string) (*model.Token, error) {
+	token, err := r.store.GetToken(pkgPath)
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, gqlerror.Wrap(err)
+	}
+
+	return model.NewToken(token), nil
+}
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
@@ -0,0 +1,61 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.45
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gnolang/tx-indexer/serve/graph/model"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DeployTransaction is the resolver for the deploy_transaction field.
+func (r *tokenResolver) DeployTransaction(ctx context.Context, obj *model.Token) (*model.Transaction, error) {
+	if obj.DeployTxHash() == "" {
+		return nil, nil
+	}
+
+	tx, err := r.store.GetTxByHash(obj.DeployTxHash())
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, gqlerror.Wrap(err)
+	}
+
+	return model.NewTransaction(tx), nil
+}
+
+// RegistrationTransactions is the resolver for the registration_transactions field.
+func (r *tokenResolver) RegistrationTransactions(ctx context.Context, obj *model.Token) ([]*model.Transaction, error) {
+	out := make([]*model.Transaction, 0)
+
+	links, err := r.store.GetRegistrationTxs(obj.PkgPath())
+	if err != nil {
+		return nil, gqlerror.Wrap(err)
+	}
+
+	for _, link := range links {
+		tx, err := r.store.GetTxByHash(link.TxHash)
+		if err != nil {
+			graphql.AddError(ctx, err)
+
+			continue
+		}
+
+		out = append(out, model.NewTransaction(tx))
+	}
+
+	return out, nil
+}
+
+// Token returns TokenResolver implementation.
+func (r *Resolver) Token() TokenResolver { return &tokenResolver{r} }
+
+type tokenResolver struct{ *Resolver }
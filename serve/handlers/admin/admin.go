@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+type Handler struct {
+	registrar Registrar
+}
+
+func NewHandler(registrar Registrar) *Handler {
+	return &Handler{
+		registrar: registrar,
+	}
+}
+
+// ListPendingHandler returns the tokens pending a manual approval
+func (h *Handler) ListPendingHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 0 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	tokens, err := h.registrar.ListPending()
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return tokens, nil
+}
+
+// ApproveHandler approves the pending token registration
+func (h *Handler) ApproveHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return handleTokenAction(params, h.registrar.Approve)
+}
+
+// RejectHandler rejects the pending token registration,
+// with an optional reason
+func (h *Handler) RejectHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	pkgPath, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var reason string
+
+	if len(params) == 2 {
+		if reason, ok = params[1].(string); !ok {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	token, err := h.registrar.Reject(pkgPath, reason)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return token, nil
+}
+
+// RetryHandler retries the failed token registration
+func (h *Handler) RetryHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return handleTokenAction(params, h.registrar.Retry)
+}
+
+// RequeueHandler sends the token through the registration pipeline again
+func (h *Handler) RequeueHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return handleTokenAction(params, h.registrar.Requeue)
+}
+
+// PauseHandler pauses the registrations, once the in-flight
+// registration is done, and returns the registrar status
+func (h *Handler) PauseHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return handleControl(params, h.registrar.Pause, h.registrar.Paused)
+}
+
+// ResumeHandler resumes the paused registrations,
+// and returns the registrar status
+func (h *Handler) ResumeHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return handleControl(params, h.registrar.Resume, h.registrar.Paused)
+}
+
+// StatusHandler returns the registrar status
+func (h *Handler) StatusHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return handleControl(params, func() {}, h.registrar.Paused)
+}
+
+// handleControl runs the registrar control action,
+// that takes no params, and returns the registrar status
+func handleControl(
+	params []any,
+	action func(),
+	paused func() bool,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 0 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	action()
+
+	return &Status{
+		Paused: paused(),
+	}, nil
+}
+
+// handleTokenAction runs the admin action
+// that takes the token pkgPath as the only param
+func handleTokenAction(
+	params []any,
+	action func(string) (*ledger.Token, error),
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	pkgPath, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	token, err := action(pkgPath)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,214 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestListPending_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockRegistrar{})
+
+	response, err := h.ListPendingHandler(nil, []any{1})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+}
+
+func TestListPending_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pending = []*ledger.Token{
+			{PkgPath: "gno.land/r/demo/foo", Status: ledger.StatusNeedsReview},
+		}
+
+		mockRegistrar = &mockRegistrar{
+			listPendingFn: func() ([]*ledger.Token, error) {
+				return pending, nil
+			},
+		}
+	)
+
+	h := NewHandler(mockRegistrar)
+
+	response, err := h.ListPendingHandler(nil, []any{})
+	require.Nil(t, err)
+
+	assert.Equal(t, pending, response)
+}
+
+func TestTokenActions_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockRegistrar{})
+
+	handlers := map[string]func(*metadata.Metadata, []any) (any, *spec.BaseJSONError){
+		"approve": h.ApproveHandler,
+		"reject":  h.RejectHandler,
+		"retry":   h.RetryHandler,
+		"requeue": h.RequeueHandler,
+	}
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param count",
+			[]any{},
+		},
+		{
+			"invalid pkgPath",
+			[]any{1},
+		},
+	}
+
+	for name, handler := range handlers {
+		for _, testCase := range testTable {
+			response, err := handler(nil, testCase.params)
+			assert.Nil(t, response, name)
+
+			require.NotNil(t, err, name)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code, name)
+		}
+	}
+}
+
+func TestTokenActions_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pkgPath = "gno.land/r/demo/foo"
+		called  = make([]string, 0)
+
+		action = func(name string, status ledger.Status) tokenActionDelegate {
+			return func(p string) (*ledger.Token, error) {
+				called = append(called, name)
+
+				return &ledger.Token{PkgPath: p, Status: status}, nil
+			}
+		}
+
+		mockRegistrar = &mockRegistrar{
+			approveFn: action("approve", ledger.StatusQueued),
+			retryFn:   action("retry", ledger.StatusRegistered),
+			requeueFn: action("requeue", ledger.StatusNeedsReview),
+			rejectFn: func(p, reason string) (*ledger.Token, error) {
+				called = append(called, "reject")
+
+				return &ledger.Token{PkgPath: p, Status: ledger.StatusRejected, PolicyReason: reason}, nil
+			},
+		}
+	)
+
+	h := NewHandler(mockRegistrar)
+
+	response, err := h.ApproveHandler(nil, []any{pkgPath})
+	require.Nil(t, err)
+	assert.Equal(t, ledger.StatusQueued, response.(*ledger.Token).Status)
+
+	response, err = h.RetryHandler(nil, []any{pkgPath})
+	require.Nil(t, err)
+	assert.Equal(t, ledger.StatusRegistered, response.(*ledger.Token).Status)
+
+	response, err = h.RequeueHandler(nil, []any{pkgPath})
+	require.Nil(t, err)
+	assert.Equal(t, ledger.StatusNeedsReview, response.(*ledger.Token).Status)
+
+	response, err = h.RejectHandler(nil, []any{pkgPath, "spam"})
+	require.Nil(t, err)
+	assert.Equal(t, "spam", response.(*ledger.Token).PolicyReason)
+
+	assert.Equal(t, []string{"approve", "retry", "requeue", "reject"}, called)
+}
+
+func TestTokenActions_Error(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actionErr = errors.New("invalid token status")
+
+		mockRegistrar = &mockRegistrar{
+			approveFn: func(_ string) (*ledger.Token, error) {
+				return nil, actionErr
+			},
+		}
+	)
+
+	h := NewHandler(mockRegistrar)
+
+	response, err := h.ApproveHandler(nil, []any{"gno.land/r/demo/foo"})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.ServerErrorCode, err.Code)
+	assert.Contains(t, err.Message, actionErr.Error())
+}
+
+func TestControl_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockRegistrar{})
+
+	handlers := map[string]func(*metadata.Metadata, []any) (any, *spec.BaseJSONError){
+		"pause":  h.PauseHandler,
+		"resume": h.ResumeHandler,
+		"status": h.StatusHandler,
+	}
+
+	for name, handler := range handlers {
+		response, err := handler(nil, []any{"param"})
+		assert.Nil(t, response, name)
+
+		require.NotNil(t, err, name)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code, name)
+	}
+}
+
+func TestControl_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paused = false
+
+		mockRegistrar = &mockRegistrar{
+			pauseFn: func() {
+				paused = true
+			},
+			resumeFn: func() {
+				paused = false
+			},
+			pausedFn: func() bool {
+				return paused
+			},
+		}
+	)
+
+	h := NewHandler(mockRegistrar)
+
+	response, err := h.PauseHandler(nil, []any{})
+	require.Nil(t, err)
+	assert.Equal(t, &Status{Paused: true}, response)
+
+	response, err = h.StatusHandler(nil, []any{})
+	require.Nil(t, err)
+	assert.Equal(t, &Status{Paused: true}, response)
+
+	response, err = h.ResumeHandler(nil, []any{})
+	require.Nil(t, err)
+	assert.Equal(t, &Status{Paused: false}, response)
+}
@@ -0,0 +1,82 @@
+package admin
+
+import "github.com/gnolang/tx-indexer/ledger"
+
+type (
+	listPendingDelegate func() ([]*ledger.Token, error)
+	tokenActionDelegate func(string) (*ledger.Token, error)
+	rejectDelegate      func(string, string) (*ledger.Token, error)
+	controlDelegate     func()
+	pausedDelegate      func() bool
+)
+
+type mockRegistrar struct {
+	listPendingFn listPendingDelegate
+	approveFn     tokenActionDelegate
+	rejectFn      rejectDelegate
+	retryFn       tokenActionDelegate
+	requeueFn     tokenActionDelegate
+	pauseFn       controlDelegate
+	resumeFn      controlDelegate
+	pausedFn      pausedDelegate
+}
+
+func (m *mockRegistrar) ListPending() ([]*ledger.Token, error) {
+	if m.listPendingFn != nil {
+		return m.listPendingFn()
+	}
+
+	return nil, nil
+}
+
+func (m *mockRegistrar) Approve(pkgPath string) (*ledger.Token, error) {
+	if m.approveFn != nil {
+		return m.approveFn(pkgPath)
+	}
+
+	return nil, nil
+}
+
+func (m *mockRegistrar) Reject(pkgPath, reason string) (*ledger.Token, error) {
+	if m.rejectFn != nil {
+		return m.rejectFn(pkgPath, reason)
+	}
+
+	return nil, nil
+}
+
+func (m *mockRegistrar) Retry(pkgPath string) (*ledger.Token, error) {
+	if m.retryFn != nil {
+		return m.retryFn(pkgPath)
+	}
+
+	return nil, nil
+}
+
+func (m *mockRegistrar) Requeue(pkgPath string) (*ledger.Token, error) {
+	if m.requeueFn != nil {
+		return m.requeueFn(pkgPath)
+	}
+
+	return nil, nil
+}
+
+func (m *mockRegistrar) Pause() {
+	if m.pauseFn != nil {
+		m.pauseFn()
+	}
+}
+
+func (m *mockRegistrar) Resume() {
+	if m.resumeFn != nil {
+		m.resumeFn()
+	}
+}
+
+func (m *mockRegistrar) Paused() bool {
+	if m.pausedFn != nil {
+		return m.pausedFn()
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package admin
+
+import "github.com/gnolang/tx-indexer/ledger"
+
+type Registrar interface {
+	// ListPending returns the tokens pending a manual approval
+	ListPending() ([]*ledger.Token, error)
+
+	// Approve approves the pending token, and adds it to the registration queue
+	Approve(pkgPath string) (*ledger.Token, error)
+
+	// Reject rejects the pending (or queued) token, with the given reason
+	Reject(pkgPath, reason string) (*ledger.Token, error)
+
+	// Retry retries the registration of the failed token
+	Retry(pkgPath string) (*ledger.Token, error)
+
+	// Requeue sends the token through the registration pipeline again
+	Requeue(pkgPath string) (*ledger.Token, error)
+
+	// Pause pauses the registrations, without stopping the detection
+	Pause()
+
+	// Resume resumes the paused registrations
+	Resume()
+
+	// Paused checks if the registrations are paused
+	Paused() bool
+}
+
+// Status is the registrar runtime status
+type Status struct {
+	Paused bool `json:"paused"`
+}
@@ -0,0 +1,21 @@
+package spend
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/budget"
+)
+
+type summaryDelegate func(time.Time) budget.Summary
+
+type mockTracker struct {
+	summaryFn summaryDelegate
+}
+
+func (m *mockTracker) Summary(now time.Time) budget.Summary {
+	if m.summaryFn != nil {
+		return m.summaryFn(now)
+	}
+
+	return budget.Summary{}
+}
@@ -0,0 +1,32 @@
+package spend
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+type Handler struct {
+	tracker Tracker
+}
+
+func NewHandler(tracker Tracker) *Handler {
+	return &Handler{
+		tracker: tracker,
+	}
+}
+
+// GetSpendingHandler returns the registrar
+// spend accounting and budget limits
+func (h *Handler) GetSpendingHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 0 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	return h.tracker.Summary(time.Now()), nil
+}
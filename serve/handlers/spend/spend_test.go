@@ -0,0 +1,56 @@
+package spend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/budget"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetSpending_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockTracker{})
+
+	response, err := h.GetSpendingHandler(nil, []any{1})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+}
+
+func TestGetSpending_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		summary = budget.Summary{
+			Limits: budget.Config{
+				Hourly: 10_000_000,
+			},
+			PerDeployer: map[string]int64{
+				"g1alice": 1_000_000,
+			},
+			Hourly: 1_000_000,
+			Daily:  1_000_000,
+			Total:  5_000_000,
+		}
+
+		mockTracker = &mockTracker{
+			summaryFn: func(_ time.Time) budget.Summary {
+				return summary
+			},
+		}
+	)
+
+	h := NewHandler(mockTracker)
+
+	response, err := h.GetSpendingHandler(nil, []any{})
+	require.Nil(t, err)
+
+	assert.Equal(t, summary, response)
+}
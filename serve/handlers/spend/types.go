@@ -0,0 +1,12 @@
+package spend
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/budget"
+)
+
+type Tracker interface {
+	// Summary returns the spend accounting overview, at the given time
+	Summary(time.Time) budget.Summary
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/gnolang/tx-indexer/events"
 	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/ledger"
 	"github.com/gnolang/tx-indexer/serve/conns"
 	"github.com/gnolang/tx-indexer/serve/filters"
 	"github.com/gnolang/tx-indexer/serve/filters/filter"
@@ -503,6 +504,83 @@ func TestSubscribe_Valid(t *testing.T) {
 	}
 }
 
+func TestSubscribe_BudgetExceeded(t *testing.T) {
+	t.Parallel()
+
+	var (
+		eventsCh = make(chan events.Event)
+
+		connID   = "connection ID"
+		metadata = &metadata.Metadata{
+			WebSocketID: &connID,
+		}
+
+		mockEvents = &mock.Events{
+			SubscribeFn: func(eventTypes []events.Type) *events.Subscription {
+				require.Contains(t, eventTypes, indexerTypes.BudgetExceededEvent)
+
+				return &events.Subscription{
+					ID:    events.SubscriptionID(1),
+					SubCh: eventsCh,
+				}
+			},
+		}
+
+		written  = make(chan any, 1)
+		mockConn = &mock.Conn{
+			WriteDataFn: func(data any) error {
+				written <- data
+
+				return nil
+			},
+		}
+		mockConnFetcher = &mockConnectionFetcher{
+			getWSConnectionFn: func(_ string) conns.WSConnection {
+				return mockConn
+			},
+		}
+
+		alert = &indexerTypes.BudgetExceeded{
+			Token:  &ledger.Token{PkgPath: "gno.land/r/demo/foo"},
+			Budget: "daily",
+			Limit:  10,
+			Spent:  10,
+			Amount: 1,
+		}
+	)
+
+	fm := filters.NewFilterManager(
+		context.Background(),
+		&mock.Storage{},
+		mockEvents,
+	)
+
+	h := NewHandler(fm, mockConnFetcher)
+
+	responseRaw, subscribeErr := h.SubscribeHandler(metadata, []any{
+		subscription.BudgetExceededEvent,
+	})
+	require.Nil(t, subscribeErr)
+
+	id, ok := responseRaw.(string)
+	require.True(t, ok)
+
+	// Signal the budget alert
+	select {
+	case eventsCh <- alert:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+
+	// Make sure the alert was written to the subscription
+	select {
+	case data := <-written:
+		assert.Equal(t, spec.NewJSONSubscribeResponse(id, alert), data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+}
+
 func TestSubscribeUnsubscribe_InvalidParams(t *testing.T) {
 	t.Parallel()
 
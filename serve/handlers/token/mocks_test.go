@@ -0,0 +1,29 @@
+package token
+
+import "github.com/gnolang/tx-indexer/ledger"
+
+type (
+	getTokenDelegate           func(string) (*ledger.Token, error)
+	getRegistrationTxsDelegate func(string) ([]*ledger.RegistrationTx, error)
+)
+
+type mockStorage struct {
+	getTokenFn           getTokenDelegate
+	getRegistrationTxsFn getRegistrationTxsDelegate
+}
+
+func (m *mockStorage) GetToken(pkgPath string) (*ledger.Token, error) {
+	if m.getTokenFn != nil {
+		return m.getTokenFn(pkgPath)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetRegistrationTxs(tokenID string) ([]*ledger.RegistrationTx, error) {
+	if m.getRegistrationTxsFn != nil {
+		return m.getRegistrationTxsFn(tokenID)
+	}
+
+	return nil, nil
+}
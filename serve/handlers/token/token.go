@@ -0,0 +1,74 @@
+package token
+
+import (
+	"errors"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetTokenHandler returns the token ledger record, with its deploy tx
+// and the registration txs linked to it by their memo, if any
+func (h *Handler) GetTokenHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	pkgPath, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	// Run the handler
+	response, err := h.getToken(pkgPath)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	if response == nil {
+		return nil, nil
+	}
+
+	return response, nil
+}
+
+// getToken fetches the token and its registration txs from storage, if any
+func (h *Handler) getToken(pkgPath string) (*Response, error) {
+	token, err := h.storage.GetToken(pkgPath)
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		//nolint:nilnil // This is a special case
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Response{
+		Token:           token,
+		RegistrationTxs: make([]*ledger.RegistrationTx, 0),
+	}
+
+	if response.RegistrationTxs, err = h.storage.GetRegistrationTxs(token.PkgPath); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
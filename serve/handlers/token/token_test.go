@@ -0,0 +1,104 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+func TestGetToken_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid package path",
+			[]any{1},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTokenHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetToken_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("token not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTokenFn: func(_ string) (*ledger.Token, error) {
+				return nil, storageErrors.ErrNotFound
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTokenHandler(nil, []any{"gno.land/r/demo/foo"})
+		require.Nil(t, err)
+
+		assert.Nil(t, response)
+	})
+
+	t.Run("token with registration txs", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			token = &ledger.Token{
+				PkgPath:      "gno.land/r/demo/foo",
+				DeployTxHash: "deploy hash",
+				Status:       ledger.StatusRegistered,
+			}
+
+			txs = []*ledger.RegistrationTx{
+				{TxHash: "register hash", TokenID: "gno.land/r/demo/foo", Height: 10},
+			}
+
+			mockStorage = &mockStorage{
+				getTokenFn: func(pkgPath string) (*ledger.Token, error) {
+					require.Equal(t, token.PkgPath, pkgPath)
+
+					return token, nil
+				},
+				getRegistrationTxsFn: func(tokenID string) ([]*ledger.RegistrationTx, error) {
+					require.Equal(t, token.PkgPath, tokenID)
+
+					return txs, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTokenHandler(nil, []any{token.PkgPath})
+		require.Nil(t, err)
+
+		assert.Equal(t, &Response{Token: token, RegistrationTxs: txs}, response)
+	})
+}
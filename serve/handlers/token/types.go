@@ -0,0 +1,20 @@
+package token
+
+import "github.com/gnolang/tx-indexer/ledger"
+
+type Storage interface {
+	// GetToken fetches the token ledger record using the token package path
+	GetToken(pkgPath string) (*ledger.Token, error)
+
+	// GetRegistrationTxs fetches the registration txs
+	// linked to the token, ordered by height
+	GetRegistrationTxs(tokenID string) ([]*ledger.RegistrationTx, error)
+}
+
+// Response is the token ledger record,
+// alongside its linked registration txs
+type Response struct {
+	*ledger.Token
+
+	RegistrationTxs []*ledger.RegistrationTx `json:"registrationTxs"`
+}
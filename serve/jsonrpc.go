@@ -15,8 +15,11 @@ import (
 	"github.com/gnolang/tx-indexer/serve/conns"
 	"github.com/gnolang/tx-indexer/serve/conns/wsconn"
 	"github.com/gnolang/tx-indexer/serve/filters"
+	"github.com/gnolang/tx-indexer/serve/handlers/admin"
 	"github.com/gnolang/tx-indexer/serve/handlers/block"
+	"github.com/gnolang/tx-indexer/serve/handlers/spend"
 	"github.com/gnolang/tx-indexer/serve/handlers/subs"
+	"github.com/gnolang/tx-indexer/serve/handlers/token"
 	"github.com/gnolang/tx-indexer/serve/handlers/tx"
 	"github.com/gnolang/tx-indexer/serve/metadata"
 	"github.com/gnolang/tx-indexer/serve/spec"
@@ -137,6 +140,72 @@ func (j *JSONRPC) RegisterBlockEndpoints(db block.Storage) {
 	)
 }
 
+// RegisterAdminEndpoints registers the registrar admin endpoints.
+// The admin endpoints should only be served behind the auth middleware
+func (j *JSONRPC) RegisterAdminEndpoints(registrar admin.Registrar) {
+	adminHandler := admin.NewHandler(registrar)
+
+	j.RegisterHandler(
+		"admin_listPending",
+		adminHandler.ListPendingHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_approve",
+		adminHandler.ApproveHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_reject",
+		adminHandler.RejectHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_retry",
+		adminHandler.RetryHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_requeue",
+		adminHandler.RequeueHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_pause",
+		adminHandler.PauseHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_resume",
+		adminHandler.ResumeHandler,
+	)
+
+	j.RegisterHandler(
+		"admin_status",
+		adminHandler.StatusHandler,
+	)
+}
+
+// RegisterTokenEndpoints registers the token ledger endpoints
+func (j *JSONRPC) RegisterTokenEndpoints(db token.Storage) {
+	tokenHandler := token.NewHandler(db)
+
+	j.RegisterHandler(
+		"getToken",
+		tokenHandler.GetTokenHandler,
+	)
+}
+
+// RegisterSpendEndpoints registers the registrar spend accounting endpoints
+func (j *JSONRPC) RegisterSpendEndpoints(tracker spend.Tracker) {
+	spendHandler := spend.NewHandler(tracker)
+
+	j.RegisterHandler(
+		"getSpending",
+		spendHandler.GetSpendingHandler,
+	)
+}
+
 func (j *JSONRPC) RegisterSubEndpoints(db storage.Storage) {
 	fm := filters.NewFilterManager(context.Background(), db, j.events)
 
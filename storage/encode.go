@@ -3,12 +3,16 @@ package storage
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"unsafe"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/pkg/errors"
+
+	"github.com/gnolang/tx-indexer/ledger"
 )
 
 const (
@@ -256,3 +260,83 @@ func decodeTx(encodedTx []byte) (*types.TxResult, error) {
 
 	return &tx, nil
 }
+
+// encodeToken encodes the token ledger record in JSON
+func encodeToken(token *ledger.Token) ([]byte, error) {
+	return json.Marshal(token)
+}
+
+// decodeToken decodes the JSON encoded token ledger record
+func decodeToken(encodedToken []byte) (*ledger.Token, error) {
+	var token ledger.Token
+
+	if err := json.Unmarshal(encodedToken, &token); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON token, %w", err)
+	}
+
+	return &token, nil
+}
+
+// encodeNotification encodes the webhook notification in JSON
+func encodeNotification(notification *ledger.Notification) ([]byte, error) {
+	return json.Marshal(notification)
+}
+
+// decodeNotification decodes the JSON encoded webhook notification
+func decodeNotification(encodedNotification []byte) (*ledger.Notification, error) {
+	var notification ledger.Notification
+
+	if err := json.Unmarshal(encodedNotification, &notification); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON notification, %w", err)
+	}
+
+	return &notification, nil
+}
+
+// encodeSpend encodes the spend record in JSON
+func encodeSpend(spend *ledger.Spend) ([]byte, error) {
+	return json.Marshal(spend)
+}
+
+// decodeSpend decodes the JSON encoded spend record
+func decodeSpend(encodedSpend []byte) (*ledger.Spend, error) {
+	var spend ledger.Spend
+
+	if err := json.Unmarshal(encodedSpend, &spend); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON spend, %w", err)
+	}
+
+	return &spend, nil
+}
+
+// encodeRegistrationTx encodes the registration tx record into JSON
+func encodeRegistrationTx(tx *ledger.RegistrationTx) ([]byte, error) {
+	return json.Marshal(tx)
+}
+
+// decodeRegistrationTx decodes the JSON encoded registration tx record
+func decodeRegistrationTx(encodedTx []byte) (*ledger.RegistrationTx, error) {
+	var tx ledger.RegistrationTx
+
+	if err := json.Unmarshal(encodedTx, &tx); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON registration tx, %w", err)
+	}
+
+	return &tx, nil
+}
+
+// encodePackage encodes the deployed package in Amino binary
+func encodePackage(pkg *std.MemPackage) ([]byte, error) {
+	return amino.Marshal(pkg)
+}
+
+// decodePackage decodes the Amino encoded deployed package
+func decodePackage(encodedPackage []byte) (*std.MemPackage, error) {
+	var pkg std.MemPackage
+
+	if err := amino.Unmarshal(encodedPackage, &pkg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal Amino package, %w", err)
+	}
+
+	return &pkg, nil
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/cockroachdb/pebble"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"go.uber.org/multierr"
 
+	"github.com/gnolang/tx-indexer/ledger"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
@@ -18,6 +20,14 @@ const (
 	// for the latest height saved in the DB
 	keyLatestHeight = "/meta/lh"
 
+	// keyRegistryVersion is the lookup key
+	// for the active registry version
+	keyRegistryVersion = "/meta/rv"
+
+	// keyGenesisImported is the marker key
+	// of the imported genesis
+	keyGenesisImported = "/meta/gi"
+
 	// prefixKeyBlocks is the key for each block saved. They are stored by height
 	prefixKeyBlocks = "/data/blocks/"
 
@@ -26,6 +36,28 @@ const (
 
 	// prefixKeyTxByHash is a secondary index to query transaction by hash
 	prefixKeyTxByHash = "/index/txh/"
+
+	// prefixKeyTokens is the prefix for each token ledger record. They are stored by package path
+	prefixKeyTokens = "/data/tokens/"
+
+	// prefixKeySpends is the prefix for each registrar spend record. They are stored by time
+	prefixKeySpends = "/data/spends/"
+
+	// prefixKeyPackages is the prefix for each deployed package. They are stored by package path
+	prefixKeyPackages = "/data/packages/"
+
+	// prefixKeyIntents is the prefix for each registration intent. They are stored by package path
+	prefixKeyIntents = "/data/intents/"
+
+	// prefixKeyRegistrationTxs is the prefix for each registration tx linked
+	// to a token. They are stored by token ID (package path), and by height
+	prefixKeyRegistrationTxs = "/data/regtxs/"
+
+	// prefixKeyNotifications is the prefix for each undelivered webhook notification. They are stored by ID
+	prefixKeyNotifications = "/data/outbox/"
+
+	// prefixKeyCheckpoints is the prefix for each block processor checkpoint. They are stored by name
+	prefixKeyCheckpoints = "/meta/cp/"
 )
 
 func keyTx(blockNum uint64, txIndex uint32) []byte {
@@ -45,6 +77,89 @@ func keyHashTx(hash string) []byte {
 	return key
 }
 
+func keyToken(pkgPath string) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeyTokens)
+	key = encodeStringAscending(key, pkgPath)
+
+	return key
+}
+
+func keyPackage(pkgPath string) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeyPackages)
+	key = encodeStringAscending(key, pkgPath)
+
+	return key
+}
+
+func keyIntent(pkgPath string) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeyIntents)
+	key = encodeStringAscending(key, pkgPath)
+
+	return key
+}
+
+func keyRegistrationTxs(tokenID string) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeyRegistrationTxs)
+	key = encodeStringAscending(key, tokenID)
+
+	return key
+}
+
+func keyRegistrationTx(tx *ledger.RegistrationTx) []byte {
+	key := keyRegistrationTxs(tx.TokenID)
+	key = encodeUint64Ascending(key, uint64(tx.Height))
+	key = encodeUint32Ascending(key, tx.Index)
+
+	return key
+}
+
+func keyNotification(id uint64) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeyNotifications)
+	key = encodeUint64Ascending(key, id)
+
+	return key
+}
+
+func keyCheckpoint(name string) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeyCheckpoints)
+	key = encodeStringAscending(key, name)
+
+	return key
+}
+
+func keySpend(spend *ledger.Spend) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeySpends)
+	key = encodeUint64Ascending(key, uint64(spend.Time.UnixNano()))
+	key = encodeStringAscending(key, spend.TxHash)
+	key = encodeStringAscending(key, spend.PkgPath)
+
+	return key
+}
+
+// keyUpperBound returns the smallest key that is greater
+// than all the keys with the given prefix
+func keyUpperBound(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+
+	return nil // no upper bound
+}
+
 func keyBlock(blockNum uint64) []byte {
 	var key []byte
 	key = encodeStringAscending(key, prefixKeyBlocks)
@@ -93,6 +208,57 @@ func (s *Pebble) GetLatestHeight() (uint64, error) {
 	return val, err
 }
 
+// GetRegistryVersion fetches the active registry version from storage, if any
+func (s *Pebble) GetRegistryVersion() (string, error) {
+	version, c, err := s.db.Get([]byte(keyRegistryVersion))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return "", storageErrors.ErrNotFound
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	defer c.Close()
+
+	return string(version), nil
+}
+
+// GetGenesisImported checks if the genesis import marker is saved
+func (s *Pebble) GetGenesisImported() (bool, error) {
+	_, c, err := s.db.Get([]byte(keyGenesisImported))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	defer c.Close()
+
+	return true, nil
+}
+
+// GetCheckpoint fetches the latest height processed
+// by the given block processor from storage, if any
+func (s *Pebble) GetCheckpoint(name string) (uint64, error) {
+	height, c, err := s.db.Get(keyCheckpoint(name))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, storageErrors.ErrNotFound
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer c.Close()
+
+	_, val, err := decodeUint64Ascending(height)
+
+	return val, err
+}
+
 // GetBlock fetches the specified block from storage, if any
 func (s *Pebble) GetBlock(blockNum uint64) (*types.Block, error) {
 	block, c, err := s.db.Get(keyBlock(blockNum))
@@ -153,6 +319,173 @@ func (s *Pebble) GetTxByHash(txHash string) (*types.TxResult, error) {
 	return decodeTx(tx)
 }
 
+// GetToken fetches the specified token ledger record from storage, if any
+func (s *Pebble) GetToken(pkgPath string) (*ledger.Token, error) {
+	token, c, err := s.db.Get(keyToken(pkgPath))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, storageErrors.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close()
+
+	return decodeToken(token)
+}
+
+// GetPackage fetches the specified deployed package from storage, if any
+func (s *Pebble) GetPackage(pkgPath string) (*std.MemPackage, error) {
+	pkg, c, err := s.db.Get(keyPackage(pkgPath))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, storageErrors.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close()
+
+	return decodePackage(pkg)
+}
+
+// GetTokens fetches all the token ledger records from storage
+func (s *Pebble) GetTokens() ([]*ledger.Token, error) {
+	var prefix []byte
+	prefix = encodeStringAscending(prefix, prefixKeyTokens)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: keyUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*ledger.Token, 0)
+
+	for it.First(); it.Valid(); it.Next() {
+		token, err := decodeToken(it.Value())
+		if err != nil {
+			return nil, multierr.Append(err, it.Close())
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, multierr.Append(it.Error(), it.Close())
+}
+
+// GetIntents fetches all the registration intents from storage, ordered by package path
+func (s *Pebble) GetIntents() ([]*ledger.Token, error) {
+	var prefix []byte
+	prefix = encodeStringAscending(prefix, prefixKeyIntents)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: keyUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	intents := make([]*ledger.Token, 0)
+
+	for it.First(); it.Valid(); it.Next() {
+		intent, err := decodeToken(it.Value())
+		if err != nil {
+			return nil, multierr.Append(err, it.Close())
+		}
+
+		intents = append(intents, intent)
+	}
+
+	return intents, multierr.Append(it.Error(), it.Close())
+}
+
+// GetNotifications fetches all the undelivered webhook notifications from storage, ordered by ID
+func (s *Pebble) GetNotifications() ([]*ledger.Notification, error) {
+	var prefix []byte
+	prefix = encodeStringAscending(prefix, prefixKeyNotifications)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: keyUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*ledger.Notification, 0)
+
+	for it.First(); it.Valid(); it.Next() {
+		notification, err := decodeNotification(it.Value())
+		if err != nil {
+			return nil, multierr.Append(err, it.Close())
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, multierr.Append(it.Error(), it.Close())
+}
+
+// GetRegistrationTxs fetches the registration txs linked
+// to the token from storage, ordered by height
+func (s *Pebble) GetRegistrationTxs(tokenID string) ([]*ledger.RegistrationTx, error) {
+	prefix := keyRegistrationTxs(tokenID)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: keyUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*ledger.RegistrationTx, 0)
+
+	for it.First(); it.Valid(); it.Next() {
+		tx, err := decodeRegistrationTx(it.Value())
+		if err != nil {
+			return nil, multierr.Append(err, it.Close())
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, multierr.Append(it.Error(), it.Close())
+}
+
+// GetSpends fetches all the registrar spend records from storage, ordered by time
+func (s *Pebble) GetSpends() ([]*ledger.Spend, error) {
+	var prefix []byte
+	prefix = encodeStringAscending(prefix, prefixKeySpends)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: keyUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spends := make([]*ledger.Spend, 0)
+
+	for it.First(); it.Valid(); it.Next() {
+		spend, err := decodeSpend(it.Value())
+		if err != nil {
+			return nil, multierr.Append(err, it.Close())
+		}
+
+		spends = append(spends, spend)
+	}
+
+	return spends, multierr.Append(it.Error(), it.Close())
+}
+
 func (s *Pebble) BlockIterator(fromBlockNum, toBlockNum uint64) (Iterator[*types.Block], error) {
 	fromKey := keyBlock(fromBlockNum)
 
@@ -328,6 +661,21 @@ func (b *PebbleBatch) SetLatestHeight(h uint64) error {
 	return b.b.Set([]byte(keyLatestHeight), val, pebble.NoSync)
 }
 
+func (b *PebbleBatch) SetRegistryVersion(version string) error {
+	return b.b.Set([]byte(keyRegistryVersion), []byte(version), pebble.NoSync)
+}
+
+func (b *PebbleBatch) SetGenesisImported() error {
+	return b.b.Set([]byte(keyGenesisImported), []byte{1}, pebble.NoSync)
+}
+
+func (b *PebbleBatch) SetCheckpoint(name string, height uint64) error {
+	var val []byte
+	val = encodeUint64Ascending(val, height)
+
+	return b.b.Set(keyCheckpoint(name), val, pebble.NoSync)
+}
+
 func (b *PebbleBatch) SetBlock(block *types.Block) error {
 	eb, err := encodeBlock(block)
 	if err != nil {
@@ -364,6 +712,92 @@ func (b *PebbleBatch) SetTx(tx *types.TxResult) error {
 	)
 }
 
+func (b *PebbleBatch) SetToken(token *ledger.Token) error {
+	encodedToken, err := encodeToken(token)
+	if err != nil {
+		return err
+	}
+
+	return b.b.Set(
+		keyToken(token.PkgPath),
+		encodedToken,
+		pebble.NoSync,
+	)
+}
+
+func (b *PebbleBatch) SetIntent(token *ledger.Token) error {
+	encodedToken, err := encodeToken(token)
+	if err != nil {
+		return err
+	}
+
+	return b.b.Set(
+		keyIntent(token.PkgPath),
+		encodedToken,
+		pebble.NoSync,
+	)
+}
+
+func (b *PebbleBatch) DeleteIntent(pkgPath string) error {
+	return b.b.Delete(keyIntent(pkgPath), pebble.NoSync)
+}
+
+func (b *PebbleBatch) SetRegistrationTx(tx *ledger.RegistrationTx) error {
+	encodedTx, err := encodeRegistrationTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return b.b.Set(
+		keyRegistrationTx(tx),
+		encodedTx,
+		pebble.NoSync,
+	)
+}
+
+func (b *PebbleBatch) SetNotification(notification *ledger.Notification) error {
+	encodedNotification, err := encodeNotification(notification)
+	if err != nil {
+		return err
+	}
+
+	return b.b.Set(
+		keyNotification(notification.ID),
+		encodedNotification,
+		pebble.NoSync,
+	)
+}
+
+func (b *PebbleBatch) DeleteNotification(id uint64) error {
+	return b.b.Delete(keyNotification(id), pebble.NoSync)
+}
+
+func (b *PebbleBatch) SetSpend(spend *ledger.Spend) error {
+	encodedSpend, err := encodeSpend(spend)
+	if err != nil {
+		return err
+	}
+
+	return b.b.Set(
+		keySpend(spend),
+		encodedSpend,
+		pebble.NoSync,
+	)
+}
+
+func (b *PebbleBatch) SetPackage(pkg *std.MemPackage) error {
+	encodedPackage, err := encodePackage(pkg)
+	if err != nil {
+		return err
+	}
+
+	return b.b.Set(
+		keyPackage(pkg.Path),
+		encodedPackage,
+		pebble.NoSync,
+	)
+}
+
 func (b *PebbleBatch) Commit() error {
 	return b.b.Commit(pebble.Sync)
 }
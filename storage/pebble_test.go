@@ -3,6 +3,7 @@ package storage
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/gnolang/tx-indexer/ledger"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
@@ -107,6 +109,103 @@ func TestStorage_Tx(t *testing.T) {
 	}
 }
 
+func TestStorage_Token(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Make sure no token exists
+	_, err = s.GetToken("gno.land/r/demo/foo")
+	require.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	tokens := []*ledger.Token{
+		{
+			PkgPath: "gno.land/r/demo/bar",
+			Symbol:  "BAR",
+			Status:  ledger.StatusRegistered,
+		},
+		{
+			PkgPath: "gno.land/r/demo/foo",
+			Symbol:  "FOO",
+			Status:  ledger.StatusSimulationFailed,
+			Error:   "undefined: token.Approve",
+		},
+	}
+
+	// Save the tokens, alongside other data
+	wb := s.WriteBatch()
+
+	for _, token := range tokens {
+		require.NoError(t, wb.SetToken(token))
+	}
+
+	require.NoError(t, wb.SetLatestHeight(10))
+	require.NoError(t, wb.Commit())
+
+	for _, token := range tokens {
+		savedToken, err := s.GetToken(token.PkgPath)
+		require.NoError(t, err)
+		assert.Equal(t, token, savedToken)
+	}
+
+	savedTokens, err := s.GetTokens()
+	require.NoError(t, err)
+	assert.Equal(t, tokens, savedTokens)
+}
+
+func TestStorage_Spend(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	now := time.Now().UTC()
+
+	spends := []*ledger.Spend{
+		{
+			Time:    now.Add(-time.Hour),
+			PkgPath: "gno.land/r/demo/foo",
+			TxHash:  "hash 1",
+			Denom:   "ugnot",
+			Amount:  1_000_000,
+		},
+		{
+			Time:    now,
+			PkgPath: "gno.land/r/demo/bar",
+			TxHash:  "hash 2",
+			Denom:   "ugnot",
+			Amount:  2_000_000,
+		},
+	}
+
+	// Save the spends out of order
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetSpend(spends[1]))
+	require.NoError(t, wb.SetSpend(spends[0]))
+	require.NoError(t, wb.Commit())
+
+	savedSpends, err := s.GetSpends()
+	require.NoError(t, err)
+
+	require.Len(t, savedSpends, len(spends))
+
+	for i, spend := range spends {
+		assert.True(t, spend.Time.Equal(savedSpends[i].Time))
+		assert.Equal(t, spend.TxHash, savedSpends[i].TxHash)
+		assert.Equal(t, spend.Amount, savedSpends[i].Amount)
+	}
+}
+
 func TestStorageIters(t *testing.T) {
 	t.Parallel()
 
@@ -235,3 +334,246 @@ func generateRandomTxs(t *testing.T, count int) []*types.TxResult {
 
 	return txs
 }
+
+func TestStorage_Package(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Make sure no package exists
+	_, err = s.GetPackage("gno.land/r/demo/foo")
+	require.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	pkg := &std.MemPackage{
+		Name: "foo",
+		Path: "gno.land/r/demo/foo",
+		Files: []*std.MemFile{
+			{
+				Name: "foo.gno",
+				Body: "package foo\n\nfunc Foo() string { return \"foo\" }\n",
+			},
+		},
+	}
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetPackage(pkg))
+	require.NoError(t, wb.Commit())
+
+	savedPackage, err := s.GetPackage(pkg.Path)
+	require.NoError(t, err)
+	assert.Equal(t, pkg, savedPackage)
+}
+
+func TestStorage_RegistryVersion(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Make sure no version is active
+	_, err = s.GetRegistryVersion()
+	require.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetRegistryVersion("v2"))
+	require.NoError(t, wb.Commit())
+
+	version, err := s.GetRegistryVersion()
+	require.NoError(t, err)
+
+	assert.Equal(t, "v2", version)
+}
+
+func TestStorage_GenesisImported(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Make sure the genesis is not imported
+	imported, err := s.GetGenesisImported()
+	require.NoError(t, err)
+
+	assert.False(t, imported)
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetGenesisImported())
+	require.NoError(t, wb.Commit())
+
+	imported, err = s.GetGenesisImported()
+	require.NoError(t, err)
+
+	assert.True(t, imported)
+}
+
+func TestStorage_Checkpoint(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Make sure no checkpoint is saved
+	_, err = s.GetCheckpoint("stats")
+	require.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, wb.SetCheckpoint("stats", 10))
+	require.NoError(t, wb.SetCheckpoint("audit", 5))
+	require.NoError(t, wb.Commit())
+
+	// Make sure the checkpoints are kept per processor
+	height, err := s.GetCheckpoint("stats")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(10), height)
+
+	height, err = s.GetCheckpoint("audit")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), height)
+}
+
+func TestStorage_Intents(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	intents := []*ledger.Token{
+		{PkgPath: "gno.land/r/demo/bar", Height: 2},
+		{PkgPath: "gno.land/r/demo/foo", Height: 1},
+	}
+
+	wb := s.WriteBatch()
+
+	for _, intent := range intents {
+		require.NoError(t, wb.SetIntent(intent))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	saved, err := s.GetIntents()
+	require.NoError(t, err)
+
+	assert.Equal(t, intents, saved)
+
+	// Make sure the consumed intents are removed
+	wb = s.WriteBatch()
+
+	require.NoError(t, wb.DeleteIntent("gno.land/r/demo/bar"))
+	require.NoError(t, wb.Commit())
+
+	saved, err = s.GetIntents()
+	require.NoError(t, err)
+
+	assert.Equal(t, intents[1:], saved)
+}
+
+func TestStorage_RegistrationTxs(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	txs := []*ledger.RegistrationTx{
+		{TxHash: "register 2", TokenID: "gno.land/r/demo/foo", Height: 20, Index: 1},
+		{TxHash: "register 1", TokenID: "gno.land/r/demo/foo", Height: 10},
+		{TxHash: "register 3", TokenID: "gno.land/r/demo/foo.key", Height: 15},
+	}
+
+	wb := s.WriteBatch()
+
+	for _, tx := range txs {
+		require.NoError(t, wb.SetRegistrationTx(tx))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	// Make sure only the txs linked to the token are fetched, ordered by height
+	saved, err := s.GetRegistrationTxs("gno.land/r/demo/foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, []*ledger.RegistrationTx{txs[1], txs[0]}, saved)
+
+	saved, err = s.GetRegistrationTxs("missing")
+	require.NoError(t, err)
+
+	assert.Empty(t, saved)
+}
+
+func TestStorage_Notifications(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	notifications := []*ledger.Notification{
+		{ID: 300, Endpoint: "http://a", Event: ledger.EventConfirmed},
+		{ID: 2, Endpoint: "http://a", Event: ledger.EventDetected},
+		{ID: 10, Endpoint: "http://b", Event: ledger.EventSubmitted, Attempts: 1},
+	}
+
+	wb := s.WriteBatch()
+
+	for _, notification := range notifications {
+		require.NoError(t, wb.SetNotification(notification))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	// Make sure the notifications are fetched ordered by ID
+	saved, err := s.GetNotifications()
+	require.NoError(t, err)
+
+	require.Len(t, saved, 3)
+
+	for i, id := range []uint64{2, 10, 300} {
+		assert.Equal(t, id, saved[i].ID)
+	}
+
+	// Make sure the delivered notifications are removed
+	wb = s.WriteBatch()
+
+	require.NoError(t, wb.DeleteNotification(10))
+	require.NoError(t, wb.Commit())
+
+	saved, err = s.GetNotifications()
+	require.NoError(t, err)
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, uint64(2), saved[0].ID)
+	assert.Equal(t, uint64(300), saved[1].ID)
+}
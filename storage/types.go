@@ -4,6 +4,9 @@ import (
 	"io"
 
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/ledger"
 )
 
 // Storage represents the permanent storage abstraction
@@ -34,6 +37,39 @@ type Reader interface {
 	// TxIterator iterates over transactions, limiting the results to be between the provided block numbers
 	// and transaction indexes
 	TxIterator(fromBlockNum, toBlockNum uint64, fromTxIndex, toTxIndex uint32) (Iterator[*types.TxResult], error)
+
+	// GetToken fetches the token ledger record using the token package path
+	GetToken(pkgPath string) (*ledger.Token, error)
+
+	// GetTokens fetches all the token ledger records, ordered by package path
+	GetTokens() ([]*ledger.Token, error)
+
+	// GetPackage fetches the deployed package using its package path
+	GetPackage(pkgPath string) (*std.MemPackage, error)
+
+	// GetRegistryVersion returns the active registry version from the storage
+	GetRegistryVersion() (string, error)
+
+	// GetGenesisImported returns a flag indicating if the genesis is imported
+	GetGenesisImported() (bool, error)
+
+	// GetCheckpoint returns the latest height processed
+	// by the given block processor from the storage
+	GetCheckpoint(name string) (uint64, error)
+
+	// GetIntents fetches all the registration intents, ordered by package path
+	GetIntents() ([]*ledger.Token, error)
+
+	// GetRegistrationTxs fetches the registration txs
+	// linked to the token, ordered by height
+	GetRegistrationTxs(tokenID string) ([]*ledger.RegistrationTx, error)
+
+	// GetSpends fetches all the registrar spend records, ordered by time
+	GetSpends() ([]*ledger.Spend, error)
+
+	// GetNotifications fetches all the undelivered
+	// webhook notifications, ordered by ID
+	GetNotifications() ([]*ledger.Notification, error)
 }
 
 type Iterator[T any] interface {
@@ -58,6 +94,29 @@ type Batch interface {
 	SetBlock(block *types.Block) error
 	// SetTx saves the transaction to the permanent storage
 	SetTx(tx *types.TxResult) error
+	// SetToken saves the token ledger record to the permanent storage
+	SetToken(token *ledger.Token) error
+	// SetIntent saves the registration intent of the detected token
+	SetIntent(token *ledger.Token) error
+	// DeleteIntent removes the consumed registration intent, if any
+	DeleteIntent(pkgPath string) error
+	// SetRegistrationTx saves the registration tx linked to the token
+	SetRegistrationTx(tx *ledger.RegistrationTx) error
+	// SetSpend saves the registrar spend record to the permanent storage
+	SetSpend(spend *ledger.Spend) error
+	// SetNotification saves the undelivered webhook notification to the outbox
+	SetNotification(notification *ledger.Notification) error
+	// DeleteNotification removes the delivered webhook notification from the outbox
+	DeleteNotification(id uint64) error
+	// SetPackage saves the deployed package to the permanent storage
+	SetPackage(pkg *std.MemPackage) error
+	// SetRegistryVersion saves the active registry version to the storage
+	SetRegistryVersion(version string) error
+	// SetGenesisImported marks the genesis as imported
+	SetGenesisImported() error
+	// SetCheckpoint saves the latest height processed
+	// by the given block processor to the storage
+	SetCheckpoint(name string, height uint64) error
 
 	// Commit stores all the provided info on the storage and make
 	// it available for other storage readers
@@ -3,11 +3,16 @@ package types
 import (
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/ledger"
 )
 
-// NewBlockEvent is the event for when new blocks appear
 var (
+	// NewBlockEvent is the event for when new blocks appear
 	NewBlockEvent events.Type = "newHeads"
+
+	// BudgetExceededEvent is the alert event for when a token
+	// registration is queued, because it would exceed the fee budget
+	BudgetExceededEvent events.Type = "budgetExceeded"
 )
 
 type NewBlock struct {
@@ -22,3 +27,19 @@ func (n *NewBlock) GetType() events.Type {
 func (n *NewBlock) GetData() any {
 	return n
 }
+
+type BudgetExceeded struct {
+	Token  *ledger.Token `json:"token"`
+	Budget string        `json:"budget"`
+	Limit  int64         `json:"limit"`
+	Spent  int64         `json:"spent"`
+	Amount int64         `json:"amount"`
+}
+
+func (b *BudgetExceeded) GetType() events.Type {
+	return BudgetExceededEvent
+}
+
+func (b *BudgetExceeded) GetData() any {
+	return b
+}
@@ -0,0 +1,364 @@
+package webhook
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+const (
+	// DefaultPollInterval is the default outbox poll interval
+	DefaultPollInterval = time.Second
+
+	// DefaultMaxAttempts is the default number of delivery
+	// attempts, after which the notification is dropped
+	DefaultMaxAttempts = 10
+
+	// DefaultMinBackoff is the default delay of the first retry
+	DefaultMinBackoff = 5 * time.Second
+
+	// DefaultMaxBackoff is the default maximum delay between retries
+	DefaultMaxBackoff = 30 * time.Minute
+)
+
+// Outbox is the persistent notification outbox storage
+type Outbox interface {
+	// GetNotifications fetches all the undelivered
+	// webhook notifications, ordered by ID
+	GetNotifications() ([]*ledger.Notification, error)
+
+	// WriteBatch provides a batch intended to do a write action that
+	// can be cancelled or committed all at the same time
+	WriteBatch() storage.Batch
+}
+
+// Dispatcher delivers the lifecycle notifications to the webhook
+// endpoints. The notifications are written to the outbox in the batch
+// that saves the lifecycle change, and delivered once it is committed.
+// Failed deliveries are retried with an exponential backoff, and the
+// notifications of an endpoint are delivered in order
+type Dispatcher struct {
+	outbox    Outbox
+	client    *Client
+	endpoints []*Endpoint
+
+	pollInterval time.Duration
+	maxAttempts  int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+
+	lastID uint64     // the latest notification ID
+	mux    sync.Mutex // guards the latest notification ID
+}
+
+// NewDispatcher creates a new lifecycle notification dispatcher
+func NewDispatcher(
+	outbox Outbox,
+	client *Client,
+	endpoints []*Endpoint,
+	opts ...DispatcherOption,
+) *Dispatcher {
+	d := &Dispatcher{
+		outbox:       outbox,
+		client:       client,
+		endpoints:    endpoints,
+		pollInterval: DefaultPollInterval,
+		maxAttempts:  DefaultMaxAttempts,
+		minBackoff:   DefaultMinBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Enqueue writes the lifecycle notification to the outbox, in the batch,
+// once for every endpoint subscribed to its event, or only for its endpoint,
+// if it is set. It is only delivered once the batch is committed
+func (d *Dispatcher) Enqueue(wb storage.Batch, notification *ledger.Notification) error {
+	endpoints := d.endpoints
+
+	if notification.Endpoint != "" {
+		endpoint := d.endpoint(notification.Endpoint)
+		if endpoint == nil {
+			return fmt.Errorf("%w, %q", errUnknownEndpoint, notification.Endpoint)
+		}
+
+		endpoints = []*Endpoint{endpoint}
+	}
+
+	id := eventID(notification)
+
+	for _, endpoint := range endpoints {
+		if notification.Endpoint == "" && !endpoint.subscribed(notification.Event) {
+			continue
+		}
+
+		queued := *notification
+
+		queued.ID = d.nextID()
+		queued.EventID = id
+		queued.Endpoint = endpoint.URL
+		queued.NextAttempt = notification.Time
+
+		// The signed tx of the pending registration is never notified
+		if notification.Token != nil {
+			token := *notification.Token
+			token.PendingTx = nil
+
+			queued.Token = &token
+		}
+
+		if err := wb.SetNotification(&queued); err != nil {
+			return fmt.Errorf("unable to enqueue notification, %w", err)
+		}
+	}
+
+	return nil
+}
+
+// eventID returns the ID of the notified event, derived from the event
+// and its subject, and not from the time it was detected. Every indexer
+// instance derives the same ID, so the receivers can drop the duplicates,
+// ex. the detections delivered again by a promoted standby instance
+func eventID(notification *ledger.Notification) string {
+	fields := []string{string(notification.Event)}
+
+	if token := notification.Token; token != nil {
+		fields = append(
+			fields,
+			token.PkgPath,
+			token.Key,
+			token.DeployTxHash,
+			token.Version,
+			token.RegisterTxHash,
+			string(token.Status),
+		)
+	}
+
+	if balance := notification.Balance; balance != nil {
+		fields = append(fields, balance.Signer, balance.Denom, strconv.FormatInt(balance.Amount, 10))
+	}
+
+	if match := notification.Match; match != nil {
+		fields = append(fields, match.Rule, match.Action, match.PkgPath, match.TxHash)
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+
+	return hex.EncodeToString(hash[:16])
+}
+
+// nextID returns the next notification ID. The IDs are based on the
+// current time, so they keep increasing across restarts
+func (d *Dispatcher) nextID() uint64 {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	id := uint64(time.Now().UnixNano())
+	if id <= d.lastID {
+		id = d.lastID + 1
+	}
+
+	d.lastID = id
+
+	return id
+}
+
+// Serve delivers the outbox notifications, until the context is cancelled.
+// The undelivered notifications are kept in the outbox for the next start
+func (d *Dispatcher) Serve(ctx context.Context) error {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	d.dispatch(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.client.logger.Info("Webhook dispatcher shut down")
+
+			return nil
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch delivers the due outbox notifications, in ID order.
+// Once a notification of an endpoint is not delivered, the rest
+// of its notifications wait for the next run
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	notifications, err := d.outbox.GetNotifications()
+	if err != nil {
+		d.client.logger.Error("unable to fetch webhook notifications", zap.Error(err))
+
+		return
+	}
+
+	var (
+		now     = time.Now()
+		blocked = make(map[string]struct{})
+	)
+
+	for _, notification := range notifications {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if _, ok := blocked[notification.Endpoint]; ok {
+			continue
+		}
+
+		endpoint := d.endpoint(notification.Endpoint)
+		if endpoint == nil {
+			// The endpoint was removed from the configuration
+			d.client.logger.Warn(
+				"dropping webhook notification of an unknown endpoint",
+				zap.String("url", notification.Endpoint),
+				zap.Uint64("id", notification.ID),
+			)
+
+			d.delete(notification)
+
+			continue
+		}
+
+		if notification.NextAttempt.After(now) {
+			blocked[notification.Endpoint] = struct{}{}
+
+			continue
+		}
+
+		if err := d.deliver(endpoint, notification); err != nil {
+			blocked[notification.Endpoint] = struct{}{}
+
+			d.retry(notification, err)
+
+			continue
+		}
+
+		d.delete(notification)
+	}
+}
+
+// endpoint returns the configured endpoint with the URL, if any
+func (d *Dispatcher) endpoint(url string) *Endpoint {
+	for _, endpoint := range d.endpoints {
+		if endpoint.URL == url {
+			return endpoint
+		}
+	}
+
+	return nil
+}
+
+// deliver posts the signed notification payload to the endpoint
+func (d *Dispatcher) deliver(endpoint *Endpoint, notification *ledger.Notification) error {
+	body, err := json.Marshal(newPayload(notification))
+	if err != nil {
+		return fmt.Errorf("unable to encode payload, %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	header := make(http.Header)
+
+	header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderID, notification.EventID)
+	header.Set(HeaderEvent, string(notification.Event))
+
+	return d.client.post(endpoint.URL, body, header)
+}
+
+// retry schedules the next delivery attempt of the failed notification,
+// or drops it once it runs out of attempts
+func (d *Dispatcher) retry(notification *ledger.Notification, deliveryErr error) {
+	notification.Attempts++
+	notification.LastError = deliveryErr.Error()
+
+	if notification.Attempts >= d.maxAttempts {
+		d.client.logger.Error(
+			"dropping undelivered webhook notification",
+			zap.String("url", notification.Endpoint),
+			zap.Uint64("id", notification.ID),
+			zap.String("event", string(notification.Event)),
+			zap.Int("attempts", notification.Attempts),
+			zap.Error(deliveryErr),
+		)
+
+		d.delete(notification)
+
+		return
+	}
+
+	notification.NextAttempt = time.Now().Add(d.backoff(notification.Attempts))
+
+	d.client.logger.Warn(
+		"unable to deliver webhook notification, retrying",
+		zap.String("url", notification.Endpoint),
+		zap.Uint64("id", notification.ID),
+		zap.Int("attempts", notification.Attempts),
+		zap.Time("nextAttempt", notification.NextAttempt),
+		zap.Error(deliveryErr),
+	)
+
+	wb := d.outbox.WriteBatch()
+
+	if err := wb.SetNotification(notification); err != nil {
+		d.client.logger.Error("unable to save webhook notification", zap.Uint64("id", notification.ID), zap.Error(err))
+
+		_ = wb.Rollback()
+
+		return
+	}
+
+	if err := wb.Commit(); err != nil {
+		d.client.logger.Error("unable to commit webhook notification", zap.Uint64("id", notification.ID), zap.Error(err))
+	}
+}
+
+// backoff returns the delay before the next delivery attempt,
+// doubled on every failed attempt, up to the maximum delay
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.minBackoff
+
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.maxBackoff)
+}
+
+// delete removes the delivered (or dropped) notification from the outbox
+func (d *Dispatcher) delete(notification *ledger.Notification) {
+	wb := d.outbox.WriteBatch()
+
+	if err := wb.DeleteNotification(notification.ID); err != nil {
+		d.client.logger.Error("unable to delete webhook notification", zap.Uint64("id", notification.ID), zap.Error(err))
+
+		_ = wb.Rollback()
+
+		return
+	}
+
+	if err := wb.Commit(); err != nil {
+		d.client.logger.Error("unable to commit webhook notification", zap.Uint64("id", notification.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,522 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+const testSecret = "secret"
+
+// delivery is a notification received by the test receiver
+type delivery struct {
+	header  http.Header
+	payload *Payload
+}
+
+// receiver is the test webhook receiver,
+// that verifies the notification signatures
+type receiver struct {
+	t *testing.T
+
+	server *httptest.Server
+	status int // the response status, OK by default
+
+	deliveries []*delivery
+	mux        sync.Mutex
+}
+
+func newReceiver(t *testing.T) *receiver {
+	t.Helper()
+
+	r := &receiver{
+		t:      t,
+		status: http.StatusOK,
+	}
+
+	r.server = httptest.NewServer(http.HandlerFunc(r.handle))
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func (r *receiver) handle(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if !assert.NoError(r.t, err) {
+		return
+	}
+
+	// Make sure the notification is signed
+	assert.True(
+		r.t,
+		Verify(testSecret, req.Header.Get(HeaderTimestamp), body, req.Header.Get(HeaderSignature)),
+	)
+
+	var payload Payload
+
+	if !assert.NoError(r.t, json.Unmarshal(body, &payload)) {
+		return
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.deliveries = append(r.deliveries, &delivery{
+		header:  req.Header,
+		payload: &payload,
+	})
+
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.status = status
+}
+
+func (r *receiver) received() []*delivery {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return append([]*delivery{}, r.deliveries...)
+}
+
+func (r *receiver) endpoint(events ...ledger.Event) *Endpoint {
+	return &Endpoint{
+		URL:    r.server.URL,
+		Secret: testSecret,
+		Events: events,
+	}
+}
+
+// newTestStorage opens the outbox storage in the directory
+func newTestStorage(t *testing.T, dir string) *storage.Pebble {
+	t.Helper()
+
+	s, err := storage.NewPebble(dir)
+	require.NoError(t, err)
+
+	return s
+}
+
+// enqueue commits the lifecycle notification of the token to the outbox
+func enqueue(t *testing.T, s *storage.Pebble, d *Dispatcher, event ledger.Event, token *ledger.Token) {
+	t.Helper()
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, d.Enqueue(wb, &ledger.Notification{
+		Event: event,
+		Time:  time.Now(),
+		Token: token,
+	}))
+
+	require.NoError(t, wb.Commit())
+}
+
+// outbox returns the undelivered notifications
+func outbox(t *testing.T, s *storage.Pebble) []*ledger.Notification {
+	t.Helper()
+
+	notifications, err := s.GetNotifications()
+	require.NoError(t, err)
+
+	return notifications
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r = newReceiver(t)
+		s = newTestStorage(t, t.TempDir())
+
+		token = &ledger.Token{
+			PkgPath:        "gno.land/r/demo/foo",
+			Name:           "Foo",
+			Symbol:         "FOO",
+			Decimals:       6,
+			Deployer:       "g1alice",
+			Height:         10,
+			Status:         ledger.StatusRegistered,
+			DeployTxHash:   "deploy hash",
+			RegisterTxHash: "register hash",
+			PendingTx:      &ledger.PendingTx{Hash: "register hash", Raw: []byte("signed tx")},
+		}
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	d := NewDispatcher(s, New(), []*Endpoint{r.endpoint()}, WithPollInterval(10*time.Millisecond))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- d.Serve(ctx)
+	}()
+
+	enqueue(t, s, d, ledger.EventConfirmed, token)
+
+	// Make sure the notification was delivered, and removed from the outbox
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 1 && len(outbox(t, s)) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancelFn()
+	require.NoError(t, <-served)
+
+	received := r.received()[0]
+
+	assert.Equal(t, string(ledger.EventConfirmed), received.header.Get(HeaderEvent))
+	assert.NotEmpty(t, received.payload.ID)
+	assert.Equal(t, received.payload.ID, received.header.Get(HeaderID))
+
+	// Make sure the payload has the token metadata and tx hashes
+	assert.Equal(t, ledger.EventConfirmed, received.payload.Event)
+	assert.Equal(t, &TokenPayload{
+		PkgPath:        token.PkgPath,
+		Kind:           ledger.KindGRC20,
+		Name:           token.Name,
+		Symbol:         token.Symbol,
+		Decimals:       token.Decimals,
+		Deployer:       token.Deployer,
+		Height:         token.Height,
+		Status:         token.Status,
+		DeployTxHash:   token.DeployTxHash,
+		RegisterTxHash: token.RegisterTxHash,
+	}, received.payload.Token)
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r = newReceiver(t)
+		s = newTestStorage(t, t.TempDir())
+
+		ctx = context.Background()
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	d := NewDispatcher(s, New(), []*Endpoint{r.endpoint()}, WithBackoff(0, 0))
+
+	enqueue(t, s, d, ledger.EventSubmitted, &ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+	enqueue(t, s, d, ledger.EventConfirmed, &ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+
+	r.setStatus(http.StatusInternalServerError)
+
+	d.dispatch(ctx)
+
+	// Make sure the failed notification was kept, and the
+	// next notification of the endpoint was held back
+	require.Len(t, r.received(), 1)
+
+	notifications := outbox(t, s)
+	require.Len(t, notifications, 2)
+
+	assert.Equal(t, 1, notifications[0].Attempts)
+	assert.Contains(t, notifications[0].LastError, "500")
+	assert.Equal(t, 0, notifications[1].Attempts)
+
+	// Make sure both notifications are delivered in order, once the receiver recovers
+	r.setStatus(http.StatusOK)
+
+	d.dispatch(ctx)
+
+	received := r.received()
+	require.Len(t, received, 3)
+
+	assert.Equal(t, received[0].payload.ID, received[1].payload.ID)
+	assert.Equal(t, ledger.EventSubmitted, received[1].payload.Event)
+	assert.Equal(t, ledger.EventConfirmed, received[2].payload.Event)
+
+	assert.Empty(t, outbox(t, s))
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r = newReceiver(t)
+		s = newTestStorage(t, t.TempDir())
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	r.setStatus(http.StatusBadGateway)
+
+	d := NewDispatcher(s, New(), []*Endpoint{r.endpoint()}, WithBackoff(time.Hour, 2*time.Hour))
+
+	enqueue(t, s, d, ledger.EventFailed, &ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+
+	d.dispatch(context.Background())
+	d.dispatch(context.Background())
+
+	// Make sure the notification is not retried before its backoff
+	assert.Len(t, r.received(), 1)
+
+	notifications := outbox(t, s)
+	require.Len(t, notifications, 1)
+
+	assert.WithinDuration(t, time.Now().Add(time.Hour), notifications[0].NextAttempt, time.Minute)
+
+	// Make sure the backoff is doubled, up to the maximum delay
+	assert.Equal(t, time.Hour, d.backoff(1))
+	assert.Equal(t, 2*time.Hour, d.backoff(2))
+	assert.Equal(t, 2*time.Hour, d.backoff(5))
+}
+
+func TestDispatcher_MaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r = newReceiver(t)
+		s = newTestStorage(t, t.TempDir())
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	r.setStatus(http.StatusInternalServerError)
+
+	d := NewDispatcher(s, New(), []*Endpoint{r.endpoint()}, WithBackoff(0, 0), WithMaxAttempts(2))
+
+	enqueue(t, s, d, ledger.EventFailed, &ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+
+	d.dispatch(context.Background())
+	d.dispatch(context.Background())
+
+	// Make sure the notification was dropped after the last attempt
+	assert.Len(t, r.received(), 2)
+	assert.Empty(t, outbox(t, s))
+}
+
+func TestDispatcher_Restart(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r   = newReceiver(t)
+		dir = t.TempDir()
+
+		endpoints = []*Endpoint{r.endpoint()}
+	)
+
+	// Enqueue the notification, without delivering it
+	s := newTestStorage(t, dir)
+
+	enqueue(t, s, NewDispatcher(s, New(), endpoints), ledger.EventDetected, &ledger.Token{
+		PkgPath:      "gno.land/r/demo/foo",
+		DeployTxHash: "deploy hash",
+	})
+
+	require.NoError(t, s.Close())
+
+	// Make sure the notification is delivered after the restart
+	s = newTestStorage(t, dir)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	NewDispatcher(s, New(), endpoints).dispatch(context.Background())
+
+	received := r.received()
+	require.Len(t, received, 1)
+
+	assert.Equal(t, ledger.EventDetected, received[0].payload.Event)
+	assert.Equal(t, "deploy hash", received[0].payload.Token.DeployTxHash)
+
+	assert.Empty(t, outbox(t, s))
+}
+
+func TestDispatcher_Events(t *testing.T) {
+	t.Parallel()
+
+	var (
+		all       = newReceiver(t)
+		confirmed = newReceiver(t)
+
+		s = newTestStorage(t, t.TempDir())
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	d := NewDispatcher(s, New(), []*Endpoint{
+		all.endpoint(),
+		confirmed.endpoint(ledger.EventConfirmed),
+	})
+
+	enqueue(t, s, d, ledger.EventSubmitted, &ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+	enqueue(t, s, d, ledger.EventConfirmed, &ledger.Token{PkgPath: "gno.land/r/demo/foo"})
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, d.Enqueue(wb, &ledger.Notification{
+		Event: ledger.EventLowBalance,
+		Time:  time.Now(),
+		Balance: &ledger.Balance{
+			Signer:    "g1signer",
+			Denom:     "ugnot",
+			Amount:    10,
+			Threshold: 100,
+		},
+	}))
+	require.NoError(t, wb.Commit())
+
+	d.dispatch(context.Background())
+
+	// Make sure every endpoint only received its subscribed events
+	received := all.received()
+	require.Len(t, received, 3)
+
+	assert.Equal(t, ledger.EventLowBalance, received[2].payload.Event)
+	assert.Equal(t, int64(10), received[2].payload.Balance.Amount)
+	assert.Nil(t, received[2].payload.Token)
+
+	received = confirmed.received()
+	require.Len(t, received, 1)
+
+	assert.Equal(t, ledger.EventConfirmed, received[0].payload.Event)
+}
+
+func TestDispatcher_UnknownEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r = newReceiver(t)
+		s = newTestStorage(t, t.TempDir())
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	enqueue(t, s, NewDispatcher(s, New(), []*Endpoint{r.endpoint()}), ledger.EventDetected, &ledger.Token{})
+
+	// Make sure the notifications of the removed endpoints are dropped
+	NewDispatcher(s, New(), nil).dispatch(context.Background())
+
+	assert.Empty(t, r.received())
+	assert.Empty(t, outbox(t, s))
+}
+
+func TestDispatcher_EventID(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r = newReceiver(t)
+
+		endpoints = []*Endpoint{r.endpoint()}
+		token     = &ledger.Token{
+			PkgPath:      "gno.land/r/demo/foo",
+			DeployTxHash: "deploy hash",
+		}
+	)
+
+	// Enqueue the same detection on two indexer instances
+	for range 2 {
+		s := newTestStorage(t, t.TempDir())
+
+		d := NewDispatcher(s, New(), endpoints)
+
+		enqueue(t, s, d, ledger.EventDetected, token)
+		enqueue(t, s, d, ledger.EventSubmitted, token)
+
+		d.dispatch(context.Background())
+
+		require.NoError(t, s.Close())
+	}
+
+	received := r.received()
+	require.Len(t, received, 4)
+
+	// Make sure the same event has the same ID on every instance,
+	// and the events of the token have different IDs
+	assert.Equal(t, received[0].payload.ID, received[2].payload.ID)
+	assert.Equal(t, received[1].payload.ID, received[3].payload.ID)
+	assert.NotEqual(t, received[0].payload.ID, received[1].payload.ID)
+}
+
+func TestDispatcher_Endpoint(t *testing.T) {
+	t.Parallel()
+
+	var (
+		matched = newReceiver(t)
+		other   = newReceiver(t)
+
+		s = newTestStorage(t, t.TempDir())
+
+		match = &ledger.RuleMatch{
+			Rule:    "factory",
+			Action:  "notify",
+			PkgPath: "gno.land/r/demo/factory",
+			TxHash:  "tx hash",
+			Height:  10,
+		}
+	)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	d := NewDispatcher(s, New(), []*Endpoint{
+		matched.endpoint(ledger.EventConfirmed),
+		other.endpoint(),
+	})
+
+	wb := s.WriteBatch()
+
+	require.NoError(t, d.Enqueue(wb, &ledger.Notification{
+		Endpoint: matched.server.URL,
+		Event:    ledger.EventRuleMatched,
+		Time:     time.Now(),
+		Match:    match,
+	}))
+
+	// Make sure the notifications of unknown endpoints are rejected
+	assert.ErrorIs(t, d.Enqueue(wb, &ledger.Notification{
+		Endpoint: "http://localhost/unknown",
+		Event:    ledger.EventRuleMatched,
+		Time:     time.Now(),
+		Match:    match,
+	}), errUnknownEndpoint)
+
+	require.NoError(t, wb.Commit())
+
+	d.dispatch(context.Background())
+
+	// Make sure the notification was only delivered to its endpoint
+	received := matched.received()
+	require.Len(t, received, 1)
+
+	assert.Equal(t, ledger.EventRuleMatched, received[0].payload.Event)
+	assert.Equal(t, match, received[0].payload.Match)
+
+	assert.Empty(t, other.received())
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+var (
+	errNoEndpoints     = errors.New("no webhook endpoints configured")
+	errInvalidEndpoint = errors.New("invalid webhook endpoint")
+	errUnknownEvent    = errors.New("unknown webhook event")
+	errUnknownEndpoint = errors.New("unknown webhook endpoint")
+)
+
+// events are the lifecycle events the endpoints can subscribe to
+var events = []ledger.Event{
+	ledger.EventDetected,
+	ledger.EventSubmitted,
+	ledger.EventConfirmed,
+	ledger.EventFailed,
+	ledger.EventNeedsReview,
+	ledger.EventLowBalance,
+}
+
+// Endpoint is the lifecycle webhook endpoint configuration
+type Endpoint struct {
+	// URL is the endpoint the notifications are posted to
+	URL string `json:"url"`
+
+	// Secret is the key the notifications are HMAC-signed with
+	Secret string `json:"secret"`
+
+	// Events are the notified lifecycle events,
+	// all of them if empty
+	Events []ledger.Event `json:"events,omitempty"`
+}
+
+// subscribed checks if the endpoint is notified of the event
+func (e *Endpoint) subscribed(event ledger.Event) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, event)
+}
+
+// LoadEndpoints loads the lifecycle webhook
+// endpoints from the given JSON config file
+func LoadEndpoints(path string) ([]*Endpoint, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read webhooks file, %w", err)
+	}
+
+	var endpoints []*Endpoint
+
+	if err := json.Unmarshal(raw, &endpoints); err != nil {
+		return nil, fmt.Errorf("unable to parse webhooks file, %w", err)
+	}
+
+	if len(endpoints) == 0 {
+		return nil, errNoEndpoints
+	}
+
+	urls := make(map[string]struct{}, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		if err := endpoint.validate(); err != nil {
+			return nil, err
+		}
+
+		// The notifications are kept in the outbox by endpoint URL
+		if _, exists := urls[endpoint.URL]; exists {
+			return nil, fmt.Errorf("%w, duplicate URL %q", errInvalidEndpoint, endpoint.URL)
+		}
+
+		urls[endpoint.URL] = struct{}{}
+	}
+
+	return endpoints, nil
+}
+
+// validate validates the endpoint configuration
+func (e *Endpoint) validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w, URL %q", errInvalidEndpoint, e.URL)
+	}
+
+	if e.Secret == "" {
+		return fmt.Errorf("%w, no secret for %q", errInvalidEndpoint, e.URL)
+	}
+
+	for _, event := range e.Events {
+		if !slices.Contains(events, event) {
+			return fmt.Errorf("%w %q, endpoint %q", errUnknownEvent, event, e.URL)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+func TestLoadEndpoints(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name        string
+		config      string
+		expectedErr error
+	}{
+		{
+			"valid endpoints",
+			`[{"url": "https://a.example", "secret": "a"}, {"url": "http://b.example/hook", "secret": "b", "events": ["registration.confirmed"]}]`,
+			nil,
+		},
+		{
+			"no endpoints",
+			`[]`,
+			errNoEndpoints,
+		},
+		{
+			"invalid URL",
+			`[{"url": "a.example", "secret": "a"}]`,
+			errInvalidEndpoint,
+		},
+		{
+			"no secret",
+			`[{"url": "https://a.example"}]`,
+			errInvalidEndpoint,
+		},
+		{
+			"duplicate URL",
+			`[{"url": "https://a.example", "secret": "a"}, {"url": "https://a.example", "secret": "b"}]`,
+			errInvalidEndpoint,
+		},
+		{
+			"unknown event",
+			`[{"url": "https://a.example", "secret": "a", "events": ["token.deleted"]}]`,
+			errUnknownEvent,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "webhooks.json")
+			require.NoError(t, os.WriteFile(path, []byte(testCase.config), 0o600))
+
+			endpoints, err := LoadEndpoints(path)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, endpoints, 2)
+
+			assert.True(t, endpoints[0].subscribed(ledger.EventDetected))
+			assert.False(t, endpoints[1].subscribed(ledger.EventDetected))
+			assert.True(t, endpoints[1].subscribed(ledger.EventConfirmed))
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body      = []byte(`{"id": 1}`)
+		signature = Sign("secret", "100", body)
+	)
+
+	assert.True(t, Verify("secret", "100", body, signature))
+
+	// Make sure the signature is bound to the secret, timestamp and body
+	assert.False(t, Verify("other", "100", body, signature))
+	assert.False(t, Verify("secret", "101", body, signature))
+	assert.False(t, Verify("secret", "100", []byte(`{"id": 2}`), signature))
+	assert.False(t, Verify("secret", "100", body, ""))
+}
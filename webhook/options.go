@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(c *Client)
+
+// WithLogger sets the logger to be used
+// with the webhook client
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithTimeout sets the webhook request timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.client.Timeout = timeout
+	}
+}
+
+type DispatcherOption func(d *Dispatcher)
+
+// WithPollInterval sets the interval at which
+// the dispatcher delivers the outbox notifications
+func WithPollInterval(interval time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.pollInterval = interval
+	}
+}
+
+// WithMaxAttempts sets the number of delivery attempts,
+// after which the notification is dropped
+func WithMaxAttempts(attempts int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxAttempts = attempts
+	}
+}
+
+// WithBackoff sets the delay of the first retry, doubled
+// on every failed attempt, up to the maximum delay
+func WithBackoff(minDelay, maxDelay time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.minBackoff = minDelay
+		d.maxBackoff = maxDelay
+	}
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/ledger"
+)
+
+// Payload is the lifecycle notification payload
+type Payload struct {
+	ID    string       `json:"id"` // the event ID, the same as the ID header
+	Event ledger.Event `json:"event"`
+	Time  time.Time    `json:"time"`
+
+	Token   *TokenPayload     `json:"token,omitempty"`
+	Balance *ledger.Balance   `json:"balance,omitempty"`
+	Match   *ledger.RuleMatch `json:"match,omitempty"`
+}
+
+// TokenPayload is the token metadata of the lifecycle notification
+type TokenPayload struct {
+	PkgPath  string      `json:"pkgPath"`
+	Kind     ledger.Kind `json:"kind"`
+	Name     string      `json:"name"`
+	Symbol   string      `json:"symbol"`
+	Decimals int         `json:"decimals"`
+	Deployer string      `json:"deployer"`
+	Height   int64       `json:"height"`
+
+	Status ledger.Status `json:"status,omitempty"`
+	Error  string        `json:"error,omitempty"`
+
+	DeployTxHash   string `json:"deployTxHash"`
+	RegisterTxHash string `json:"registerTxHash,omitempty"`
+}
+
+// newPayload creates the payload of the lifecycle notification
+func newPayload(notification *ledger.Notification) *Payload {
+	payload := &Payload{
+		ID:      notification.EventID,
+		Event:   notification.Event,
+		Time:    notification.Time,
+		Balance: notification.Balance,
+		Match:   notification.Match,
+	}
+
+	if token := notification.Token; token != nil {
+		kind := token.Kind
+		if kind == "" {
+			kind = ledger.KindGRC20
+		}
+
+		payload.Token = &TokenPayload{
+			PkgPath:        token.PkgPath,
+			Kind:           kind,
+			Name:           token.Name,
+			Symbol:         token.Symbol,
+			Decimals:       token.Decimals,
+			Deployer:       token.Deployer,
+			Height:         token.Height,
+			Status:         token.Status,
+			Error:          token.Error,
+			DeployTxHash:   token.DeployTxHash,
+			RegisterTxHash: token.RegisterTxHash,
+		}
+	}
+
+	return payload
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// HeaderSignature is the header of the notification signature,
+	// in the "sha256=<hex>" format
+	HeaderSignature = "X-Webhook-Signature"
+
+	// HeaderTimestamp is the header of the notification
+	// signing time, in Unix seconds
+	HeaderTimestamp = "X-Webhook-Timestamp"
+
+	// HeaderID is the header of the notification event ID, the same
+	// for every delivery attempt and every indexer instance,
+	// so receivers can deduplicate
+	HeaderID = "X-Webhook-Id"
+
+	// HeaderEvent is the header of the notified lifecycle event
+	HeaderEvent = "X-Webhook-Event"
+
+	// signaturePrefix is the signature algorithm prefix
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the HMAC-SHA256 signature of the notification body,
+// over "<timestamp>.<body>", so a signed body is never replayed
+// with a different timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the notification body,
+// in constant time
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	expected := Sign(secret, timestamp, body)
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}